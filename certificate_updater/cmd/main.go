@@ -5,12 +5,14 @@ import (
 	"time"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
 	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
 	"github.com/tucowsinc/tdp-workers-go/certificate_updater/handlers"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lifecycle"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
@@ -50,7 +52,6 @@ func main() {
 			types.LogFieldKeys.Error: err,
 		})
 	}
-	defer messageBusServer.Finalize()
 
 	db, err := database.New(cfg.PostgresPoolConfig(), cfg.GetDBLogLevel())
 	if err != nil {
@@ -60,13 +61,19 @@ func main() {
 	}
 	defer db.Close()
 
-	service := handlers.NewWorkerService(messageBusServer, db, tracer)
+	// recover handler panics, failing the job of the message
+	recoverer := jobhandler.NewRecoverer(db, cfg.HandlerPanicAck)
+
+	// drain in-flight handlers on shutdown
+	lc := lifecycle.New(cfg.GetShutdownTimeout())
+
+	service := handlers.NewWorkerService(lc.Bus(recoverer.Bus(messageBusServer)), db, tracer)
 	service.RegisterHandlers()
 
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range service.HealthChecks(cfg) {
+		for _, check := range append(service.HealthChecks(cfg), lc.HealthCheck()) {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -92,10 +99,11 @@ func main() {
 		Prefetch: &cfg.RmqPrefetchCount,
 	}
 
-	if err = messageBusServer.Consume(queues, consumerOptions); err != nil {
-		log.Fatal(types.LogMessages.ConsumingQueuesFailed, log.Fields{
+	if err = lc.Run(func() error { return messageBusServer.Consume(queues, consumerOptions) }, nil, func() { messageBusServer.Finalize() }); err != nil {
+		log.Error(types.LogMessages.ConsumingQueuesFailed, log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
+		return
 	}
 
 	log.Info(types.LogMessages.WorkerTerminated)
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	"google.golang.org/protobuf/proto"
+
+	crons "github.com/tucowsinc/tdp-workers-go/crons/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+)
+
+type cronRun struct {
+	CronType string        `json:"cron_type"`
+	DryRun   bool          `json:"dry_run"`
+	Skipped  []skippedSend `json:"skipped_messages,omitempty"`
+}
+
+type skippedSend struct {
+	Queue   string `json:"queue"`
+	Message string `json:"message"`
+}
+
+// dryRunBus records messages sent in dry-run mode instead of publishing them,
+// registry calls are still made since crons use them to query registry state
+type dryRunBus struct {
+	messagebus.MessageBus
+
+	mu      sync.Mutex
+	skipped []skippedSend
+}
+
+func (b *dryRunBus) Send(_ context.Context, queue string, msg proto.Message, _ map[string]any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.skipped = append(b.skipped, skippedSend{Queue: queue, Message: string(proto.MessageName(msg))})
+
+	return nil
+}
+
+// cronCommand runs a single cron once, in dry-run its database changes are rolled back
+func cronCommand(ctx context.Context, cfg config.Config, out output, args []string) error {
+	cmd, args := subcommand(args)
+	if cmd != "run" {
+		return fmt.Errorf("unknown cron command %q", cmd)
+	}
+
+	fs := flag.NewFlagSet("cron run", flag.ExitOnError)
+	cronType := fs.String("type", cfg.CronType, "cron type, e.g. "+crons.CronServiceTypeNameEnum.TransferInCron)
+	dryRun := fs.Bool("dry-run", false, "roll back database changes and skip message bus sends")
+	fs.Parse(args)
+
+	if *cronType == "" {
+		return errors.New("-type is required")
+	}
+
+	cfg.CronType = *cronType
+
+	db := openDatabase(cfg)
+	defer db.Close()
+
+	bus := openMessageBus(cfg)
+	defer bus.Finalize()
+
+	result := cronRun{CronType: *cronType, DryRun: *dryRun}
+
+	var err error
+
+	if *dryRun {
+		tx := db.Begin()
+		dryBus := &dryRunBus{MessageBus: bus}
+
+		err = crons.NewCronServiceWith(cfg, tx, dryBus).CronRouter(ctx)
+
+		if rbErr := tx.Rollback(); rbErr != nil && err == nil {
+			err = fmt.Errorf("error rolling back dry-run: %w", rbErr)
+		}
+
+		result.Skipped = dryBus.skipped
+	} else {
+		err = crons.NewCronServiceWith(cfg, db, bus).CronRouter(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error running %s: %w", *cronType, err)
+	}
+
+	return out.print(result, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "%s completed (dry-run: %t)\n", result.CronType, result.DryRun)
+		if len(result.Skipped) > 0 {
+			fmt.Fprintln(w, "\nQUEUE\tSKIPPED MESSAGE")
+			for _, s := range result.Skipped {
+				fmt.Fprintf(w, "%s\t%s\n", s.Queue, s.Message)
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tucowsinc/tdp-workers-go/job_scheduler/admin"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// jobCommand inspects or operates on a job, operations are recorded in the job admin audit trail
+func jobCommand(ctx context.Context, cfg config.Config, out output, args []string) error {
+	cmd, args := subcommand(args)
+
+	fs := flag.NewFlagSet("job "+cmd, flag.ExitOnError)
+	id := fs.String("id", "", "job id")
+	reason := fs.String("reason", "", "reason of the operation")
+	fs.Parse(args)
+
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+
+	db := openDatabase(cfg)
+	defer db.Close()
+
+	switch cmd {
+	case "inspect":
+		details, err := admin.ShowJob(ctx, db, *id, actor())
+		if err != nil {
+			return err
+		}
+		return out.print(details, func(w *tabwriter.Writer) { printJobDetails(w, details) })
+	case "retry":
+		job, err := admin.OperateJob(ctx, db, *id, admin.ActionRequeue, actor(), *reason)
+		if err != nil {
+			return err
+		}
+		return out.print(job.Info, func(w *tabwriter.Writer) { printJobs(w, []model.VJob{*job.Info}) })
+	case "fail":
+		job, err := admin.OperateJob(ctx, db, *id, admin.ActionFail, actor(), *reason)
+		if err != nil {
+			return err
+		}
+		return out.print(job.Info, func(w *tabwriter.Writer) { printJobs(w, []model.VJob{*job.Info}) })
+	default:
+		return fmt.Errorf("unknown job command %q", cmd)
+	}
+}
+
+func printJobDetails(w *tabwriter.Writer, details *admin.JobDetails) {
+	job := details.Job
+
+	fmt.Fprintf(w, "ID:\t%s\n", types.SafeDeref(job.JobID))
+	fmt.Fprintf(w, "PARENT ID:\t%s\n", types.SafeDeref(job.JobParentID))
+	fmt.Fprintf(w, "TYPE:\t%s\n", types.SafeDeref(job.JobTypeName))
+	fmt.Fprintf(w, "STATUS:\t%s\n", types.SafeDeref(job.JobStatusName))
+	fmt.Fprintf(w, "TENANT CUSTOMER ID:\t%s\n", types.SafeDeref(job.TenantCustomerID))
+	fmt.Fprintf(w, "REFERENCE:\t%s %s\n", types.SafeDeref(job.ReferenceTable), types.SafeDeref(job.ReferenceID))
+	fmt.Fprintf(w, "CREATED:\t%s\n", formatTime(job.CreatedDate))
+	fmt.Fprintf(w, "START:\t%s\n", formatTime(job.StartDate))
+	fmt.Fprintf(w, "END:\t%s\n", formatTime(job.EndDate))
+	fmt.Fprintf(w, "RETRY COUNT:\t%d\n", types.SafeDeref(job.RetryCount))
+	fmt.Fprintf(w, "RESULT MESSAGE:\t%s\n", types.SafeDeref(job.ResultMsg))
+	fmt.Fprintf(w, "\nDATA:\n%s\n", indentJSON(job.Data))
+	fmt.Fprintf(w, "\nRESULT DATA:\n%s\n", indentJSON(job.ResultData))
+
+	fmt.Fprintln(w, "\nCHILDREN:")
+	printJobs(w, details.Children)
+
+	fmt.Fprintln(w, "\nAUDIT:")
+	fmt.Fprintln(w, "DATE\tACTOR\tACTION\tSTATUS\tREASON")
+	for _, a := range details.Audit {
+		status := ""
+		if a.PreviousStatus != nil && a.NewStatus != nil {
+			status = *a.PreviousStatus + " -> " + *a.NewStatus
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.CreatedDate.Format(time.RFC3339), a.Actor, a.Action, status, types.SafeDeref(a.Reason))
+	}
+}
+
+func printJobs(w *tabwriter.Writer, jobs []model.VJob) {
+	fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tCREATED\tSTART\tRESULT MESSAGE")
+	for _, j := range jobs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			types.SafeDeref(j.JobID),
+			types.SafeDeref(j.JobTypeName),
+			types.SafeDeref(j.JobStatusName),
+			formatTime(j.CreatedDate),
+			formatTime(j.StartDate),
+			types.SafeDeref(j.ResultMsg),
+		)
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func indentJSON(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"text/tabwriter"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
+)
+
+const usage = `Operator CLI for the TDP workers, settings are read from .env.
+
+Usage:
+  tdpctl [-o table|json] <command> [flags]
+
+Commands:
+  job inspect  -id ID
+  job retry    -id ID [-reason TEXT]
+  job fail     -id ID -reason TEXT
+  poll replay  -id ID [-queue NAME]
+  cron run     -type CRON_TYPE [-dry-run]
+  publish      -type MESSAGE -file PATH (-queue NAME | -sqs [-sqs-queue NAME])
+  maintenance  list|add|end
+
+Run "tdpctl <command> -h" for the flags of a command.
+`
+
+// output prints command results as a table or as JSON
+type output struct {
+	w      io.Writer
+	asJSON bool
+}
+
+// print writes v as indented JSON, or calls table with a tab writer
+func (o output) print(v any, table func(w *tabwriter.Writer)) error {
+	if o.asJSON {
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	table(w)
+
+	return w.Flush()
+}
+
+func main() {
+	format := flag.String("o", "table", "output format, table or json")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if *format != "table" && *format != "json" {
+		fail(fmt.Errorf("invalid output format %q", *format))
+	}
+
+	args := flag.Args()
+	if len(args) < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfiguration(".env")
+	if err != nil {
+		fail(fmt.Errorf("error loading configuration: %w", err))
+	}
+
+	cfg.LogLevel = "error"
+	log.Setup(cfg)
+	defer log.Sync()
+
+	out := output{w: os.Stdout, asJSON: *format == "json"}
+	ctx := context.Background()
+
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "job":
+		err = jobCommand(ctx, cfg, out, args)
+	case "poll":
+		err = pollCommand(ctx, cfg, out, args)
+	case "cron":
+		err = cronCommand(ctx, cfg, out, args)
+	case "publish":
+		err = publish(ctx, cfg, out, args)
+	case "maintenance":
+		err = maintenanceCommand(ctx, cfg, out, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fail(err)
+	}
+}
+
+// subcommand returns the subcommand and its arguments, printing the usage if it is missing
+func subcommand(args []string) (string, []string) {
+	if len(args) < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	return args[0], args[1:]
+}
+
+func openDatabase(cfg config.Config) database.Database {
+	db, err := database.New(cfg.PostgresPoolConfig(), cfg.GetDBLogLevel())
+	if err != nil {
+		fail(fmt.Errorf("error connecting to database: %w", err))
+	}
+
+	return db
+}
+
+func openMessageBus(cfg config.Config) messagebus.MessageBus {
+	bus, err := message_bus.SetupMessageBus(cfg)
+	if err != nil {
+		fail(fmt.Errorf("error connecting to message bus: %w", err))
+	}
+
+	return bus
+}
+
+// actor identifies the operator in the job admin audit trail
+func actor() string {
+	if u, err := user.Current(); err == nil {
+		return "tdpctl:" + u.Username
+	}
+
+	return "tdpctl:" + os.Getenv("USER")
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
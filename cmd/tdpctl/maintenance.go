@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+)
+
+const maintenanceUsage = `Manage registry maintenance windows per accreditation.
+
+While a window is active, jobs for the accreditation are held and released
+in order once the window ends.
+
+Usage:
+  tdpctl maintenance list   [-accreditation NAME] [-all]
+  tdpctl maintenance add    -accreditation NAME -start TIME -end TIME [-reason TEXT]
+  tdpctl maintenance end    -id ID
+
+TIME is RFC3339 (2006-01-02T15:04:05Z07:00) or "now".
+"end" ends an active window now and releases held jobs; a window which has
+not started yet is removed.
+`
+
+func maintenanceCommand(ctx context.Context, cfg config.Config, out output, args []string) error {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, maintenanceUsage)
+		os.Exit(2)
+	}
+
+	db := openDatabase(cfg)
+	defer db.Close()
+
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "list":
+		return maintenanceList(ctx, db, out, args)
+	case "add":
+		return maintenanceAdd(ctx, db, args)
+	case "end":
+		return maintenanceEnd(ctx, db, args)
+	default:
+		fmt.Fprint(os.Stderr, maintenanceUsage)
+		os.Exit(2)
+	}
+
+	return nil
+}
+
+func maintenanceList(ctx context.Context, db database.Database, out output, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	accreditation := fs.String("accreditation", "", "accreditation name")
+	all := fs.Bool("all", false, "include ended windows")
+	fs.Parse(args)
+
+	windows, err := db.GetMaintenances(ctx, *accreditation, *all)
+	if err != nil {
+		return fmt.Errorf("error listing maintenance windows: %w", err)
+	}
+
+	return out.print(windows, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tACCREDITATION ID\tSTART\tEND\tREASON")
+		for _, m := range windows {
+			reason := ""
+			if m.Reason != nil {
+				reason = *m.Reason
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.ID, m.AccreditationID, m.StartDate.Format(time.RFC3339), m.EndDate.Format(time.RFC3339), reason)
+		}
+	})
+}
+
+func maintenanceAdd(ctx context.Context, db database.Database, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	accreditation := fs.String("accreditation", "", "accreditation name")
+	start := fs.String("start", "", "window start time")
+	endTime := fs.String("end", "", "window end time")
+	reason := fs.String("reason", "", "reason of the maintenance")
+	fs.Parse(args)
+
+	if *accreditation == "" || *start == "" || *endTime == "" {
+		return errors.New("-accreditation, -start and -end are required")
+	}
+
+	acc, err := db.GetAccreditationByName(ctx, *accreditation)
+	if err != nil {
+		return fmt.Errorf("error getting accreditation %q: %w", *accreditation, err)
+	}
+
+	m := &model.AccreditationMaintenance{AccreditationID: acc.ID}
+
+	if m.StartDate, err = parseTime(*start); err != nil {
+		return err
+	}
+
+	if m.EndDate, err = parseTime(*endTime); err != nil {
+		return err
+	}
+
+	if !m.EndDate.After(m.StartDate) {
+		return errors.New("-end must be after -start")
+	}
+
+	if *reason != "" {
+		m.Reason = reason
+	}
+
+	if err = db.CreateMaintenance(ctx, m); err != nil {
+		return fmt.Errorf("error creating maintenance window: %w", err)
+	}
+
+	fmt.Println(m.ID)
+
+	return nil
+}
+
+func maintenanceEnd(ctx context.Context, db database.Database, args []string) error {
+	fs := flag.NewFlagSet("end", flag.ExitOnError)
+	id := fs.String("id", "", "maintenance window id")
+	fs.Parse(args)
+
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+
+	m, err := db.GetMaintenanceById(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("error getting maintenance window %q: %w", *id, err)
+	}
+
+	now := time.Now()
+
+	switch {
+	case m.StartDate.After(now):
+		if err = db.DeleteMaintenance(ctx, m.ID); err != nil {
+			return fmt.Errorf("error removing maintenance window: %w", err)
+		}
+		fmt.Println("maintenance window removed")
+	case m.EndDate.After(now):
+		released, err := db.EndMaintenance(ctx, m.ID)
+		if err != nil {
+			return fmt.Errorf("error ending maintenance window: %w", err)
+		}
+		fmt.Printf("maintenance window ended, %d held jobs released\n", len(released))
+	default:
+		fmt.Println("maintenance window already ended")
+	}
+
+	return nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "now" {
+		return time.Now(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+	pollenqueuer "github.com/tucowsinc/tdp-workers-go/poll_enqueuer/handler"
+)
+
+type pollReplay struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Queue string `json:"queue"`
+}
+
+// pollCommand replays a stored poll message to the poll worker queue, converted like the poll enqueuer does
+func pollCommand(ctx context.Context, cfg config.Config, out output, args []string) error {
+	cmd, args := subcommand(args)
+	if cmd != "replay" {
+		return fmt.Errorf("unknown poll command %q", cmd)
+	}
+
+	fs := flag.NewFlagSet("poll replay", flag.ExitOnError)
+	id := fs.String("id", "", "poll message id")
+	queue := fs.String("queue", cfg.RmqQueueName, "poll worker queue")
+	fs.Parse(args)
+
+	if *id == "" || *queue == "" {
+		return errors.New("-id and -queue are required")
+	}
+
+	db := openDatabase(cfg)
+	defer db.Close()
+
+	bus := openMessageBus(cfg)
+	defer bus.Finalize()
+
+	row, err := db.GetPollMessageById(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("error getting poll message %q: %w", *id, err)
+	}
+
+	msg, err := pollenqueuer.NewWorkerService(bus, db).DBPollMessageHandler(row)
+	if err != nil {
+		return fmt.Errorf("error converting poll message %q: %w", *id, err)
+	}
+
+	err = bus.Send(ctx, *queue, msg, nil)
+	if err != nil {
+		return fmt.Errorf("error sending poll message %q: %w", *id, err)
+	}
+
+	err = db.UpdatePollMessageStatus(ctx, row.ID, types.PollMessageStatus.Submitted)
+	if err != nil {
+		return fmt.Errorf("poll message %q sent but its status was not updated: %w", *id, err)
+	}
+
+	result := pollReplay{ID: row.ID, Type: db.GetPollMessageTypeName(row.TypeID), Queue: *queue}
+
+	return out.print(result, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tTYPE\tQUEUE")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.ID, result.Type, result.Queue)
+	})
+}
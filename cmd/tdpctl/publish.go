@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/sqs"
+
+	// register message types which can be published
+	_ "github.com/tucowsinc/tdp-messages-go/message/certbot"
+	_ "github.com/tucowsinc/tdp-messages-go/message/datamanager"
+	_ "github.com/tucowsinc/tdp-messages-go/message/job"
+	_ "github.com/tucowsinc/tdp-messages-go/message/ryinterface"
+	_ "github.com/tucowsinc/tdp-messages-go/message/worker"
+	_ "github.com/tucowsinc/tucows-domainshosting-app/cmd/functions/order/proto"
+)
+
+type publishResult struct {
+	Message     string `json:"message"`
+	Destination string `json:"destination"`
+	MessageID   string `json:"message_id,omitempty"`
+}
+
+// publish sends a proto message read from a JSON file to a message bus queue or to SQS
+func publish(ctx context.Context, cfg config.Config, out output, args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	messageType := fs.String("type", "", "full proto message name (package.Message)")
+	file := fs.String("file", "", "JSON file of the message")
+	queue := fs.String("queue", "", "message bus queue")
+	toSQS := fs.Bool("sqs", false, "publish to SQS instead of the message bus")
+	sqsQueue := fs.String("sqs-queue", cfg.AWSSqsQueueName, "SQS queue name")
+	fs.Parse(args)
+
+	if *messageType == "" || *file == "" {
+		return errors.New("-type and -file are required")
+	}
+
+	if *toSQS == (*queue != "") {
+		return errors.New("one of -queue or -sqs is required")
+	}
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(*messageType))
+	if err != nil {
+		return fmt.Errorf("unknown message type %q: %w", *messageType, err)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", *file, err)
+	}
+
+	msg := mt.New().Interface()
+	if err = protojson.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("error decoding %s as %s: %w", *file, *messageType, err)
+	}
+
+	result := publishResult{Message: *messageType}
+
+	if *toSQS {
+		cfg.AWSSqsQueueName = *sqsQueue
+
+		publisher, err := setupSQSPublisher(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		result.Destination = "sqs:" + *sqsQueue
+		if result.MessageID, err = publisher.Send(msg); err != nil {
+			return fmt.Errorf("error sending message to SQS: %w", err)
+		}
+	} else {
+		bus := openMessageBus(cfg)
+		defer bus.Finalize()
+
+		result.Destination = *queue
+		if err = bus.Send(ctx, *queue, msg, nil); err != nil {
+			return fmt.Errorf("error sending message to %s: %w", *queue, err)
+		}
+	}
+
+	return out.print(result, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "MESSAGE\tDESTINATION\tMESSAGE ID")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Message, result.Destination, result.MessageID)
+	})
+}
+
+func setupSQSPublisher(ctx context.Context, cfg config.Config) (sqs.Publisher, error) {
+	options, err := sqs.NewOptionsBuilder().
+		WithDebugModeEnabled(cfg.IsDebugEnabled()).
+		WithQueueName(cfg.AWSSqsQueueName).
+		WithQueueAccountId(cfg.AWSSqsQueueAccountId).
+		WithSSOProfileName(cfg.AWSSSOProfileName).
+		WithAccessKeyId(cfg.AWSAccessKeyId).
+		WithSecretAccessKey(cfg.AWSSecretAccessKey).
+		WithSessionToken(cfg.AWSSessionToken).
+		WithRegion(cfg.AWSRegion).
+		WithRoles(cfg.AWSRoles).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("error configuring SQS options: %w", err)
+	}
+
+	publisher, err := sqs.NewPublisher(ctx, *options)
+	if err != nil {
+		return nil, fmt.Errorf("error creating SQS publisher: %w", err)
+	}
+
+	return publisher, nil
+}
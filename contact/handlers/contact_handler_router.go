@@ -1,40 +1,18 @@
 package handlers
 
 import (
-	"fmt"
-
-	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
-	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
-	"github.com/tucowsinc/tdp-workers-go/pkg/types"
-	"google.golang.org/protobuf/proto"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
 )
 
-func (service *WorkerService) HandlerRouter(s messagebus.Server, m proto.Message) (err error) {
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := m.(*job.Notification)
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.Message: request.String(),
-	})
-
-	logger.Info("Routing job to appropriate handler")
-
-	switch request.Type {
-	case "provision_contact_create":
-		return service.ContactProvisionHandler(s, m)
-	case "provision_domain_contact_update":
-		return service.ContactUpdateHandler(s, m)
-	case "provision_contact_delete":
-		return service.ContactDeleteHandler(s, m)
-	}
-
-	err = fmt.Errorf("no handlers for type: %s", request.Type)
-
-	logger.Error("No handler found for job type", log.Fields{
-		types.LogFieldKeys.Error: err,
-	})
+// routes builds the route table of the job types handled by the contact worker
+func (service *WorkerService) routes() *jobhandler.Router {
+	return jobhandler.NewRouter().
+		Handle(service.ContactProvisionHandler, "provision_contact_create").
+		Handle(service.ContactUpdateHandler, "provision_domain_contact_update").
+		Handle(service.ContactDeleteHandler, "provision_contact_delete")
+}
 
-	return
+// Routes returns the route table built once by RegisterHandlers
+func (service *WorkerService) Routes() *jobhandler.Router {
+	return service.router
 }
@@ -1,16 +1,14 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -18,102 +16,47 @@ import (
 // ContactUpdateHandler This is a callback handler for the ContactUpdate event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) ContactUpdateHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "ContactUpdateHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Info("Starting ContactUpdateHandler for the job")
+	return jobhandler.NewJobHandler("ContactUpdateHandler", service.db, service.tracer, service.contactUpdate).
+		Use(jobhandler.RegistryGate(func(data *types.ContactUpdateData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.ContactUpdateData) lease.Key {
+			return lease.Contact(data.Handle, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-	data := new(types.ContactUpdateData)
+func (service *WorkerService) contactUpdate(r *jobhandler.JobRequest[types.ContactUpdateData]) (err error) {
+	data := r.Data
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	r.Logger.Info("Starting contact update job processing")
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
+	msg, err := toContactUpdateRequest(*data)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return
+	}
 
-		logger.Info("Starting contact update job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
-
-		err = json.Unmarshal(job.Info.Data, data)
-		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
-
-		msg, err := toContactUpdateRequest(*data)
-		if err != nil {
-			logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobContactProvisionUpdate",
-			"correlation_id": jobId,
-		}
-
-		err = server.MessageBus().Send(ctx, queue, msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobContactProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Contact:              data.Handle,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	err = r.Server.MessageBus().Send(r.Ctx, queue, msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
-
-		return
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Contact:              data.Handle,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
 
 // toContactUpdateRequest converts ContactUpdateData to ryinterface's ContactUpdateRequest
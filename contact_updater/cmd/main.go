@@ -10,11 +10,13 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
 	"github.com/tucowsinc/tdp-workers-go/contact_updater/handlers"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lifecycle"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 )
@@ -54,7 +56,6 @@ func main() {
 			types.LogFieldKeys.Error: err,
 		})
 	}
-	defer messagebusServer.Finalize()
 
 	log.Info(types.LogMessages.MessageBusSetupSuccess)
 
@@ -68,13 +69,19 @@ func main() {
 
 	log.Info(types.LogMessages.DatabaseConnectionSuccess)
 
-	service := handlers.NewWorkerService(messagebusServer, db, tracer)
+	// recover handler panics, failing the job of the message
+	recoverer := jobhandler.NewRecoverer(db, cfg.HandlerPanicAck)
+
+	// drain in-flight handlers on shutdown
+	lc := lifecycle.New(cfg.GetShutdownTimeout())
+
+	service := handlers.NewWorkerService(lc.Bus(recoverer.Bus(messagebusServer)), db, tracer)
 	service.RegisterHandlers()
 
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range service.HealthChecks(cfg) {
+		for _, check := range append(service.HealthChecks(cfg), lc.HealthCheck()) {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -100,11 +107,12 @@ func main() {
 		Prefetch: &cfg.RmqPrefetchCount,
 	}
 
-	err = messagebusServer.Consume(queues, consumerOptions) // block until the messagebus quits
+	err = lc.Run(func() error { return messagebusServer.Consume(queues, consumerOptions) }, nil, func() { messagebusServer.Finalize() }) // block until the messagebus quits or is drained on shutdown
 	if err != nil {
-		log.Fatal(types.LogMessages.ConsumingQueuesFailed, log.Fields{
+		log.Error(types.LogMessages.ConsumingQueuesFailed, log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
+		return
 	}
 
 	log.Info(types.LogMessages.WorkerTerminated)
@@ -1,15 +1,13 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -17,100 +15,59 @@ import (
 // RyContactProvisionHandler receives the responses from the registry interface
 // and updates the database
 func (service *WorkerService) RyContactProvisionHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyContactProvisionHandler")
-	defer service.tracer.FinishSpan(span)
+	return jobhandler.NewJobHandler("RyContactProvisionHandler", service.db, service.tracer, service.ryContactProvision).
+		ForRegistryResponse().
+		Deduplicate(service.dedup).
+		Handle(server, message)
+}
 
-	correlationId := server.Envelope().CorrelationId
+func (service *WorkerService) ryContactProvision(r *jobhandler.JobRequest[types.ContactData]) (err error) {
+	data := r.Data
+	response := r.Message.(*ryinterface.ContactCreateResponse)
 
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: correlationId,
+	r.Logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
+		types.LogFieldKeys.Response: response.String(),
 	})
 
-	response := message.(*ryinterface.ContactCreateResponse)
+	r.Logger.Info("Starting response processing for contact provision job")
 
-	logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
-		types.LogFieldKeys.Response: response.String(),
-	})
+	registryResponse := response.GetRegistryResponse()
 
-	data := new(types.ContactData)
+	jrd := types.JobResultData{Message: r.Message}
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, correlationId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	outcome := epp_utils.ResolveJobOutcome(r.Ctx, r.Tx, epp_utils.ResultPolicyInput{
+		JobType:           *r.Job.Info.JobTypeName,
+		AccreditationName: data.Accreditation.AccreditationName,
+	}, registryResponse, r.Logger)
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
+	if outcome.IsSuccess() {
+		r.Logger.Info("Contact was successfully created on the registry backend", log.Fields{
+			types.LogFieldKeys.Contact: data.Contact,
 		})
 
-		logger.Info("Starting response processing for contact provision job")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.SetStatus(outcome.Status, &jrd)
+		if err != nil {
 			return
 		}
+	} else {
+		r.Logger.Error("Failed to create contact in the registry", log.Fields{
+			types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
+			types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
+			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
+		})
 
-		err = json.Unmarshal(job.Info.Data, data)
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.FailOrReschedule(joberrors.FromRegistryResponse(registryResponse), &jrd)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+			r.Logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
 			return
 		}
+	}
 
-		registryResponse := response.GetRegistryResponse()
-
-		jrd := types.JobResultData{Message: message}
-
-		if registryResponse.GetIsSuccess() {
-			logger.Info("Contact was successfully created on the registry backend", log.Fields{
-				types.LogFieldKeys.Contact: data.Contact,
-			})
-
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		} else {
-			logger.Error("Failed to create contact in the registry", log.Fields{
-				types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
-				types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
-				types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
-			})
-
-			epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		}
-
-		logger.Info(types.LogMessages.JobProcessingCompleted)
+	r.Logger.Info(types.LogMessages.JobProcessingCompleted)
 
-		return
-	})
+	return
 }
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	tcwire "github.com/tucowsinc/tdp-messages-go/message"
+	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
+	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
+	"github.com/tucowsinc/tdp-shared-go/logger"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	mb "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// RyTimeoutHandler reconciles contact jobs whose registry command timed out
+func (service *WorkerService) RyTimeoutHandler(server messagebus.Server, message proto.Message) (err error) {
+	ctx := server.Context()
+	headers := server.Headers()
+	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyTimeoutHandler")
+	defer service.tracer.FinishSpan(span)
+
+	correlationId := server.Envelope().CorrelationId
+
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.CorrelationID: correlationId,
+	})
+
+	response := message.(*tcwire.ErrorResponse)
+
+	logger.Info("Received timeout error response from RY interface", log.Fields{
+		types.LogFieldKeys.Response: response.GetMessage(),
+	})
+
+	job, err := service.db.GetJobById(ctx, correlationId, true)
+	if err != nil {
+		logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	jobType := *job.Info.JobTypeName
+
+	logger = logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.LogID:   uuid.NewString(),
+		types.LogFieldKeys.JobType: jobType,
+	})
+
+	// a timeout received again, or after the job was already finished, must not reschedule the job
+	if job.StatusID != service.db.GetJobStatusId(types.JobStatus.Processing) &&
+		job.StatusID != service.db.GetJobStatusId(types.JobStatus.Dispatching) {
+		logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
+			types.LogFieldKeys.Status: job.Info.JobStatusName,
+		})
+		return
+	}
+
+	logger.Info("Starting reconciliation process for job")
+
+	err = JobReconcilerRouter(service, ctx, response, job, logger)
+	if err != nil {
+		logger.Error("Reconciliation process failed for job", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+
+		job.ResultMessage = getJobResultMsg(jobType)
+		err = service.db.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
+		if err != nil {
+			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return
+		}
+	}
+
+	logger.Info("Reconciliation process was successful for job")
+
+	return
+}
+
+// JobReconcilerRouter routes the job to the appropriate reconciliation handler
+func JobReconcilerRouter(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	jobType := *job.Info.JobTypeName
+
+	switch jobType {
+	case "provision_contact_create":
+		err = createContact(service, ctx, message, job, logger)
+	case "provision_contact_update":
+		err = updateContact(service, ctx, message, job, logger)
+	case "provision_contact_delete":
+		err = deleteContact(service, ctx, message, job, logger)
+	default:
+		err = fmt.Errorf("unsupported job type: %s", jobType)
+	}
+
+	return
+}
+
+// createContact completes the contact create job if the contact already exists in the registry
+func createContact(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	data := new(types.ContactData)
+	err = json.Unmarshal(job.Info.Data, data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	// contact id sent to the registry is the prefix tdp- and the last 12 of the job id
+	handle := fmt.Sprintf("tdp-%s", job.ID[len(job.ID)-12:])
+
+	contactInfoResp, err := service.getContactInfo(ctx, handle, data.Accreditation.AccreditationName)
+	applied := err == nil &&
+		contactInfoResp.GetRegistryResponse().GetEppCode() == types.EppCode.Success &&
+		contactInfoResp.GetClid() == data.Accreditation.RegistrarID
+
+	return reconcileJob(service, ctx, message, job, applied, contactInfoResp, logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.Contact: handle,
+	}))
+}
+
+// updateContact completes the contact update job if the registry already has the updated contact details
+func updateContact(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	data := new(types.ContactUpdateData)
+	err = json.Unmarshal(job.Info.Data, data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	contactInfoResp, err := service.getContactInfo(ctx, data.Handle, data.Accreditation.AccreditationName)
+	applied := err == nil &&
+		contactInfoResp.GetRegistryResponse().GetEppCode() == types.EppCode.Success &&
+		contactUpdateApplied(data, contactInfoResp)
+
+	return reconcileJob(service, ctx, message, job, applied, contactInfoResp, logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.Contact: data.Handle,
+	}))
+}
+
+// deleteContact completes the contact delete job if the contact no longer exists in the registry
+func deleteContact(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	data := new(types.ContactDeleteData)
+	err = json.Unmarshal(job.Info.Data, data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	contactInfoResp, err := service.getContactInfo(ctx, data.Handle, data.Accreditation.AccreditationName)
+	applied := err == nil && contactInfoResp.GetRegistryResponse().GetEppCode() == types.EppCode.ObjectDoesNotExist
+
+	return reconcileJob(service, ctx, message, job, applied, contactInfoResp, logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.Contact: data.Handle,
+	}))
+}
+
+// contactUpdateApplied tells if the contact info reflects every change of the update
+func contactUpdateApplied(data *types.ContactUpdateData, contactInfo *ryinterface.ContactInfoResponse) bool {
+	contact := data.Contact
+
+	if contact.ContactPostals != nil {
+		postalInfo := contactInfo.GetPostalInfoLoc()
+		if types.SafeDeref(contact.ContactPostals[0].IsInternational) {
+			postalInfo = contactInfo.GetPostalInfoInt()
+		}
+
+		if !contactPostalApplied(contact, postalInfo) {
+			return false
+		}
+	}
+
+	if contact.Email != nil && *contact.Email != contactInfo.GetEmail() {
+		return false
+	}
+
+	if contact.Phone != nil && *contact.Phone != contactInfo.GetVoice() {
+		return false
+	}
+
+	if contact.Fax != nil && *contact.Fax != contactInfo.GetFax() {
+		return false
+	}
+
+	return true
+}
+
+// contactPostalApplied tells if the postal info holds every postal field sent by the update,
+// the name is sent as the first and last name joined the same way as by the contact worker
+func contactPostalApplied(contact types.Contact, postalInfo *commonmessages.ContactPostalInfo) bool {
+	if postalInfo == nil {
+		return false
+	}
+
+	postal := contact.ContactPostals[0]
+	address := postalInfo.GetAddress()
+
+	if postal.FirstName != nil || postal.LastName != nil {
+		name := types.SafeDeref(postal.FirstName) + " " + types.SafeDeref(postal.LastName)
+		if name != postalInfo.GetName() {
+			return false
+		}
+	}
+
+	fields := []struct {
+		sent     *string
+		registry string
+	}{
+		{postal.OrgName, postalInfo.GetOrg()},
+		{postal.Address1, address.GetStreet1()},
+		{postal.Address2, address.GetStreet2()},
+		{postal.Address3, address.GetStreet3()},
+		{postal.City, address.GetCity()},
+		{postal.State, address.GetSp()},
+		{postal.PostalCode, address.GetPc()},
+		{contact.Country, address.GetCc()},
+	}
+
+	for _, field := range fields {
+		if field.sent != nil && *field.sent != field.registry {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reconcileJob completes the job when the registry already applied the command,
+// otherwise the job is rescheduled and failed once out of retries
+func reconcileJob(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, applied bool, contactInfoResp *ryinterface.ContactInfoResponse, logger logger.ILogger) (err error) {
+	db := service.db
+
+	if applied {
+		logger.Info("Contact command already applied in registry")
+
+		jrd := types.JobResultData{Message: contactInfoResp}
+		err = db.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+		if err != nil {
+			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+		}
+
+		return
+	}
+
+	logger.Info("Contact command not applied in registry, retrying")
+
+	jrd := types.JobResultData{Message: message}
+	epp_utils.SetJobErrorFromRegistryErrorResponse(message, job, &jrd)
+	err = joberrors.FailOrReschedule(ctx, db, job, joberrors.Transient(errors.New(*getJobResultMsg(*job.Info.JobTypeName))), &jrd, logger)
+	if err != nil {
+		logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+
+	return
+}
+
+// getJobResultMsg returns the job result message based on the job type
+func getJobResultMsg(jobType string) *string {
+	var msg string
+
+	switch jobType {
+	case "provision_contact_create":
+		msg = "Timeout error occurred while creating contact in registry"
+	case "provision_contact_update":
+		msg = "Timeout error occurred while updating contact in registry"
+	case "provision_contact_delete":
+		msg = "Timeout error occurred while deleting contact in registry"
+	default:
+		msg = "Failed to process job"
+	}
+
+	return &msg
+}
+
+func (service *WorkerService) getContactInfo(ctx context.Context, handle string, accName string) (*ryinterface.ContactInfoResponse, error) {
+	contactInfoMsg := &ryinterface.ContactInfoRequest{Id: handle}
+	response, err := mb.Call(ctx, service.bus, types.GetQueryQueue(accName), contactInfoMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	contactInfoResp, ok := response.(*ryinterface.ContactInfoResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type received for contact info response: %T", response)
+	}
+
+	return contactInfoResp, nil
+}
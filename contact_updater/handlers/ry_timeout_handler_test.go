@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
+	"github.com/tucowsinc/tdp-messages-go/message"
+	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+func TestContactUpdateApplied(t *testing.T) {
+	contactInfo := &ryinterface.ContactInfoResponse{}
+	err := protojson.Unmarshal([]byte(`{
+		"email": "new@example.com",
+		"voice": "+1.5555555555",
+		"postal_info_int": {
+			"name": "John Doe",
+			"org": "Example Inc",
+			"address": {"street1": "1 Main St", "city": "Toronto", "sp": "ON", "pc": "M1M1M1", "cc": "CA"}
+		}
+	}`), contactInfo)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		contact types.Contact
+		applied bool
+	}{
+		{
+			name:    "no changes",
+			applied: true,
+		},
+		{
+			name:    "email and voice applied",
+			contact: types.Contact{Email: types.ToPointer("new@example.com"), Phone: types.ToPointer("+1.5555555555")},
+			applied: true,
+		},
+		{
+			name:    "email not applied",
+			contact: types.Contact{Email: types.ToPointer("old@example.com")},
+		},
+		{
+			name:    "fax not applied",
+			contact: types.Contact{Fax: types.ToPointer("+1.5555555556")},
+		},
+		{
+			name: "postal info applied",
+			contact: types.Contact{
+				Country: types.ToPointer("CA"),
+				ContactPostals: []types.Postal{{
+					FirstName:       types.ToPointer("John"),
+					LastName:        types.ToPointer("Doe"),
+					Address1:        types.ToPointer("1 Main St"),
+					City:            types.ToPointer("Toronto"),
+					IsInternational: types.ToPointer(true),
+				}},
+			},
+			applied: true,
+		},
+		{
+			name: "postal address not applied",
+			contact: types.Contact{ContactPostals: []types.Postal{{
+				City:            types.ToPointer("Montreal"),
+				IsInternational: types.ToPointer(true),
+			}}},
+		},
+		{
+			name: "postal name not applied",
+			contact: types.Contact{ContactPostals: []types.Postal{{
+				FirstName:       types.ToPointer("Jane"),
+				LastName:        types.ToPointer("Doe"),
+				IsInternational: types.ToPointer(true),
+			}}},
+		},
+		{
+			name: "country not applied",
+			contact: types.Contact{
+				Country:        types.ToPointer("US"),
+				ContactPostals: []types.Postal{{IsInternational: types.ToPointer(true)}},
+			},
+		},
+		{
+			name: "local postal info missing",
+			contact: types.Contact{ContactPostals: []types.Postal{{
+				City:            types.ToPointer("Toronto"),
+				IsInternational: types.ToPointer(false),
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &types.ContactUpdateData{Handle: "tdp-contact", Contact: tt.contact}
+			assert.Equal(t, tt.applied, contactUpdateApplied(data, contactInfo))
+		})
+	}
+}
+
+func TestRyTimeoutHandlerUnexpectedStatus(t *testing.T) {
+	cfg := config.Config{LogLevel: "mute"}
+	log.Setup(cfg)
+
+	tracer, _, err := tracing.Setup(context.Background(), cfg)
+	assert.NoError(t, err)
+
+	db := &database.MockDatabase{}
+	s := &mocks.MockMessageBusServer{}
+
+	s.On("Context").Return(context.Background())
+	s.On("Headers").Return(nil)
+	s.On("Envelope").Return(&message.TcWire{CorrelationId: "job-id"})
+
+	job := &model.Job{
+		ID:       "job-id",
+		StatusID: "completed-id",
+		Info: &model.VJob{
+			JobTypeName:   types.ToPointer("provision_contact_update"),
+			JobStatusName: types.ToPointer(types.JobStatus.Completed),
+		},
+	}
+	db.On("GetJobById", mock.Anything, "job-id", true).Return(job, nil)
+	db.On("GetJobStatusId", types.JobStatus.Processing).Return("processing-id")
+	db.On("GetJobStatusId", types.JobStatus.Dispatching).Return("dispatching-id")
+
+	service := NewWorkerService(&mocks.MockMessageBus{}, db, tracer)
+
+	// the completed job is neither reconciled nor rescheduled
+	err = service.RyTimeoutHandler(s, &message.ErrorResponse{Code: message.ErrorResponse_TIMEOUT})
+	assert.NoError(t, err)
+
+	db.AssertExpectations(t)
+	db.AssertNotCalled(t, "RescheduleJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	db.AssertNotCalled(t, "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -42,6 +42,7 @@ func TestDomainPurgeCronTestSuite(t *testing.T) {
 func (suite *DomainPurgeCronTestSuite) SetupSuite() {
 	suite.cfg = config.Config{}
 	suite.db = &database.MockDatabase{}
+	suite.db.On("GetActiveMaintenance", mock.Anything, mock.Anything).Return((*model.AccreditationMaintenance)(nil), database.ErrNotFound)
 	suite.bus = &mocks.MockMessageBus{}
 	suite.service = &CronService{cfg: suite.cfg, db: suite.db, bus: suite.bus}
 	suite.ctx = context.Background()
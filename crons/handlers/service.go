@@ -12,6 +12,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/maintenance"
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -38,11 +39,17 @@ func NewCronService(cfg config.Config) (*CronService, error) {
 		})
 	}
 
+	return NewCronServiceWith(cfg, db, mb), nil
+}
+
+// NewCronServiceWith creates a cron service using the given database and message bus,
+// e.g. a transaction which is rolled back to run a cron without applying its changes
+func NewCronServiceWith(cfg config.Config, db database.Database, bus messagebus.MessageBus) *CronService {
 	return &CronService{
 		cfg: cfg,
 		db:  db,
-		bus: mb,
-	}, nil
+		bus: bus,
+	}
 }
 
 // CronRouter routes the cron service to the appropriate handler based on the service type.
@@ -93,6 +100,11 @@ func (s *CronService) CronRouter(ctx context.Context) (err error) {
 }
 
 func (s *CronService) getDomainInfo(ctx context.Context, domainName string, acc *model.Accreditation) (*rymessages.DomainInfoResponse, error) {
+	// skip while registry is in maintenance, the next cron run picks the item up again
+	if err := maintenance.Check(ctx, s.db, acc.Name); err != nil {
+		return nil, err
+	}
+
 	domainInfoMsg := &rymessages.DomainInfoRequest{Name: domainName}
 	response, err := message_bus.Call(ctx, s.bus, types.GetTransformQueue(acc.Name), domainInfoMsg)
 	if err != nil {
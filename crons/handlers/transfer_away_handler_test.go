@@ -36,6 +36,7 @@ func TestTransferAwayCronTestSuite(t *testing.T) {
 func (suite *TransferAwayCronTestSuite) SetupSuite() {
 	suite.cfg = config.Config{}
 	suite.db = &database.MockDatabase{}
+	suite.db.On("GetActiveMaintenance", mock.Anything, mock.Anything).Return((*model.AccreditationMaintenance)(nil), database.ErrNotFound)
 	suite.bus = &mocks.MockMessageBus{}
 	suite.service = &CronService{cfg: suite.cfg, db: suite.db, bus: suite.bus}
 	suite.ctx = context.Background()
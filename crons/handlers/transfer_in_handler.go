@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -9,6 +10,7 @@ import (
 	"github.com/tucowsinc/tdp-shared-go/logger"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/maintenance"
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -75,6 +77,17 @@ func (s *CronService) processPendingTransfer(ctx context.Context, tn *model.Prov
 		return fmt.Errorf("error getting accreditation by id: %w", err)
 	}
 
+	// skip while registry is in maintenance, the next cron run picks the request up again
+	if err = maintenance.Check(ctx, s.db, acc.Name); err != nil {
+		if errors.Is(err, maintenance.ErrInMaintenance) {
+			logger.Info("Skipping transfer query request", log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return nil
+		}
+		return err
+	}
+
 	logger.Info("Sending transfer query request to registry")
 	transferQueryMsg := &rymessages.DomainTransferQueryRequest{
 		Name: tn.DomainName,
@@ -12,11 +12,14 @@ import (
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
 
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
 	"github.com/tucowsinc/tdp-workers-go/domain/handlers"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/dns"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lifecycle"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 )
 
@@ -51,7 +54,6 @@ func main() {
 			types.LogFieldKeys.Error: err,
 		})
 	}
-	defer messagebusServer.Finalize()
 
 	log.Info(types.LogMessages.MessageBusSetupSuccess)
 
@@ -66,13 +68,26 @@ func main() {
 
 	log.Info(types.LogMessages.DatabaseConnectionSuccess)
 
-	service := handlers.NewWorkerService(messagebusServer, db, tracer)
+	// recover handler panics, failing the job of the message
+	recoverer := jobhandler.NewRecoverer(db, cfg.HandlerPanicAck)
+
+	// drain in-flight handlers on shutdown
+	lc := lifecycle.New(cfg.GetShutdownTimeout())
+
+	resolver, err := dns.NewDNSResolver(cfg)
+	if err != nil {
+		log.Fatal("Failed to create DNS resolver", log.Fields{"error": err})
+	}
+
+	service := handlers.NewWorkerService(lc.Bus(recoverer.Bus(messagebusServer)), db, tracer)
+	service.SetNameserverChecker(dns.NewNameserverChecker(resolver, cfg))
 	service.RegisterHandlers()
+	service.Routes().Validate(context.Background(), db, cfg.RmqQueueName)
 
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range service.HealthChecks(cfg) {
+		for _, check := range append(service.HealthChecks(cfg), lc.HealthCheck()) {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -98,11 +113,12 @@ func main() {
 		Prefetch: &cfg.RmqPrefetchCount,
 	}
 
-	err = messagebusServer.Consume(queues, consumerOptions) // block until the messagebus quits
+	err = lc.Run(func() error { return messagebusServer.Consume(queues, consumerOptions) }, nil, func() { messagebusServer.Finalize() }) // block until the messagebus quits or is drained on shutdown
 	if err != nil {
-		log.Fatal(types.LogMessages.ConsumingQueuesFailed, log.Fields{
+		log.Error(types.LogMessages.ConsumingQueuesFailed, log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
+		return
 	}
 
 	log.Info(types.LogMessages.WorkerTerminated)
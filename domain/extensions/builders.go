@@ -0,0 +1,162 @@
+package extensions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+)
+
+// field is an order attribute copied to a field of the extension
+type field struct {
+	// attribute is the order attribute key
+	attribute string
+	// key is the extension field
+	key      string
+	required bool
+	// values are the allowed values, any value is allowed when empty
+	values  []string
+	pattern *regexp.Regexp
+}
+
+// attributeBuilder builds an extension with the order attributes of its fields, sent as a
+// google.protobuf.Struct which the registry interface maps to the registry EPP extension
+type attributeBuilder struct {
+	name   string
+	fields []field
+	// optional builders send nothing on create when the order has none of their attributes
+	optional bool
+	// check validates rules across fields of the extension
+	check func(ext map[string]any) error
+}
+
+func (b attributeBuilder) Name() string {
+	return b.name
+}
+
+func (b attributeBuilder) Build(attributes map[string]string, update bool) (proto.Message, error) {
+	ext := make(map[string]any)
+	for _, f := range b.fields {
+		if value := strings.TrimSpace(attributes[f.attribute]); value != "" {
+			ext[f.key] = value
+		}
+	}
+
+	if len(ext) == 0 && (update || b.optional) {
+		return nil, nil
+	}
+
+	for _, f := range b.fields {
+		value, ok := ext[f.key].(string)
+		if !ok {
+			if f.required {
+				return nil, fmt.Errorf("%w: attribute %s is required", ErrInvalid, f.attribute)
+			}
+			continue
+		}
+
+		if len(f.values) > 0 && !slices.Contains(f.values, value) {
+			return nil, fmt.Errorf("%w: attribute %s value %q is not one of %v", ErrInvalid, f.attribute, value, f.values)
+		}
+
+		if f.pattern != nil && !f.pattern.MatchString(value) {
+			return nil, fmt.Errorf("%w: attribute %s value %q is not valid", ErrInvalid, f.attribute, value)
+		}
+	}
+
+	if b.check != nil {
+		if err := b.check(ext); err != nil {
+			return nil, err
+		}
+	}
+
+	return structpb.NewStruct(ext)
+}
+
+// nexusBuilder builds the .us nexus extension, the country is required for the C31 and C32 categories
+var nexusBuilder = attributeBuilder{
+	name: "nexus",
+	fields: []field{
+		{attribute: "nexus.category", key: "category", required: true, values: []string{"C11", "C12", "C21", "C31", "C32"}},
+		{attribute: "nexus.country", key: "country", pattern: countryPattern},
+		{attribute: "nexus.app_purpose", key: "app_purpose", required: true, values: []string{"P1", "P2", "P3", "P4", "P5"}},
+	},
+	check: func(ext map[string]any) error {
+		category := ext["category"].(string)
+		_, hasCountry := ext["country"]
+
+		if (category == "C31" || category == "C32") && !hasCountry {
+			return fmt.Errorf("%w: attribute nexus.country is required for category %s", ErrInvalid, category)
+		}
+
+		if category != "C31" && category != "C32" && hasCountry {
+			return fmt.Errorf("%w: attribute nexus.country is only allowed for categories C31 and C32", ErrInvalid)
+		}
+
+		return nil
+	},
+}
+
+// eligibilityBuilder builds the .au registrant eligibility extension
+var eligibilityBuilder = attributeBuilder{
+	name: "eligibility",
+	fields: []field{
+		{attribute: "eligibility.type", key: "type", required: true, values: []string{
+			"Charity", "Citizen/Resident", "Club", "Commercial Statutory Body", "Company", "Incorporated Association",
+			"Industry Body", "Non-profit Organisation", "Other", "Partnership", "Pending TM Owner", "Political Party",
+			"Registered Business", "Sole Trader", "Trade Union", "Trademark Owner",
+		}},
+		{attribute: "eligibility.name", key: "name"},
+		{attribute: "eligibility.id_type", key: "id_type", values: []string{"ABN", "ACN", "ACT BN", "NSW BN", "NT BN", "OTHER", "QLD BN", "SA BN", "TAS BN", "TM", "VIC BN", "WA BN"}},
+		{attribute: "eligibility.id", key: "id"},
+		{attribute: "eligibility.policy_reason", key: "policy_reason", required: true, values: []string{"1", "2"}},
+	},
+	check: func(ext map[string]any) error {
+		_, hasIdType := ext["id_type"]
+		_, hasId := ext["id"]
+
+		if hasIdType != hasId {
+			return fmt.Errorf("%w: attributes eligibility.id and eligibility.id_type must be set together", ErrInvalid)
+		}
+
+		return nil
+	},
+}
+
+// trademarkBuilder builds the trademark extension of TLDs restricting registrations to trademark holders
+var trademarkBuilder = attributeBuilder{
+	name: "trademark",
+	fields: []field{
+		{attribute: "trademark.name", key: "name", required: true},
+		{attribute: "trademark.number", key: "number", required: true},
+		{attribute: "trademark.country", key: "country", required: true, pattern: countryPattern},
+		{attribute: "trademark.date", key: "date"},
+	},
+	check: func(ext map[string]any) error {
+		if date, ok := ext["date"].(string); ok {
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return fmt.Errorf("%w: attribute trademark.date value %q is not a YYYY-MM-DD date", ErrInvalid, date)
+			}
+		}
+
+		return nil
+	},
+}
+
+// languageBuilder builds the registrant language extension, the language is a BCP 47 tag
+var languageBuilder = attributeBuilder{
+	name: "language",
+	fields: []field{
+		{attribute: "language.tag", key: "tag", required: true, pattern: languagePattern},
+	},
+	optional: true,
+}
@@ -0,0 +1,97 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tldsetting"
+)
+
+// BuildersSetting is the TLD setting listing the extension builders of the TLD
+const BuildersSetting = "tld.order.extension_builders"
+
+// ErrInvalid is wrapped by the errors of order attributes which are missing or not valid
+var ErrInvalid = joberrors.Permanent(errors.New("invalid extension attributes"))
+
+// ErrUnknownBuilder is returned when a TLD setting lists a builder which is not registered
+var ErrUnknownBuilder = joberrors.Permanent(errors.New("unknown extension builder"))
+
+// Builder builds a TLD specific registry extension from the order attributes
+type Builder interface {
+	// Name is the name of the builder in the TLD setting and the extension key of the registry request
+	Name() string
+	// Build returns the extension of a domain create, or of a domain update when update is set.
+	// It returns nil when there is nothing to send.
+	Build(attributes map[string]string, update bool) (proto.Message, error)
+}
+
+// registry holds the builders by name
+var registry = map[string]Builder{}
+
+func init() {
+	for _, b := range []Builder{nexusBuilder, eligibilityBuilder, trademarkBuilder, languageBuilder} {
+		Register(b)
+	}
+}
+
+// Register adds the builder to the registry, replacing a builder of the same name.
+// It must be called before the worker starts handling jobs.
+func Register(b Builder) {
+	registry[b.Name()] = b
+}
+
+// Lookup returns the registered builder of name
+func Lookup(name string) (Builder, bool) {
+	b, ok := registry[name]
+	return b, ok
+}
+
+// LoadBuilders returns the builders listed in the TLD setting of the accreditation tld
+func LoadBuilders(ctx context.Context, db database.Database, accreditationTldId string) ([]Builder, error) {
+	names, err := tldsetting.StringList(ctx, db, accreditationTldId, BuildersSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]Builder, 0, len(names))
+	for _, name := range names {
+		b, ok := Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownBuilder, name)
+		}
+		builders = append(builders, b)
+	}
+
+	return builders, nil
+}
+
+// Build runs the builders on the order attributes and returns their extensions keyed by builder name
+func Build(builders []Builder, attributes map[string]string, update bool) (map[string]*anypb.Any, error) {
+	extensions := make(map[string]*anypb.Any)
+
+	for _, b := range builders {
+		msg, err := b.Build(attributes, update)
+		if err != nil {
+			return nil, fmt.Errorf("%s extension: %w", b.Name(), err)
+		}
+
+		if msg == nil {
+			continue
+		}
+
+		anyMsg, err := anypb.New(msg)
+		if err != nil {
+			return nil, fmt.Errorf("%s extension: %w", b.Name(), err)
+		}
+
+		extensions[b.Name()] = anyMsg
+	}
+
+	return extensions, nil
+}
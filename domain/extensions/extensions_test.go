@@ -0,0 +1,99 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+)
+
+// fixture is a test case of testdata/<builder>.json
+type fixture struct {
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes"`
+	Update     bool              `json:"update"`
+	// Extension is the expected extension, no extension is expected when empty
+	Extension map[string]any `json:"extension"`
+	// Error is the expected error message, the build is expected to succeed when empty
+	Error string `json:"error"`
+}
+
+func TestBuilders(t *testing.T) {
+	for _, name := range []string{"nexus", "eligibility", "trademark", "language"} {
+		b, ok := Lookup(name)
+		require.True(t, ok, name)
+
+		raw, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+		require.NoError(t, err)
+
+		var fixtures []fixture
+		require.NoError(t, json.Unmarshal(raw, &fixtures))
+
+		for _, f := range fixtures {
+			t.Run(name+"/"+f.Name, func(t *testing.T) {
+				msg, err := b.Build(f.Attributes, f.Update)
+				if f.Error != "" {
+					require.ErrorIs(t, err, ErrInvalid)
+					require.ErrorContains(t, err, f.Error)
+					return
+				}
+
+				require.NoError(t, err)
+				if f.Extension == nil {
+					require.Nil(t, msg)
+					return
+				}
+
+				require.IsType(t, &structpb.Struct{}, msg)
+				require.Equal(t, f.Extension, msg.(*structpb.Struct).AsMap())
+			})
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	builders := []Builder{nexusBuilder, languageBuilder}
+
+	extensions, err := Build(builders, map[string]string{"nexus.category": "C11", "nexus.app_purpose": "P1"}, false)
+	require.NoError(t, err)
+	require.Len(t, extensions, 1)
+
+	nexus := new(structpb.Struct)
+	require.NoError(t, extensions["nexus"].UnmarshalTo(nexus))
+	require.Equal(t, map[string]any{"category": "C11", "app_purpose": "P1"}, nexus.AsMap())
+
+	extensions, err = Build(builders, nil, true)
+	require.NoError(t, err)
+	require.Empty(t, extensions)
+
+	_, err = Build(builders, map[string]string{"language.tag": "fr"}, false)
+	require.ErrorIs(t, err, ErrInvalid)
+	require.ErrorContains(t, err, "nexus extension")
+}
+
+func TestLoadBuilders(t *testing.T) {
+	ctx := context.Background()
+
+	db := &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{BuildersSetting: "{nexus,language}"})
+
+	builders, err := LoadBuilders(ctx, db, "acc-tld")
+	require.NoError(t, err)
+	require.Len(t, builders, 2)
+	require.Equal(t, "nexus", builders[0].Name())
+	require.Equal(t, "language", builders[1].Name())
+
+	db = &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{BuildersSetting: "{nexus,unknown}"})
+
+	_, err = LoadBuilders(ctx, db, "acc-tld")
+	require.ErrorIs(t, err, ErrUnknownBuilder)
+	require.False(t, joberrors.IsTransient(err))
+}
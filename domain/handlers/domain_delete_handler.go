@@ -1,15 +1,13 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	rymessages "github.com/tucowsinc/tdp-messages-go/message/ryinterface"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -17,105 +15,57 @@ import (
 // DomainDeleteHandler This is a callback handler for the DomainDelete event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) DomainDeleteHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainDeleteHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Info("Starting DomainDeleteHandler for the job")
-
-	data := new(types.DomainDeleteData)
-
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
-
-		logger.Info("Starting domain delete job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
+	return jobhandler.NewJobHandler("DomainDeleteHandler", service.db, service.tracer, service.domainDelete).
+		Use(domainDeleted).
+		Use(jobhandler.RegistryGate(func(data *types.DomainDeleteData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.DomainDeleteData) lease.Key {
+			return lease.Domain(data.Name, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-		err = json.Unmarshal(job.Info.Data, data)
-		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
+// domainDeleted completes the job without a registry command when the domain is already deleted on the registry
+func domainDeleted(next jobhandler.JobHandlerFunc[types.DomainDeleteData]) jobhandler.JobHandlerFunc[types.DomainDeleteData] {
+	return func(r *jobhandler.JobRequest[types.DomainDeleteData]) error {
+		if r.Data.InRedemptionGracePeriod || r.Data.Metadata["domain_not_found"] == true {
+			r.Logger.Info("Domain was successfully deleted on the registry backend", log.Fields{
+				types.LogFieldKeys.Domain: r.Data.Name,
 			})
 
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
+			return r.SetStatus(types.JobStatus.Completed, nil)
 		}
 
-		// Check if the domain was deleted, so mark the job as completed
-		if data.InRedemptionGracePeriod || data.Metadata["domain_not_found"] == true {
-			logger.Info("Domain was successfully deleted on the registry backend", log.Fields{
-				types.LogFieldKeys.Domain: data.Name,
-			})
+		return next(r)
+	}
+}
 
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Completed, nil)
-		}
+func (service *WorkerService) domainDelete(r *jobhandler.JobRequest[types.DomainDeleteData]) (err error) {
+	data := r.Data
 
-		msg := rymessages.DomainDeleteRequest{
-			Name: data.Name,
-		}
+	r.Logger.Info("Starting domain delete job processing")
 
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
-		}
+	msg := rymessages.DomainDeleteRequest{
+		Name: data.Name,
+	}
 
-		err = server.MessageBus().Send(ctx, queue, &msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	err = r.Server.MessageBus().Send(r.Ctx, queue, &msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
-
-		return
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
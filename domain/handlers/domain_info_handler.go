@@ -1,15 +1,12 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -17,102 +14,44 @@ import (
 // DomainInfoHandler This is a callback handler for the DomainInfo event
 // It sends a DomainInfoRequest to the registry to get the domain information
 func (service *WorkerService) DomainInfoHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainInfoHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Info("Started DomainInfoHandler for the job")
-
-	data := new(types.DomainInfoData)
-
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
-
-		logger.Info("Starting domain info job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
-
-		err = json.Unmarshal(job.Info.Data, data)
-		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
+	return jobhandler.NewJobHandler("DomainInfoHandler", service.db, service.tracer, service.domainInfo).
+		Use(jobhandler.RegistryGate(func(data *types.DomainInfoData) string { return data.Accreditation.AccreditationName })).
+		Handle(server, message)
+}
 
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
+func (service *WorkerService) domainInfo(r *jobhandler.JobRequest[types.DomainInfoData]) (err error) {
+	data := r.Data
 
-		// Create the DomainInfoRequest message
-		msg := ryinterface.DomainInfoRequest{
-			Name: data.Name,
-		}
+	r.Logger.Info("Starting domain info job processing")
 
-		// Set the pw if it is not nil
-		if data.Pw != nil {
-			msg.Pw = data.Pw
-		}
+	// Create the DomainInfoRequest message
+	msg := ryinterface.DomainInfoRequest{
+		Name: data.Name,
+	}
 
-		queue := types.GetQueryQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
-		}
+	// Set the pw if it is not nil
+	if data.Pw != nil {
+		msg.Pw = data.Pw
+	}
 
-		err = server.MessageBus().Send(ctx, queue, &msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	queue := types.GetQueryQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	err = r.Server.MessageBus().Send(r.Ctx, queue, &msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
-
-		return
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
@@ -1,21 +1,23 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface/extension"
 	"github.com/tucowsinc/tdp-shared-go/logger"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	tldextensions "github.com/tucowsinc/tdp-workers-go/domain/extensions"
+	"github.com/tucowsinc/tdp-workers-go/pkg/dnssec"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/idn"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -23,122 +25,162 @@ import (
 // DomainProvisionHandler This is a callback handler for the DomainProvision event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) DomainProvisionHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainProvisionHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
+	return jobhandler.NewJobHandler("DomainProvisionHandler", service.db, service.tracer, service.domainProvision).
+		Use(jobhandler.RegistryGate(func(data *types.DomainData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.DomainData) lease.Key {
+			return lease.Domain(data.Name, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-	logger.Info("Starting DomainProvisionHandler for the job")
+func (service *WorkerService) domainProvision(r *jobhandler.JobRequest[types.DomainData]) (err error) {
+	data := r.Data
 
-	data := new(types.DomainData)
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	r.Logger.Info("Starting domain provision job processing")
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
+	// check if the registry supports host objects
+	hostObjectSupported, err := getBoolAttribute(r.DB, r.Ctx, "tld.order.host_object_supported", data.AccreditationTld.AccreditationTldId)
+	if err != nil {
+		r.Logger.Error("Failed to get host object supported attribute", log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(err, nil)
+	}
 
-		logger.Info("Starting domain provision job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
+	// normalize the domain name to its A-label and validate its IDN data
+	err = prepareIdn(r)
+	if err != nil {
+		r.Logger.Error("Failed to prepare internationalized domain name", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return r.FailOrReschedule(err, nil)
+	}
 
-		err = json.Unmarshal(job.Info.Data, data)
+	// validate DNSSEC data and convert it to the interface the registry supports
+	if data.SecDNS != nil {
+		policy, err := dnssec.LoadPolicy(r.Ctx, r.DB, data.AccreditationTld.AccreditationTldId)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+			r.Logger.Error("Failed to get DNSSEC policy", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
+			return r.FailOrReschedule(err, nil)
 		}
 
-		// check if the registry supports host objects
-		hostObjectSupported, err := getBoolAttribute(tx, ctx, "tld.order.host_object_supported", data.AccreditationTld.AccreditationTldId)
+		data.SecDNS, err = policy.Prepare(data.Name, data.SecDNS)
 		if err != nil {
-			logger.Error("Failed to get host object supported attribute", log.Fields{
+			r.Logger.Error("Invalid DNSSEC data", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
-			return err
+			return r.FailOrReschedule(err, nil)
 		}
+	}
 
-		// create the message to send to the registry interface
-		reqBuilder := NewDomainCreateRequestBuilder(data)
+	// check the nameservers resolve and answer for the domain when the TLD requires it
+	warning, err := service.checkNameservers(r.Ctx, r.DB, data.AccreditationTld.AccreditationTldId, data.Name, data.Nameservers)
+	if err != nil {
+		r.Logger.Error("Failed to check nameservers", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return r.FailOrReschedule(err, nil)
+	}
 
-		// set the contacts and nameservers
-		reqBuilder, err = reqBuilder.
-			SetDomainCreateContacts(data.Contacts, logger).
-			SetDomainCreateNameservers(data.Nameservers, types.SafeDeref(hostObjectSupported), logger).
-			SetDomainCreateExtensions(data, logger)
+	if warning != "" {
+		r.Logger.Warn("Nameserver check failed, flagging the job", log.Fields{
+			types.LogFieldKeys.Error: warning,
+		})
+	}
 
-		if err != nil {
-			logger.Error("Failed to set domain create request extensions", log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return err
-		}
+	// get the builders of the TLD specific extensions
+	builders, err := tldextensions.LoadBuilders(r.Ctx, r.DB, data.AccreditationTld.AccreditationTldId)
+	if err != nil {
+		r.Logger.Error("Failed to get TLD extension builders", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return r.FailOrReschedule(err, nil)
+	}
 
-		// build the message
-		msg := reqBuilder.Build()
+	// create the message to send to the registry interface
+	reqBuilder := NewDomainCreateRequestBuilder(data)
 
-		// send the message to the registry interface
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
-		}
+	// set the contacts and nameservers
+	reqBuilder, err = reqBuilder.
+		SetDomainCreateContacts(data.Contacts, r.Logger).
+		SetDomainCreateNameservers(data.Nameservers, types.SafeDeref(hostObjectSupported), r.Logger).
+		SetDomainCreateExtensions(data, builders, r.Logger)
 
-		err = server.MessageBus().Send(ctx, queue, msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	if err != nil {
+		r.Logger.Error("Failed to set domain create request extensions", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return r.FailOrReschedule(err, nil)
+	}
+
+	// build the message
+	msg := reqBuilder.Build()
+
+	// send the message to the registry interface
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	err = r.Server.MessageBus().Send(r.Ctx, queue, msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
+
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
+	})
+
+	// flag the job with the nameserver check warning
+	if warning != "" {
+		r.Job.ResultMessage = &warning
+	}
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
+}
+
+// prepareIdn converts the domain name to its A-label. The IDN uname must be the same name and is
+// sent as its normalized U-label, the IDN language must be supported and match the label scripts.
+func prepareIdn(r *jobhandler.JobRequest[types.DomainData]) error {
+	data := r.Data
+
+	ascii, unicodeName, err := idn.Normalize(data.Name)
+	if err != nil {
+		return err
+	}
+
+	data.Name = ascii
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
+	if data.IdnData == nil {
+		return nil
+	}
+
+	if data.IdnData.IdnUname != "" {
+		unameAscii, _, err := idn.Normalize(data.IdnData.IdnUname)
 		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
+			return err
 		}
 
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
+		if unameAscii != ascii {
+			return fmt.Errorf("%w: uname %q does not match domain name %q", idn.ErrInvalid, data.IdnData.IdnUname, ascii)
+		}
+	}
 
-		return
-	})
+	data.IdnData.IdnUname = unicodeName
+
+	supported, err := idn.LoadSupportedLanguages(r.Ctx, r.DB, data.AccreditationTld.AccreditationTldId)
+	if err != nil {
+		return err
+	}
+
+	return idn.CheckLanguage(unicodeName, data.IdnData.IdnLang, supported)
 }
 
 type DomainCreateRequestBuilder struct {
@@ -227,7 +269,7 @@ func (b *DomainCreateRequestBuilder) SetDomainCreateNameservers(domainNameserver
 	return b
 }
 
-func (b *DomainCreateRequestBuilder) SetDomainCreateExtensions(data *types.DomainData, logger logger.ILogger) (*DomainCreateRequestBuilder, error) {
+func (b *DomainCreateRequestBuilder) SetDomainCreateExtensions(data *types.DomainData, builders []tldextensions.Builder, logger logger.ILogger) (*DomainCreateRequestBuilder, error) {
 	// initialize the extensions map and error
 	var extensions = make(map[string]*anypb.Any)
 	var err error
@@ -256,6 +298,19 @@ func (b *DomainCreateRequestBuilder) SetDomainCreateExtensions(data *types.Domai
 		}
 	}
 
+	// add the TLD specific extensions built from the order attributes
+	tldExtensions, err := tldextensions.Build(builders, data.Attributes, false)
+	if err != nil {
+		logger.Error("Failed to build TLD extensions", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return b, err
+	}
+
+	for name, ext := range tldExtensions {
+		extensions[name] = ext
+	}
+
 	// set the extensions
 	if len(extensions) > 0 {
 		b.request.Extensions = extensions
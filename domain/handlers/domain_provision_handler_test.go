@@ -6,8 +6,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
 	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
@@ -76,7 +78,7 @@ func insertDomainProvisionTestJob(db database.Database, withPrice bool, withSecD
 	}
 
 	data = &types.DomainData{
-		Name: "test_domain_name",
+		Name: "test-domain-name.sexy",
 		Contacts: []types.DomainContact{
 			{
 				Type:   "admin",
@@ -134,19 +136,20 @@ func insertDomainProvisionTestJob(db database.Database, withPrice bool, withSecD
 			MaxSigLife: &max_sig_life,
 			DsData: &[]types.DSData{
 				{
-					KeyTag:     1,
-					Algorithm:  3,
-					DigestType: 1,
-					Digest:     "test-digest",
+					KeyTag:     60485,
+					Algorithm:  13,
+					DigestType: 2,
+					Digest:     "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A",
 				},
 			},
 		}
 	}
 
 	if withIdn {
+		data.Name = "xn--bcher-kva.sexy"
 		data.IdnData = &types.IdnData{
-			IdnUname: "test-idn-uname",
-			IdnLang:  "test-idn-lang",
+			IdnUname: "Bücher.sexy",
+			IdnLang:  "de",
 		}
 	}
 
@@ -316,7 +319,7 @@ func (suite *DomainProvisionTestSuite) TestDomainProvisionHandlerWithSecDNS() {
 		MaxSigLife: &expectedMaxSigLife,
 		Data: &extension.SecdnsCreateRequest_DsSet{
 			DsSet: &extension.DsDataSet{
-				DsData: []*extension.DsData{{Digest: "test-digest", KeyTag: 1, Alg: 3, DigestType: 1}},
+				DsData: []*extension.DsData{{Digest: "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A", KeyTag: 60485, Alg: 13, DigestType: 2}},
 			},
 		}})
 	expectedMsg := ryinterface.DomainCreateRequest{
@@ -470,8 +473,8 @@ func (suite *DomainProvisionTestSuite) TestDomainProvisionHandlerWithIdn() {
 		Id:   "test_handle",
 	})
 	expectedIdnData, _ := anypb.New(&extension.IdnCreateRequest{
-		Uname: "test-idn-uname",
-		Table: "test-idn-lang",
+		Uname: "bücher.sexy",
+		Table: "de",
 	})
 
 	expectedMsg := ryinterface.DomainCreateRequest{
@@ -503,6 +506,63 @@ func (suite *DomainProvisionTestSuite) TestDomainProvisionHandlerWithIdn() {
 	suite.s.AssertExpectations(suite.T())
 }
 
+func (suite *DomainProvisionTestSuite) TestDomainProvisionHandlerWithInvalidIdn() {
+	tests := []struct {
+		name  string
+		uname string
+		lang  string
+	}{
+		{"uname not matching the domain name", "buecher.sexy", "de"},
+		{"language not matching the label scripts", "bücher.sexy", "ru"},
+		{"uname mixing scripts", "bücherпример.sexy", "de"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			_, data, err := insertDomainProvisionTestJob(suite.db, false, false, false, true)
+			suite.NoError(err, "Failed to insert test job")
+
+			data.IdnData.IdnUname = tt.uname
+			data.IdnData.IdnLang = tt.lang
+			serializedData, err := json.Marshal(data)
+			suite.NoError(err)
+
+			var tenantCustomerId, jobId string
+			suite.NoError(suite.db.GetDB().Table("tenant_customer").Select("id").Scan(&tenantCustomerId).Error)
+			sql := `SELECT job_submit(?, ?, ?, ?)`
+			err = suite.db.GetDB().Raw(sql, tenantCustomerId, "provision_domain_create", "0268f162-5d83-44d2-894a-ab7578c498fb", serializedData).Scan(&jobId).Error
+			suite.NoError(err, "Failed to insert test job")
+
+			msg := &jobmessage.Notification{
+				JobId:          jobId,
+				Type:           "domain_provision",
+				Status:         "status",
+				ReferenceId:    "0268f162-5d83-44d2-894a-ab7578c498fb",
+				ReferenceTable: "1234",
+			}
+
+			expectedContext := context.Background()
+
+			service := NewWorkerService(suite.mb, suite.db, suite.tracer)
+
+			suite.s.On("Headers").Return(map[string]any{})
+			suite.s.On("Context").Return(expectedContext)
+
+			handler := service.DomainProvisionHandler
+			err = handler(suite.s, msg)
+			suite.NoError(err, types.LogMessages.HandleMessageFailed)
+
+			job, err := suite.db.GetJobById(expectedContext, jobId, false)
+			suite.NoError(err, "Failed to get job by id")
+			suite.Equal(types.JobStatus.Failed, *job.Info.JobStatusName)
+
+			suite.mb.AssertNotCalled(suite.T(), "Send")
+		})
+	}
+}
+
 func (suite *DomainProvisionTestSuite) TestUnmarshallJobDataWithIdn() {
 	j := `{"pw": "9(;!B<d\\obYTGqm", "name": "tdp-test-1-1725478976.help", "idn": {"uname": "test-idn-uname", "language": "test-idn-lang"}, "contacts": [{"type": "billing", "handle": null}, {"type": "tech", "handle": null}, {"type": "admin", "handle": null}, {"type": "registrant", "handle": null}], "metadata": {"order_id": "a683cab1-fd62-4752-893b-381dd778b571"}, "nameservers": null, "accreditation": {"is_proxy": false, "tenant_id": "26ac88c7-b774-4f56-938b-9f7378cb3eca", "provider_id": "2cdc06fa-ed02-4584-a629-b3649fa12305", "tenant_name": "opensrs", "provider_name": "trs", "accreditation_id": "95f2baed-5b5f-4756-a5b4-b6c5bad764f2", "accreditation_name": "opensrs-uniregistry", "provider_instance_id": "9fbb569a-7f5c-4ace-862c-943e9c57f04f", "provider_instance_name": "trs-uniregistry"}, "accreditation_tld": {"tld_id": "e6d3b097-7a19-4da3-99e1-09eead9cd703", "is_proxy": false, "tld_name": "help", "tenant_id": "26ac88c7-b774-4f56-938b-9f7378cb3eca", "is_default": true, "provider_id": "2cdc06fa-ed02-4584-a629-b3649fa12305", "registry_id": "bf84cf8a-a067-4204-a1e5-f99f08fb0e95", "tenant_name": "opensrs", "provider_name": "trs", "registry_name": "unr-registry", "accreditation_id": "95f2baed-5b5f-4756-a5b4-b6c5bad764f2", "accreditation_name": "opensrs-uniregistry", "accreditation_tld_id": "bcfb51e1-fc64-4ee7-a6de-049e06b61f4c", "provider_instance_id": "9fbb569a-7f5c-4ace-862c-943e9c57f04f", "provider_instance_name": "trs-uniregistry"}, "tenant_customer_id": "d50ff47e-2a80-4528-b455-6dc5d200ecbe", "registration_period": 1, "provision_contact_id": "42fc0845-1f09-4833-853b-87d5247493f6"}`
 
@@ -515,3 +575,113 @@ func (suite *DomainProvisionTestSuite) TestUnmarshallJobDataWithIdn() {
 	suite.Equal("test-idn-uname", data.IdnData.IdnUname)
 	suite.Equal("test-idn-lang", data.IdnData.IdnLang)
 }
+
+// setTLDSetting overrides the TLD setting of the accreditation tld and returns a function restoring its default
+func setTLDSetting(db database.Database, accreditationTldId string, key string, value string) (reset func(), err error) {
+	tx := db.GetDB()
+
+	err = tx.Exec(`UPDATE v_attribute SET value = ? WHERE accreditation_tld_id = ? AND key = ?`, value, accreditationTldId, key).Error
+	if err != nil {
+		return
+	}
+
+	reset = func() {
+		tx.Exec(`
+			DELETE FROM attr_value av
+			USING v_attribute va
+			WHERE va.accreditation_tld_id = ? AND va.key = ?
+				AND av.key_id = va.key_id AND av.tenant_id = va.tenant_id AND av.tld_id = va.tld_id
+		`, accreditationTldId, key)
+	}
+
+	return
+}
+
+func (suite *DomainProvisionTestSuite) TestDomainProvisionHandlerWithExtensionBuilders() {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		extension  map[string]any
+	}{
+		{
+			name:       "valid attributes",
+			attributes: map[string]string{"nexus.category": "C11", "nexus.app_purpose": "P1"},
+			extension:  map[string]any{"category": "C11", "app_purpose": "P1"},
+		},
+		{
+			name:       "missing attributes",
+			attributes: nil,
+		},
+		{
+			name:       "invalid attributes",
+			attributes: map[string]string{"nexus.category": "C99", "nexus.app_purpose": "P1"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			_, data, err := insertDomainProvisionTestJob(suite.db, false, false, false, false)
+			suite.NoError(err, "Failed to insert test job")
+
+			reset, err := setTLDSetting(suite.db, data.AccreditationTld.AccreditationTldId, "tld.order.extension_builders", "{nexus}")
+			suite.NoError(err, "Failed to set extension builders")
+			defer reset()
+
+			data.Attributes = tt.attributes
+			serializedData, err := json.Marshal(data)
+			suite.NoError(err)
+
+			var tenantCustomerId, jobId string
+			suite.NoError(suite.db.GetDB().Table("tenant_customer").Select("id").Scan(&tenantCustomerId).Error)
+			sql := `SELECT job_submit(?, ?, ?, ?)`
+			err = suite.db.GetDB().Raw(sql, tenantCustomerId, "provision_domain_create", "0268f162-5d83-44d2-894a-ab7578c498fb", serializedData).Scan(&jobId).Error
+			suite.NoError(err, "Failed to insert test job")
+
+			msg := &jobmessage.Notification{
+				JobId:          jobId,
+				Type:           "domain_provision",
+				Status:         "status",
+				ReferenceId:    "0268f162-5d83-44d2-894a-ab7578c498fb",
+				ReferenceTable: "1234",
+			}
+
+			expectedContext := context.Background()
+			expectedDestination := types.GetTransformQueue(accreditationName)
+
+			service := NewWorkerService(suite.mb, suite.db, suite.tracer)
+
+			// the extension is compared once decoded, struct fields are not marshalled in a stable order
+			var sent *ryinterface.DomainCreateRequest
+			suite.mb.On("Send", expectedContext, expectedDestination, mock.AnythingOfType("*ryinterface.DomainCreateRequest"), mock.Anything).
+				Run(func(args mock.Arguments) { sent = args.Get(2).(*ryinterface.DomainCreateRequest) }).
+				Return(nil).Maybe()
+			suite.s.On("MessageBus").Return(suite.mb).Maybe()
+			suite.s.On("Headers").Return(map[string]any{})
+			suite.s.On("Context").Return(expectedContext)
+
+			handler := service.DomainProvisionHandler
+			err = handler(suite.s, msg)
+			suite.NoError(err, types.LogMessages.HandleMessageFailed)
+
+			job, err := suite.db.GetJobById(expectedContext, jobId, false)
+			suite.NoError(err, "Failed to get job by id")
+
+			if tt.extension == nil {
+				suite.Equal(types.JobStatus.Failed, *job.Info.JobStatusName)
+				suite.Contains(types.SafeDeref(job.ResultMessage), "nexus extension")
+				suite.mb.AssertNotCalled(suite.T(), "Send")
+				return
+			}
+
+			suite.Equal(types.JobStatus.Processing, *job.Info.JobStatusName)
+			suite.Require().NotNil(sent)
+
+			nexus := new(structpb.Struct)
+			suite.Require().Contains(sent.Extensions, "nexus")
+			suite.NoError(sent.Extensions["nexus"].UnmarshalTo(nexus))
+			suite.Equal(tt.extension, nexus.AsMap())
+		})
+	}
+}
@@ -1,18 +1,16 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface/extension"
 	"github.com/tucowsinc/tdp-shared-go/logger"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -20,126 +18,71 @@ import (
 // DomainRedeemHandler This is a callback handler for the DomainRedeem event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) DomainRedeemHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainRedeemHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Debug("Starting DomainRedeemHandler for the job")
-
-	data := new(types.DomainRedeemData)
-
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
-
-		logger.Info("Starting domain redeem job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-
-			return
-		}
-
-		err = json.Unmarshal(job.Info.Data, data)
-		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
+	return jobhandler.NewJobHandler("DomainRedeemHandler", service.db, service.tracer, service.domainRedeem).
+		Use(domainRestorePending).
+		Use(jobhandler.RegistryGate(func(data *types.DomainRedeemData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.DomainRedeemData) lease.Key {
+			return lease.Domain(data.Name, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-		// If the domain RGP status is pending restore, don't send the request to the registry
-		// and set the job status to completed
-		pdr, err := tx.GetProvisionDomainRedeem(ctx, data.ProvisionDomainRedeemId)
+// domainRestorePending completes the job without a registry command when the domain RGP status is pending restore
+func domainRestorePending(next jobhandler.JobHandlerFunc[types.DomainRedeemData]) jobhandler.JobHandlerFunc[types.DomainRedeemData] {
+	return func(r *jobhandler.JobRequest[types.DomainRedeemData]) error {
+		pdr, err := r.Tx.GetProvisionDomainRedeem(r.Ctx, r.Data.ProvisionDomainRedeemId)
 		if err != nil {
-			logger.Error("Failed to get provision data from DB", log.Fields{
+			r.Logger.Error("Failed to get provision data from DB", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
+			return r.Fail(err.Error())
 		}
 
 		if pdr.InRestorePendingStatus != nil && *pdr.InRestorePendingStatus {
-			logger.Info("Domain is in pending restore status", log.Fields{
-				types.LogFieldKeys.Domain: data.Name,
+			r.Logger.Info("Domain is in pending restore status", log.Fields{
+				types.LogFieldKeys.Domain: r.Data.Name,
 			})
 
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Completed, nil)
+			return r.SetStatus(types.JobStatus.Completed, nil)
 		}
 
-		msg, err := toDomainRedeemRequest(*data, logger)
-		if err != nil {
-			logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-		}
+		return next(r)
+	}
+}
 
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
-		}
+func (service *WorkerService) domainRedeem(r *jobhandler.JobRequest[types.DomainRedeemData]) (err error) {
+	data := r.Data
 
-		err = server.MessageBus().Send(ctx, queue, msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	r.Logger.Info("Starting domain redeem job processing")
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	msg, err := toDomainRedeemRequest(*data, r.Logger)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.Fail(err.Error())
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
+	err = r.Server.MessageBus().Send(r.Ctx, queue, msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		return
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
 
 func toDomainRedeemRequest(data types.DomainRedeemData, logger logger.ILogger) (msg *ryinterface.DomainUpdateRequest, err error) {
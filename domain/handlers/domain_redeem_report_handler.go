@@ -1,21 +1,20 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface/extension"
 	"github.com/tucowsinc/tdp-shared-go/logger"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -23,105 +22,47 @@ import (
 // DomainRedeemReportHandler This is a callback handler for the DomainRedeemReport event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) DomainRedeemReportHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainRedeemReportHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Debug("Starting DomainRedeemReportHandler for the job")
+	return jobhandler.NewJobHandler("DomainRedeemReportHandler", service.db, service.tracer, service.domainRedeemReport).
+		Use(jobhandler.RegistryGate(func(data *types.DomainRedeemData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.DomainRedeemData) lease.Key {
+			return lease.Domain(data.Name, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-	data := new(types.DomainRedeemData)
+func (service *WorkerService) domainRedeemReport(r *jobhandler.JobRequest[types.DomainRedeemData]) (err error) {
+	data := r.Data
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	r.Logger.Info("Starting domain redeem report job processing")
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
+	msg, err := toDomainRedeemReport(*data, r.Logger)
+	if err != nil {
+		r.Logger.Error("Failed to create domain redeem report", log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.Fail(err.Error())
+	}
 
-		logger.Info("Starting domain redeem report job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
-
-		err = json.Unmarshal(job.Info.Data, data)
-		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
-
-		msg, err := toDomainRedeemReport(*data, logger)
-		if err != nil {
-			logger.Error("Failed to create domain redeem report", log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-		}
-
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
-		}
-
-		err = server.MessageBus().Send(ctx, queue, msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	err = r.Server.MessageBus().Send(r.Ctx, queue, msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
-
-		return
-
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
 
 func formatDomainRedeemData(item types.DomainRedeemData, logger logger.ILogger) string {
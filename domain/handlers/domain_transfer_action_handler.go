@@ -1,15 +1,15 @@
 package handlers
 
 import (
-	"encoding/json"
+	"fmt"
 
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	rymessages "github.com/tucowsinc/tdp-messages-go/message/ryinterface"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -17,117 +17,63 @@ import (
 // DomainTransferActionHandler This is a callback handler for the DomainTransferAway/Cancel event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) DomainTransferActionHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainTransferActionHandler")
-	defer service.tracer.FinishSpan(span)
-
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Info("Started DomainTransferActionHandler for the job")
-
-	data := new(types.DomainTransferActionData)
-
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
-
-		logger.Info("Starting domain transfer action job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
+	return jobhandler.NewJobHandler("DomainTransferActionHandler", service.db, service.tracer, service.domainTransferAction).
+		Use(jobhandler.RegistryGate(func(data *types.DomainTransferActionData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.DomainTransferActionData) lease.Key {
+			return lease.Domain(data.Name, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-		err = json.Unmarshal(job.Info.Data, data)
-		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
+func (service *WorkerService) domainTransferAction(r *jobhandler.JobRequest[types.DomainTransferActionData]) (err error) {
+	data := r.Data
 
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
+	r.Logger.Info("Starting domain transfer action job processing")
 
-		var msg proto.Message
+	var msg proto.Message
 
-		switch data.TransferStatus {
-		case types.TransferStatus.ClientApproved:
-			msg = &rymessages.DomainTransferApproveRequest{
-				Name: data.Name,
-				Pw:   data.Pw,
-			}
-		case types.TransferStatus.ClientRejected:
-			msg = &rymessages.DomainTransferRejectRequest{
-				Name: data.Name,
-				Pw:   data.Pw,
-			}
-		case types.TransferStatus.ClientCancelled:
-			msg = &rymessages.DomainTransferCancelRequest{
-				Name: data.Name,
-				Pw:   data.Pw,
-			}
-		default:
-			logger.Error("Unexpected transfer status in job data", log.Fields{
-				types.LogFieldKeys.Status: data.TransferStatus,
-			})
-			return
+	switch data.TransferStatus {
+	case types.TransferStatus.ClientApproved:
+		msg = &rymessages.DomainTransferApproveRequest{
+			Name: data.Name,
+			Pw:   data.Pw,
 		}
-
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
+	case types.TransferStatus.ClientRejected:
+		msg = &rymessages.DomainTransferRejectRequest{
+			Name: data.Name,
+			Pw:   data.Pw,
 		}
-
-		err = server.MessageBus().Send(ctx, queue, msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
+	case types.TransferStatus.ClientCancelled:
+		msg = &rymessages.DomainTransferCancelRequest{
+			Name: data.Name,
+			Pw:   data.Pw,
 		}
-
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.Status:               data.TransferStatus,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	default:
+		r.Logger.Error("Unexpected transfer status in job data", log.Fields{
+			types.LogFieldKeys.Status: data.TransferStatus,
 		})
+		return r.Fail(fmt.Sprintf("unexpected transfer status %q", data.TransferStatus))
+	}
+
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
+
+	err = r.Server.MessageBus().Send(r.Ctx, queue, msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
-
-		return
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.Status:               data.TransferStatus,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
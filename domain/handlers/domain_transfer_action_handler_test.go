@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -119,6 +120,9 @@ func (suite *DomainTransferActionSuite) TestDomainTransferActionHandler() {
 			})
 			suite.db.On("GetJobById", mock.Anything, mock.Anything, mock.Anything).Return(expectedJob, nil)
 			suite.db.On("GetJobStatusId", "submitted").Return("submitted")
+			suite.db.On("GetActiveMaintenance", mock.Anything, mock.Anything).Return((*model.AccreditationMaintenance)(nil), database.ErrNotFound)
+			suite.db.On("AcquireRateLimitToken", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+			suite.db.On("AcquireObjectLease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(time.Duration(0), nil)
 			suite.db.On("SetJobStatus", mock.Anything, mock.Anything, "processing", mock.Anything).Return(nil)
 
 			suite.s.On("Context").Return(context.Background())
@@ -1,18 +1,16 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface/extension"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -20,113 +18,60 @@ import (
 // DomainTransferInRequestHandler This is a callback handler for the DomainTransfer event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) DomainTransferInRequestHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainTransferInRequestHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Info("Started DomainTransferInRequestHandler for the job")
-
-	data := new(types.DomainTransferInRequestData)
-
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{types.LogFieldKeys.Error: err})
-			return
-		}
-
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
-
-		logger.Info("Starting domain transfer in request job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
-
-		err = json.Unmarshal(job.Info.Data, data)
-		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
+	return jobhandler.NewJobHandler("DomainTransferInRequestHandler", service.db, service.tracer, service.domainTransferInRequest).
+		Use(jobhandler.RegistryGate(func(data *types.DomainTransferInRequestData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.DomainTransferInRequestData) lease.Key {
+			return lease.Domain(data.Name, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-		periodUnit := commonmessages.PeriodUnit_YEAR
+func (service *WorkerService) domainTransferInRequest(r *jobhandler.JobRequest[types.DomainTransferInRequestData]) (err error) {
+	data := r.Data
 
-		msg := ryinterface.DomainTransferRequest{
-			Name:           data.Name,
-			Pw:             data.Pw,
-			Period:         &data.TransferPeriod,
-			PeriodUnit:     &periodUnit,
-			RegistrantRoid: nil,
-		}
+	r.Logger.Info("Starting domain transfer in request job processing")
 
-		if data.Price != nil {
-			var anyFee *anypb.Any
-			feeExtension := &extension.FeeTransformRequest{Fee: []*extension.FeeFee{{Price: types.ToMoneyMsg(data.Price)}}}
-			anyFee, err = anypb.New(feeExtension)
-			if err != nil {
-				logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-			msg.Extensions = map[string]*anypb.Any{"fee": anyFee}
-		}
+	periodUnit := commonmessages.PeriodUnit_YEAR
 
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
-		}
+	msg := ryinterface.DomainTransferRequest{
+		Name:           data.Name,
+		Pw:             data.Pw,
+		Period:         &data.TransferPeriod,
+		PeriodUnit:     &periodUnit,
+		RegistrantRoid: nil,
+	}
 
-		err = server.MessageBus().Send(ctx, queue, &msg, headers)
+	if data.Price != nil {
+		var anyFee *anypb.Any
+		feeExtension := &extension.FeeTransformRequest{Fee: []*extension.FeeFee{{Price: types.ToMoneyMsg(data.Price)}}}
+		anyFee, err = anypb.New(feeExtension)
 		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			r.Logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
 			return
 		}
-
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+		msg.Extensions = map[string]*anypb.Any{"fee": anyFee}
+	}
+
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
+
+	err = r.Server.MessageBus().Send(r.Ctx, queue, &msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
-
-		return
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
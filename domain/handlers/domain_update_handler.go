@@ -2,20 +2,22 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	rymessages "github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface/extension"
+	tldextensions "github.com/tucowsinc/tdp-workers-go/domain/extensions"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/dnssec"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -29,116 +31,97 @@ var RYContactTypeMap = map[string]commonmessages.DomainContact_DomainContactType
 // DomainUpdateHandler This is a callback handler for the DomainProvision event
 // and is in charge of sending the request to the registry interface
 func (service *WorkerService) DomainUpdateHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "DomainUpdateHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
+	return jobhandler.NewJobHandler("DomainUpdateHandler", service.db, service.tracer, service.domainUpdate).
+		Use(jobhandler.RegistryGate(func(data *types.DomainUpdateData) string { return data.Accreditation.AccreditationName })).
+		Use(jobhandler.ObjectLease(func(data *types.DomainUpdateData) lease.Key {
+			return lease.Domain(data.Name, data.Accreditation.AccreditationName)
+		})).
+		Handle(server, message)
+}
 
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
+func (service *WorkerService) domainUpdate(r *jobhandler.JobRequest[types.DomainUpdateData]) (err error) {
+	data := r.Data
 
-	logger.Debug("Starting DomainUpdateHandler for the job")
+	r.Logger.Info("Starting domain update job processing")
 
-	data := new(types.DomainUpdateData)
+	// check if the registry supports host objects
+	hostObjectSupported, err := getBoolAttribute(r.DB, r.Ctx, "tld.order.host_object_supported", data.AccreditationTld.AccreditationTldId)
+	if err != nil {
+		r.Logger.Error("Failed to fetch host object support attribute", log.Fields{types.LogFieldKeys.Error: err})
+		return r.FailOrReschedule(err, nil)
+	}
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
+	// validate DNSSEC data and convert it to the interface the registry supports
+	if data.SecDNSData != nil {
+		policy, err := dnssec.LoadPolicy(r.Ctx, r.DB, data.AccreditationTld.AccreditationTldId)
 		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{types.LogFieldKeys.Error: err})
-			return
+			r.Logger.Error("Failed to get DNSSEC policy", log.Fields{types.LogFieldKeys.Error: err})
+			return r.FailOrReschedule(err, nil)
 		}
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
-
-		logger.Info("Starting domain update job processing")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
-
-		err = json.Unmarshal(job.Info.Data, data)
+		data.SecDNSData, err = policy.PrepareUpdate(data.Name, data.SecDNSData)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
+			r.Logger.Error("Invalid DNSSEC data", log.Fields{types.LogFieldKeys.Error: err})
+			return r.FailOrReschedule(err, nil)
 		}
+	}
 
-		logger.Info("Validated job data for domain update")
+	// check the added nameservers resolve and answer for the domain when the TLD requires it
+	warning, err := service.checkUpdateNameservers(r.Ctx, r.DB, data)
+	if err != nil {
+		r.Logger.Error("Failed to check nameservers", log.Fields{types.LogFieldKeys.Error: err})
+		return r.FailOrReschedule(err, nil)
+	}
 
-		// check if the registry supports host objects
-		hostObjectSupported, err := getBoolAttribute(tx, ctx, "tld.order.host_object_supported", data.AccreditationTld.AccreditationTldId)
-		if err != nil {
-			logger.Error("Failed to fetch host object support attribute", log.Fields{types.LogFieldKeys.Error: err})
-			return err
-		}
+	if warning != "" {
+		r.Logger.Warn("Nameserver check failed, flagging the job", log.Fields{types.LogFieldKeys.Error: warning})
+	}
 
-		msg, err := toDomainUpdateRequest(ctx, service, tx, *data, types.SafeDeref(hostObjectSupported))
-		if err != nil {
-			logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{types.LogFieldKeys.Error: err})
+	// get the builders of the TLD specific extensions
+	builders, err := tldextensions.LoadBuilders(r.Ctx, r.DB, data.AccreditationTld.AccreditationTldId)
+	if err != nil {
+		r.Logger.Error("Failed to get TLD extension builders", log.Fields{types.LogFieldKeys.Error: err})
+		return r.FailOrReschedule(err, nil)
+	}
 
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-		}
+	msg, err := toDomainUpdateRequest(r.Ctx, service, r.DB, *data, types.SafeDeref(hostObjectSupported), builders)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{types.LogFieldKeys.Error: err})
+		return r.FailOrReschedule(err, nil)
+	}
 
-		if msg == nil {
-			logger.Info("No changes detected for domain update, skipping message sending")
-			resMsg := "No changes detected for domain update"
-			job.ResultMessage = &resMsg
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Completed, nil)
-		}
-		queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
-		}
+	if msg == nil {
+		r.Logger.Info("No changes detected for domain update, skipping message sending")
+		resMsg := "No changes detected for domain update"
+		r.Job.ResultMessage = &resMsg
+		return r.SetStatus(types.JobStatus.Completed, nil)
+	}
 
-		err = server.MessageBus().Send(ctx, queue, msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	queue := types.GetTransformQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	err = r.Server.MessageBus().Send(r.Ctx, queue, msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
+	})
 
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
+	// flag the job with the nameserver check warning
+	if warning != "" {
+		r.Job.ResultMessage = &warning
+	}
 
-		return
-	})
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
 
 // findHandleInDomainInfo finds the handle in the domain info response
@@ -166,8 +149,18 @@ func getDomainInfoIfNeeded(ctx context.Context, service *WorkerService, domainIn
 	return nil
 }
 
+// checkUpdateNameservers runs the nameserver check on the nameservers added by the domain update job
+func (service *WorkerService) checkUpdateNameservers(ctx context.Context, db database.Database, data *types.DomainUpdateData) (string, error) {
+	nameservers := make([]types.Nameserver, 0, len(data.Nameservers.Add))
+	for _, ns := range data.Nameservers.Add {
+		nameservers = append(nameservers, *ns)
+	}
+
+	return service.checkNameservers(ctx, db, data.AccreditationTld.AccreditationTldId, data.Name, nameservers)
+}
+
 // toDomainUpdateRequest converts DomainUpdateData to ryinterface's DomainUpdateRequest
-func toDomainUpdateRequest(ctx context.Context, service *WorkerService, db database.Database, data types.DomainUpdateData, hostObjectSupported bool) (domainUpdateRequest *ryinterface.DomainUpdateRequest, err error) {
+func toDomainUpdateRequest(ctx context.Context, service *WorkerService, db database.Database, data types.DomainUpdateData, hostObjectSupported bool, builders []tldextensions.Builder) (domainUpdateRequest *ryinterface.DomainUpdateRequest, err error) {
 	var registrant *string
 	domainUpdateRequest = &ryinterface.DomainUpdateRequest{}
 	domainUpdateRequest.Name = data.Name
@@ -380,6 +373,19 @@ func toDomainUpdateRequest(ctx context.Context, service *WorkerService, db datab
 		return nil, err
 	}
 
+	// add the TLD specific extensions built from the order attributes
+	tldExtensions, err := tldextensions.Build(builders, data.Attributes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, ext := range tldExtensions {
+		if domainUpdateRequest.Extensions == nil {
+			domainUpdateRequest.Extensions = make(map[string]*anypb.Any)
+		}
+		domainUpdateRequest.Extensions[name] = ext
+	}
+
 	// Return nil if the domain update request has no actual changes
 	if domainUpdateRequest.Add == nil &&
 		domainUpdateRequest.Rem == nil &&
@@ -565,7 +571,7 @@ func processLocks(data types.DomainUpdateData, domainInfo *rymessages.DomainInfo
 	}
 
 	for lockName, lockValue := range data.Locks {
-		eppStatus, valid := LocksToEppStatus[lockName]
+		eppStatus, valid := types.LocksToEppStatus[lockName]
 		if !valid {
 			continue
 		}
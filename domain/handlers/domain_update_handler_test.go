@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	messagebus "github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
@@ -244,10 +245,10 @@ func insertDomainUpdateTestJob(db database.Database, domain *model.Domain, inclu
 			AddData: &types.SecDNSUpdateAddData{
 				DSData: &[]types.DSData{
 					{
-						KeyTag:     1,
-						Algorithm:  3,
-						DigestType: 1,
-						Digest:     "new-digest",
+						KeyTag:     60485,
+						Algorithm:  13,
+						DigestType: 2,
+						Digest:     "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A",
 					},
 				},
 			},
@@ -1225,10 +1226,10 @@ func (suite *DomainUpdateTestSuite) TestDomainProvisionHandlerWithSecDNS() {
 				DsSet: &extension.DsDataSet{
 					DsData: []*extension.DsData{
 						{
-							KeyTag:     1,
-							Alg:        3,
-							DigestType: 1,
-							Digest:     "new-digest",
+							KeyTag:     60485,
+							Alg:        13,
+							DigestType: 2,
+							Digest:     "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A",
 						},
 					},
 				},
@@ -1411,3 +1412,91 @@ func (suite *DomainUpdateTestSuite) TestDomainProvisionHandlerWithLocks() {
 		})
 	}
 }
+
+func (suite *DomainUpdateTestSuite) TestDomainUpdateHandlerWithExtensionBuilders() {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		extension  map[string]any
+	}{
+		{
+			name:       "valid attributes",
+			attributes: map[string]string{"nexus.category": "C31", "nexus.country": "CA", "nexus.app_purpose": "P2"},
+			extension:  map[string]any{"category": "C31", "country": "CA", "app_purpose": "P2"},
+		},
+		{
+			name:       "invalid attributes",
+			attributes: map[string]string{"nexus.category": "C32", "nexus.app_purpose": "P2"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			domain, err := insertTestDomainForUpdate(suite.db, fmt.Sprintf("%v.sexy", uuid.NewString()))
+			suite.NoError(err, "Failed to insert test domain")
+
+			reset, err := setTLDSetting(suite.db, *domain.AccreditationTldID, "tld.order.extension_builders", "{nexus}")
+			suite.NoError(err, "Failed to set extension builders")
+			defer reset()
+
+			_, data, err := insertDomainUpdateTestJob(suite.db, domain, false, false, false, nil)
+			suite.NoError(err, "Failed to insert test job")
+
+			data.Attributes = tt.attributes
+			serializedData, err := json.Marshal(data)
+			suite.NoError(err)
+
+			var jobId string
+			sql := `SELECT job_submit(?, ?, ?, ?)`
+			err = suite.db.GetDB().Raw(sql, data.TenantCustomerId, "provision_domain_update", "0268f162-5d83-44d2-894a-ab7578c498fb", serializedData).Scan(&jobId).Error
+			suite.NoError(err, "Failed to insert test job")
+
+			msg := &jobmessage.Notification{
+				JobId:          jobId,
+				Type:           "provision_domain_update",
+				Status:         "status",
+				ReferenceId:    "0268f162-5d83-44d2-894a-ab7578c498fb",
+				ReferenceTable: "1234",
+			}
+
+			expectedContext := context.Background()
+			expectedDestination := types.GetTransformQueue(accreditationName)
+
+			service := NewWorkerService(suite.mb, suite.db, suite.tracer)
+
+			// the extension is compared once decoded, struct fields are not marshalled in a stable order
+			var sent *ryinterface.DomainUpdateRequest
+			suite.mb.On("Send", expectedContext, expectedDestination, mock.AnythingOfType("*ryinterface.DomainUpdateRequest"), mock.Anything).
+				Run(func(args mock.Arguments) { sent = args.Get(2).(*ryinterface.DomainUpdateRequest) }).
+				Return(nil).Maybe()
+			suite.s.On("MessageBus").Return(suite.mb).Maybe()
+			suite.s.On("Headers").Return(map[string]any{})
+			suite.s.On("Context").Return(expectedContext)
+
+			handler := service.DomainUpdateHandler
+			err = handler(suite.s, msg)
+			suite.NoError(err, types.LogMessages.HandleMessageFailed)
+
+			job, err := suite.db.GetJobById(expectedContext, jobId, false)
+			suite.NoError(err, "Failed to get job by id")
+
+			if tt.extension == nil {
+				suite.Equal(types.JobStatus.Failed, *job.Info.JobStatusName)
+				suite.Contains(types.SafeDeref(job.ResultMessage), "nexus extension")
+				suite.mb.AssertNotCalled(suite.T(), "Send")
+				return
+			}
+
+			suite.Equal(types.JobStatus.Processing, *job.Info.JobStatusName)
+			suite.Require().NotNil(sent)
+			suite.Equal(data.Name, sent.Name)
+
+			nexus := new(structpb.Struct)
+			suite.Require().Contains(sent.Extensions, "nexus")
+			suite.NoError(sent.Extensions["nexus"].UnmarshalTo(nexus))
+			suite.Equal(tt.extension, nexus.AsMap())
+		})
+	}
+}
@@ -3,14 +3,16 @@ package handlers
 import (
 	"context"
 	"fmt"
-	"strconv"
 
 	"github.com/alexliesenfeld/health"
 	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/dns"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tldsetting"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
@@ -18,18 +20,12 @@ import (
 	mb "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 )
 
-var LocksToEppStatus = map[string]string{
-	"update":   "clientUpdateProhibited",
-	"delete":   "clientDeleteProhibited",
-	"transfer": "clientTransferProhibited",
-	"renew":    "clientRenewProhibited",
-	"hold":     "clientHold",
-}
-
 type WorkerService struct {
-	db     database.Database
-	bus    messagebus.MessageBus
-	tracer *oteltrace.Tracer
+	db                database.Database
+	bus               messagebus.MessageBus
+	tracer            *oteltrace.Tracer
+	router            *jobhandler.Router
+	nameserverChecker *dns.NameserverChecker
 }
 
 func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *oteltrace.Tracer) *WorkerService {
@@ -40,43 +36,56 @@ func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *o
 	}
 }
 
-func getBoolAttribute(tx database.Database, ctx context.Context, attributeName string, accTldId string) (*bool, error) {
-	// get the TLD setting
-	tldSetting, err := tx.GetTLDSetting(
-		ctx,
-		accTldId,
-		attributeName,
-	)
-	if err != nil {
-		log.Error("Failed to get Tld setting", log.Fields{
-			types.LogFieldKeys.Error: err.Error(),
-		})
-		return nil, err
+// SetNameserverChecker enables the nameserver check of domain create and update for TLDs turning it on
+func (s *WorkerService) SetNameserverChecker(checker *dns.NameserverChecker) {
+	s.nameserverChecker = checker
+}
+
+// checkNameservers runs the nameserver check of the TLD on the nameservers of domain. It returns the check
+// error when the check fails the job, and the check result as warning when it only flags the job. The check
+// error wraps dns.ErrNameserverCheck when a problem is found and is transient when a query failed.
+func (s *WorkerService) checkNameservers(ctx context.Context, db database.Database, accTldId string, domain string, nameservers []types.Nameserver) (warning string, err error) {
+	if s.nameserverChecker == nil || len(nameservers) == 0 {
+		return
+	}
+
+	policy, err := dns.LoadNameserverPolicy(ctx, db, accTldId)
+	if err != nil || policy.Mode == dns.NameserverCheckOff {
+		return
+	}
+
+	checkErr := s.nameserverChecker.Check(ctx, domain, nameservers, policy.Authoritative)
+	if checkErr == nil {
+		return
 	}
 
-	// parse the TLD setting value
-	parsedValue := false
-	if tldSetting == nil {
-		return &parsedValue, nil
+	if policy.Mode == dns.NameserverCheckFail {
+		return "", checkErr
 	}
 
-	parsedValue, err = strconv.ParseBool(tldSetting.Value)
+	return checkErr.Error(), nil
+}
+
+func getBoolAttribute(tx database.Database, ctx context.Context, attributeName string, accTldId string) (*bool, error) {
+	parsedValue, err := tldsetting.Bool(ctx, tx, accTldId, attributeName)
 	if err != nil {
-		log.Error("Failed to parse Tld setting value", log.Fields{
+		log.Error("Failed to get Tld setting", log.Fields{
 			types.LogFieldKeys.Error: err.Error(),
 		})
 		return nil, err
 	}
 
-	return &parsedValue, err
+	return &parsedValue, nil
 }
 
 // RegisterHandlers registers the handlers for the service.
 func (s *WorkerService) RegisterHandlers() {
+	s.router = s.routes()
+
 	// notifications from database
 	s.bus.Register(
 		&job.Notification{}, // go type for the message
-		s.HandlerRouter,     // handler function
+		s.router.Route,      // handler function
 	)
 }
 
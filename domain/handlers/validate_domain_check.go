@@ -1,18 +1,16 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
-	"github.com/tucowsinc/tdp-messages-go/message/job"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface/extension"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/idn"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -20,112 +18,58 @@ import (
 // ValidateDomainCheckHandler This is a callback handler for the validate domain job
 // and is in charge of sending the domain check request to the registry interface
 func (service *WorkerService) ValidateDomainCheckHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "ValidateDomainCheckHandler")
-	defer service.tracer.FinishSpan(span)
-
-	// we need to type-cast the proto.Message to the wanted type
-	request := message.(*job.Notification)
-	jobId := request.GetJobId()
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: jobId,
-	})
-
-	logger.Debug("Starting ValidateDomainCheckHandler for the job")
+	return jobhandler.NewJobHandler("ValidateDomainCheckHandler", service.db, service.tracer, service.validateDomainCheck).
+		Use(jobhandler.RegistryGate(func(data *types.DomainCheckValidationData) string { return data.Accreditation.AccreditationName })).
+		Handle(server, message)
+}
 
-	data := new(types.DomainCheckValidationData)
+func (service *WorkerService) validateDomainCheck(r *jobhandler.JobRequest[types.DomainCheckValidationData]) (err error) {
+	data := r.Data
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, jobId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{types.LogFieldKeys.Error: err})
-			return
-		}
+	r.Logger.Info("Starting validate domain check job processing")
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
+	data.Name, _, err = idn.Normalize(data.Name)
+	if err != nil {
+		r.Logger.Error("Invalid internationalized domain name", log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(err, nil)
+	}
 
-		logger.Info("Starting validate domain check job processing")
+	r.Logger.Info("Validated job data for domain check")
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Submitted) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
+	msg := ryinterface.DomainCheckRequest{
+		Names: []string{data.Name},
+	}
 
-		err = json.Unmarshal(job.Info.Data, data)
+	if data.Price != nil {
+		err = addFeeExtension(data, &msg)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
-
-		logger.Info("Validated job data for domain check")
-
-		msg := ryinterface.DomainCheckRequest{
-			Names: []string{data.Name},
-		}
-
-		if data.Price != nil {
-			err = addFeeExtension(data, &msg)
-			if err != nil {
-				logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{types.LogFieldKeys.Error: err})
-				resMsg := err.Error()
-				job.ResultMessage = &resMsg
-				err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-				if err != nil {
-					logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{types.LogFieldKeys.Error: err})
-				}
-				return
-			}
-		}
-
-		queue := types.GetQueryQueue(data.Accreditation.AccreditationName)
-		headers := map[string]any{
-			"reply_to":       "WorkerJobDomainProvisionUpdate",
-			"correlation_id": jobId,
+			r.Logger.Error(types.LogMessages.ParseJobDataToRegistryRequestFailed, log.Fields{types.LogFieldKeys.Error: err})
+			return r.Fail(err.Error())
 		}
+	}
 
-		err = server.MessageBus().Send(ctx, queue, &msg, headers)
-		if err != nil {
-			logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	queue := types.GetQueryQueue(data.Accreditation.AccreditationName)
+	headers := map[string]any{
+		"reply_to":       "WorkerJobDomainProvisionUpdate",
+		"correlation_id": r.Job.ID,
+	}
 
-		logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
-			types.LogFieldKeys.Domain:               data.Name,
-			types.LogFieldKeys.MessageCorrelationID: jobId,
+	err = r.Server.MessageBus().Send(r.Ctx, queue, &msg, headers)
+	if err != nil {
+		r.Logger.Error(types.LogMessages.MessageSendingToBusFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
 		})
+		return r.FailOrReschedule(joberrors.Transient(err), nil)
+	}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Processing, nil)
-		if err != nil {
-			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
-
-		logger.Info(types.LogMessages.UpdateStatusInDBSuccess)
-
-		return
+	r.Logger.Info(types.LogMessages.MessageSendingToBusSuccess, log.Fields{
+		types.LogFieldKeys.Domain:               data.Name,
+		types.LogFieldKeys.MessageCorrelationID: r.Job.ID,
 	})
+
+	return r.SetStatus(types.JobStatus.Processing, nil)
 }
 
 // addFeeExtension adds the fee extension to the domain check request
@@ -71,7 +71,7 @@ func insertValidateDomainCheckTestJob(db database.Database, isPremiumDomainEnabl
 
 	period := uint32(1)
 	data = &types.DomainCheckValidationData{
-		Name:             "test_domain.com",
+		Name:             "test-domain.com",
 		OrderItemPlanId:  uuid.New().String(),
 		TenantCustomerId: id,
 		Accreditation: types.Accreditation{
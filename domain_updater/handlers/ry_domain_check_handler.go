@@ -52,7 +52,9 @@ func (service *WorkerService) RyDomainCheckHandler(server messagebus.Server, mes
 
 		logger.Info("Starting response processing for domain check job")
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
+		// the job is still dispatching when the response arrives before the worker recorded the send
+		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) &&
+			job.StatusID != tx.GetJobStatusId(types.JobStatus.Dispatching) {
 			logger.Error(types.LogMessages.UnexpectedJobStatus, log.Fields{
 				types.LogFieldKeys.Status: job.Info.JobStatusName,
 			})
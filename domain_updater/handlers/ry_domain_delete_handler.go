@@ -2,11 +2,9 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
-	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	"google.golang.org/protobuf/proto"
 
@@ -17,6 +15,8 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -24,138 +24,96 @@ import (
 // RyDomainDeleteHandler receives the responses from the registry interface
 // and updates the database
 func (service *WorkerService) RyDomainDeleteHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyDomainDeleteHandler")
-	defer service.tracer.FinishSpan(span)
+	return jobhandler.NewJobHandler("RyDomainDeleteHandler", service.db, service.tracer, service.ryDomainDelete).
+		ForRegistryResponse().
+		Deduplicate(service.dedup).
+		Handle(server, message)
+}
 
-	correlationId := server.Envelope().CorrelationId
+func (service *WorkerService) ryDomainDelete(r *jobhandler.JobRequest[types.DomainDeleteData]) (err error) {
+	data := r.Data
+	response := r.Message.(*ryinterface.DomainDeleteResponse)
 
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: correlationId,
+	r.Logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
+		types.LogFieldKeys.Response: response.String(),
 	})
 
-	response := message.(*ryinterface.DomainDeleteResponse)
+	r.Logger.Info("Starting response processing for domain delete job")
 
-	logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
-		types.LogFieldKeys.Response: response.String(),
-	})
+	registryResponse := response.GetRegistryResponse()
 
-	data := new(types.DomainDeleteData)
+	jrd := types.JobResultData{Message: r.Message}
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, correlationId, true)
+	outcome := epp_utils.ResolveJobOutcome(r.Ctx, r.Tx, epp_utils.ResultPolicyInput{
+		JobType:           *r.Job.Info.JobTypeName,
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, r.Logger)
+
+	if registryResponse.GetIsSuccess() && outcome.IsSuccess() {
+		r.Logger.Info("Domain was successfully deleted on the registry backend")
+
+		// Check if the domain is in the redemption grace period
+		data, err = service.IsDomainInRedemptionGracePeriod(r.Ctx, r.Tx, registryResponse, data, r.Logger)
 		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
+			r.Logger.Error("Error checking domain RGP status", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
-			return
-		}
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
-
-		logger.Info("Starting response processing for domain delete job")
-
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
+			resMsg := "Failed to retrieve the domain RGP status"
+			r.Job.ResultMessage = &resMsg
+			return r.SetStatus(types.JobStatus.Failed, &jrd)
 		}
 
-		err = json.Unmarshal(job.Info.Data, data)
+		// Process the domain delete response
+		err = ProcessRyDomainDeleteResponse(r.Ctx, r.Tx, registryResponse, data, r.Job, r.Logger)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+			r.Logger.Error("Failed to process domain delete response", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
 
 			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
+			r.Job.ResultMessage = &resMsg
+			return r.SetStatus(types.JobStatus.Failed, &jrd)
 		}
 
-		registryResponse := response.GetRegistryResponse()
-
-		jrd := types.JobResultData{Message: message}
-
-		if registryResponse.GetIsSuccess() {
-			logger.Info("Domain was successfully deleted on the registry backend")
-
-			// Check if the domain is in the redemption grace period
-			data, err = service.IsDomainInRedemptionGracePeriod(ctx, tx, registryResponse, data, logger)
-			if err != nil {
-				logger.Error("Error checking domain RGP status", log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-
-				resMsg := "Failed to retrieve the domain RGP status"
-				job.ResultMessage = &resMsg
-				return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			}
-
-			// Process the domain delete response
-			err = ProcessRyDomainDeleteResponse(ctx, tx, registryResponse, data, job, logger)
-			if err != nil {
-				logger.Error("Failed to process domain delete response", log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-
-				resMsg := err.Error()
-				job.ResultMessage = &resMsg
-				return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			}
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.SetStatus(outcome.Status, &jrd)
+		if err != nil {
+			return
+		}
+	} else if outcome.IsSuccess() {
+		// the policy accepts the error, as when the domain does not exist in the registry
+		r.Logger.Info("Domain delete error accepted by EPP result policy", log.Fields{
+			types.LogFieldKeys.Domain:  data.Name,
+			types.LogFieldKeys.EppCode: registryResponse.GetEppCode(),
+		})
 
-			// Set the job status to completed
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		} else if registryResponse.GetEppCode() == types.EppCode.ObjectDoesNotExist {
-			// If the domain does not exist, complete the job.
-			logger.Info("Domain does not exist in the registry", log.Fields{
-				types.LogFieldKeys.Domain: data.Name,
-			})
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.SetStatus(outcome.Status, &jrd)
+		if err != nil {
+			return
+		}
+	} else {
+		r.Logger.Error("Failed to delete domain in registry", log.Fields{
+			types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
+			types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
+			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
+		})
 
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		} else {
-			logger.Error("Failed to delete domain in registry", log.Fields{
-				types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
-				types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
-				types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.FailOrReschedule(joberrors.FromRegistryResponse(registryResponse), &jrd)
+		if err != nil {
+			r.Logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
 			})
-
-			epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
+			return
 		}
+	}
 
-		logger.Info(types.LogMessages.JobProcessingCompleted)
+	r.Logger.Info(types.LogMessages.JobProcessingCompleted)
 
-		return
-	})
+	return
 }
 
 // ProcessRyDomainDeleteResponse processes the response from the registry interface for domain delete and updates the database
@@ -196,7 +154,7 @@ func (service *WorkerService) IsDomainInRedemptionGracePeriod(ctx context.Contex
 		// domain was not in add grace period therefore must enter redemption grace period after deletion
 		data.InRedemptionGracePeriod = true
 	} else {
-		if registryResponse.GetEppCode() == 1000 {
+		if registryResponse.GetEppCode() == types.EppCode.Success {
 			// domain was in add grace period prior to deletion, response code states domain is deleted from registry immediately; no redemption grace period
 			data.InRedemptionGracePeriod = false
 		} else {
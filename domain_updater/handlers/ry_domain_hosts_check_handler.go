@@ -55,9 +55,13 @@ func (service *WorkerService) RyDomainHostsCheckHandler(server messagebus.Server
 
 	jrd := types.JobResultData{Message: response}
 
-	jobStatus := types.JobStatus.Completed
+	outcome := epp_utils.ResolveJobOutcome(ctx, tx, epp_utils.ResultPolicyInput{
+		JobType:           types.SafeDeref(job.Info.JobTypeName),
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, logger)
 
-	if registryResponse.GetIsSuccess() {
+	if registryResponse.GetIsSuccess() && outcome.IsSuccess() {
 		// Check if the domain is belong to the same registrar
 		if response.Clid != data.Accreditation.RegistrarID {
 			logger.Info("Domain does not belong to the registrar", log.Fields{
@@ -142,10 +146,11 @@ func (service *WorkerService) RyDomainHostsCheckHandler(server messagebus.Server
 				return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
 			}
 		}
-	} else if registryResponse.GetEppCode() == types.EppCode.ObjectDoesNotExist {
-		// If the domain does not exist, complete the job.
+	} else if outcome.IsSuccess() {
+		// the policy accepts the error when the domain does not exist in the registry, complete the job
 		logger.Info("Domain does not exist in the registry", log.Fields{
-			types.LogFieldKeys.Domain: data.Name,
+			types.LogFieldKeys.Domain:  data.Name,
+			types.LogFieldKeys.EppCode: registryResponse.GetEppCode(),
 		})
 
 		// Set order metadata to indicate that the domain was not found
@@ -171,12 +176,13 @@ func (service *WorkerService) RyDomainHostsCheckHandler(server messagebus.Server
 		})
 
 		// Fail the job to fail delete order
-		epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
+		outcome.SetJobResult(registryResponse, job, &jrd)
 		return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
 	}
 
 	// Set job status
-	err = tx.SetJobStatus(ctx, job, jobStatus, &jrd)
+	outcome.SetJobResult(registryResponse, job, &jrd)
+	err = tx.SetJobStatus(ctx, job, outcome.Status, &jrd)
 	if err != nil {
 		logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 			types.LogFieldKeys.Error: err,
@@ -38,7 +38,9 @@ func (service *WorkerService) RyDomainInfoRouter(server messagebus.Server, messa
 
 		logger.Info("Starting response processing for domain info job")
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
+		// the job is still dispatching when the response arrives before the worker recorded the send
+		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) &&
+			job.StatusID != tx.GetJobStatusId(types.JobStatus.Dispatching) {
 			log.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
 				types.LogFieldKeys.Status: job.Info.JobStatusName,
 			})
@@ -2,9 +2,7 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	// import required to parse domain create response
@@ -14,6 +12,8 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -21,123 +21,81 @@ import (
 // RyDomainProvisionHandler receives the responses from the registry interface
 // and updates the database
 func (service *WorkerService) RyDomainProvisionHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyDomainProvisionHandler")
-	defer service.tracer.FinishSpan(span)
-
-	correlationId := server.Envelope().CorrelationId
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: correlationId,
-	})
+	return jobhandler.NewJobHandler("RyDomainProvisionHandler", service.db, service.tracer, service.ryDomainProvision).
+		ForRegistryResponse().
+		Deduplicate(service.dedup).
+		Handle(server, message)
+}
 
-	response := message.(*ryinterface.DomainCreateResponse)
+func (service *WorkerService) ryDomainProvision(r *jobhandler.JobRequest[types.DomainData]) (err error) {
+	data := r.Data
+	response := r.Message.(*ryinterface.DomainCreateResponse)
 
-	logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
+	r.Logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
 		types.LogFieldKeys.Response: response.String(),
 	})
 
-	data := new(types.DomainData)
+	r.Logger.Info("Starting response processing for domain create job")
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, correlationId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	registryResponse := response.GetRegistryResponse()
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
+	jrd := types.JobResultData{Message: r.Message}
 
-		logger.Info("Starting response processing for domain create job")
+	outcome := epp_utils.ResolveJobOutcome(r.Ctx, r.Tx, epp_utils.ResultPolicyInput{
+		JobType:           *r.Job.Info.JobTypeName,
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, r.Logger)
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
+	if outcome.IsSuccess() {
+		r.Logger.Info("Domain successfully provisioned on registry backend", log.Fields{
+			types.LogFieldKeys.Domain: data.Name,
+		})
 
-		err = json.Unmarshal(job.Info.Data, data)
+		// Process the domain provision response
+		err = ProcessRyDomainProvisionResponse(r.Ctx, response, r.Job, r.Tx, r.Logger)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+			r.Logger.Error("Failed to process domain provision response", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
 
 			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
+			r.Job.ResultMessage = &resMsg
+			return r.SetStatus(types.JobStatus.Failed, &jrd)
 		}
 
-		registryResponse := response.GetRegistryResponse()
-
-		jrd := types.JobResultData{Message: message}
-
-		if registryResponse.GetIsSuccess() {
-			logger.Info("Domain successfully provisioned on registry backend", log.Fields{
+		if outcome.Status == types.JobStatus.CompletedConditionally {
+			r.Logger.Info("Domain provision completed conditionally", log.Fields{
 				types.LogFieldKeys.Domain: data.Name,
 			})
+		}
 
-			// Process the domain provision response
-			err = ProcessRyDomainProvisionResponse(ctx, response, job, tx, logger)
-			if err != nil {
-				logger.Error("Failed to process domain provision response", log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-
-				resMsg := err.Error()
-				job.ResultMessage = &resMsg
-				return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			}
-
-			if registryResponse.EppCode == types.EppCode.Pending {
-				logger.Info("Domain provision completed conditionally", log.Fields{
-					types.LogFieldKeys.Domain: data.Name,
-				})
-
-				return tx.SetJobStatus(ctx, job, types.JobStatus.CompletedConditionally, &jrd)
-			}
-
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		} else {
-			logger.Error("Failed to provision domain in registry", log.Fields{
-				types.LogFieldKeys.Domain:      data.Name,
-				types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
-				types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
-				types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
-			})
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.SetStatus(outcome.Status, &jrd)
+		if err != nil {
+			return
+		}
+	} else {
+		r.Logger.Error("Failed to provision domain in registry", log.Fields{
+			types.LogFieldKeys.Domain:      data.Name,
+			types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
+			types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
+			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
+		})
 
-			epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.FailOrReschedule(joberrors.FromRegistryResponse(registryResponse), &jrd)
+		if err != nil {
+			r.Logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return
 		}
+	}
 
-		logger.Info(types.LogMessages.JobProcessingCompleted)
+	r.Logger.Info(types.LogMessages.JobProcessingCompleted)
 
-		return
-	})
+	return
 }
 
 // ProcessRyDomainProvisionResponse processes the domain provisioning response and updates the database
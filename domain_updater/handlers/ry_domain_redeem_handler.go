@@ -12,6 +12,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -53,7 +54,13 @@ func (service *WorkerService) RyDomainRedeemHandler(server messagebus.Server, me
 
 	jrd := types.JobResultData{Message: message}
 
-	if registryResponse.GetIsSuccess() {
+	outcome := epp_utils.ResolveJobOutcome(ctx, tx, epp_utils.ResultPolicyInput{
+		JobType:           *job.Info.JobTypeName,
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, logger)
+
+	if outcome.IsSuccess() {
 		logger.Info("Domain successfully redeemed in registry", log.Fields{
 			types.LogFieldKeys.Domain: data.Name,
 		})
@@ -64,7 +71,8 @@ func (service *WorkerService) RyDomainRedeemHandler(server messagebus.Server, me
 			service.handleDomainRedeemExpiryUpdate(ctx, logger, tx, job, data)
 		}
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+		outcome.SetJobResult(registryResponse, job, &jrd)
+		err = tx.SetJobStatus(ctx, job, outcome.Status, &jrd)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
@@ -78,8 +86,8 @@ func (service *WorkerService) RyDomainRedeemHandler(server messagebus.Server, me
 			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
 		})
 
-		epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
+		outcome.SetJobResult(registryResponse, job, &jrd)
+		err = joberrors.FailOrReschedule(ctx, tx, job, joberrors.FromRegistryResponse(registryResponse), &jrd, logger)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
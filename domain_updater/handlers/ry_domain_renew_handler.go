@@ -1,16 +1,14 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -18,132 +16,84 @@ import (
 // RyDomainRenewHandler receives the responses from the registry interface
 // and updates the database
 func (service *WorkerService) RyDomainRenewHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyDomainRenewHandler")
-	defer service.tracer.FinishSpan(span)
+	return jobhandler.NewJobHandler("RyDomainRenewHandler", service.db, service.tracer, service.ryDomainRenew).
+		ForRegistryResponse().
+		Deduplicate(service.dedup).
+		Handle(server, message)
+}
 
-	correlationId := server.Envelope().CorrelationId
+func (service *WorkerService) ryDomainRenew(r *jobhandler.JobRequest[types.DomainRenewData]) (err error) {
+	data := r.Data
+	response := r.Message.(*ryinterface.DomainRenewResponse)
 
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: correlationId,
+	r.Logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
+		types.LogFieldKeys.Response: response.String(),
 	})
 
-	response := message.(*ryinterface.DomainRenewResponse)
+	r.Logger.Info("Starting response processing for domain renew job")
 
-	logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
-		types.LogFieldKeys.Response: response.String(),
-	})
+	registryResponse := response.GetRegistryResponse()
 
-	data := new(types.DomainRenewData)
+	jrd := types.JobResultData{Message: r.Message}
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, correlationId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	outcome := epp_utils.ResolveJobOutcome(r.Ctx, r.Tx, epp_utils.ResultPolicyInput{
+		JobType:           *r.Job.Info.JobTypeName,
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, r.Logger)
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
+	if outcome.IsSuccess() {
+		expiryDate := response.GetExpiryDate().AsTime()
 
-		logger.Info("Starting response processing for domain renew job")
+		r.Logger.Info("Domain renewed successfully in registry", log.Fields{
+			types.LogFieldKeys.Domain: data.Name,
+			"ry_expiry_date":          expiryDate.String(),
+		})
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
+		renewData := model.ProvisionDomainRenew{
+			ID:           *r.Job.Info.ReferenceID,
+			RyExpiryDate: &expiryDate,
+			RyCltrid:     &registryResponse.EppCltrid,
 		}
 
-		err = json.Unmarshal(job.Info.Data, data)
+		err = r.Tx.UpdateProvisionDomainRenew(r.Ctx, &renewData)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+			r.Logger.Error("Failed to update provision_domain_renew", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
 
 			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
+			r.Job.ResultMessage = &resMsg
+			err = r.SetStatus(types.JobStatus.Failed, &jrd)
 			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
 			}
 			return
 		}
 
-		registryResponse := response.GetRegistryResponse()
-
-		jrd := types.JobResultData{Message: message}
-
-		if registryResponse.GetIsSuccess() {
-			expiryDate := response.GetExpiryDate().AsTime()
-
-			logger.Info("Domain renewed successfully in registry", log.Fields{
-				types.LogFieldKeys.Domain: data.Name,
-				"ry_expiry_date":          expiryDate.String(),
-			})
-
-			renewData := model.ProvisionDomainRenew{
-				ID:           *job.Info.ReferenceID,
-				RyExpiryDate: &expiryDate,
-				RyCltrid:     &registryResponse.EppCltrid,
-			}
-
-			err = tx.UpdateProvisionDomainRenew(ctx, &renewData)
-			if err != nil {
-				logger.Error("Failed to update provision_domain_renew", log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-
-				resMsg := err.Error()
-				job.ResultMessage = &resMsg
-				err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-				if err != nil {
-					logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-						types.LogFieldKeys.Error: err,
-					})
-				}
-				return
-			}
-
-			if registryResponse.EppCode == types.EppCode.Pending {
-				err = tx.SetJobStatus(ctx, job, types.JobStatus.CompletedConditionally, &jrd)
-			} else {
-				err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
-			}
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.SetStatus(outcome.Status, &jrd)
+		if err != nil {
+			return
+		}
+	} else {
+		r.Logger.Error("Failed to renew domain in registry", log.Fields{
+			types.LogFieldKeys.Domain:      data.Name,
+			types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
+			types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
+			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
+		})
 
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		} else {
-			logger.Error("Failed to renew domain in registry", log.Fields{
-				types.LogFieldKeys.Domain:      data.Name,
-				types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
-				types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
-				types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.FailOrReschedule(joberrors.FromRegistryResponse(registryResponse), &jrd)
+		if err != nil {
+			r.Logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
 			})
-
-			epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
+			return
 		}
+	}
 
-		logger.Info(types.LogMessages.JobProcessingCompleted)
+	r.Logger.Info(types.LogMessages.JobProcessingCompleted)
 
-		return
-	})
+	return
 }
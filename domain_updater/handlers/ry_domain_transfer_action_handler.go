@@ -11,6 +11,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -51,12 +52,15 @@ func (service *WorkerService) RyDomainTransferActionHandler(server messagebus.Se
 
 	jrd := types.JobResultData{Message: message}
 
-	if registryResponse.GetIsSuccess() {
-		if registryResponse.EppCode != types.EppCode.Success {
-			return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-		}
+	outcome := epp_utils.ResolveJobOutcome(ctx, tx, epp_utils.ResultPolicyInput{
+		JobType:           types.SafeDeref(job.Info.JobTypeName),
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, logger)
 
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+	if outcome.IsSuccess() {
+		outcome.SetJobResult(registryResponse, job, &jrd)
+		err = tx.SetJobStatus(ctx, job, outcome.Status, &jrd)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
@@ -70,8 +74,8 @@ func (service *WorkerService) RyDomainTransferActionHandler(server messagebus.Se
 			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
 		})
 
-		epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
+		outcome.SetJobResult(registryResponse, job, &jrd)
+		err = joberrors.FailOrReschedule(ctx, tx, job, joberrors.FromRegistryResponse(registryResponse), &jrd, logger)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
@@ -55,7 +55,8 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	job := &model.Job{
 		ID: "test-job-id",
 		Info: &model.VJob{
-			Data: sqlx.JSONText(`{"name": "test-domain.sexy"}`),
+			JobTypeName: types.ToPointer("provision_domain_transfer_away"),
+			Data:        sqlx.JSONText(`{"name": "test-domain.sexy"}`),
 		},
 	}
 
@@ -69,6 +70,7 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	service := NewWorkerService(suite.mb, suite.db, suite.t)
 	suite.s.On("Context").Return(expectedContext)
 	suite.s.On("Headers").Return(nil)
+	suite.db.On("GetEppResultPolicies", expectedContext, "provision_domain_transfer_away", int32(1000)).Return([]model.VEppResultPolicy(nil), nil)
 	suite.db.On("SetJobStatus", expectedContext, job, types.JobStatus.Completed, mock.Anything).Return(nil)
 
 	err := service.RyDomainTransferActionHandler(suite.s, response, job, suite.db, log.GetLogger())
@@ -81,7 +83,8 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	job := &model.Job{
 		ID: "test-job-id",
 		Info: &model.VJob{
-			Data: sqlx.JSONText(`{"name": "test-domain.sexy"}`),
+			JobTypeName: types.ToPointer("provision_domain_transfer_away"),
+			Data:        sqlx.JSONText(`{"name": "test-domain.sexy"}`),
 		},
 	}
 
@@ -94,6 +97,10 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	service := NewWorkerService(suite.mb, suite.db, suite.t)
 	suite.s.On("Context").Return(expectedContext)
 	suite.s.On("Headers").Return(nil)
+	// a pending transfer action fails the job by policy
+	suite.db.On("GetEppResultPolicies", expectedContext, "provision_domain_transfer_away", int32(1001)).Return([]model.VEppResultPolicy{
+		{JobTypeName: "provision_domain_transfer_away", EppCode: 1001, JobStatusName: types.JobStatus.Failed},
+	}, nil)
 	suite.db.On("SetJobStatus", expectedContext, job, types.JobStatus.Failed, mock.Anything).Return(nil)
 
 	err := service.RyDomainTransferActionHandler(suite.s, response, job, suite.db, log.GetLogger())
@@ -106,7 +113,8 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	job := &model.Job{
 		ID: "test-job-id",
 		Info: &model.VJob{
-			Data: sqlx.JSONText(`{"name": "test-domain.sexy"}`),
+			JobTypeName: types.ToPointer("provision_domain_transfer_away"),
+			Data:        sqlx.JSONText(`{"name": "test-domain.sexy"}`),
 		},
 	}
 
@@ -121,6 +129,7 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	service := NewWorkerService(suite.mb, suite.db, suite.t)
 	suite.s.On("Headers").Return(nil)
 	suite.s.On("Context").Return(expectedContext)
+	suite.db.On("GetEppResultPolicies", expectedContext, "provision_domain_transfer_away", int32(2304)).Return([]model.VEppResultPolicy(nil), nil)
 	suite.db.On("SetJobStatus", expectedContext, job, types.JobStatus.Failed, mock.Anything).Return(nil)
 
 	err := service.RyDomainTransferActionHandler(suite.s, response, job, suite.db, log.GetLogger())
@@ -159,7 +168,8 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	job := &model.Job{
 		ID: "test-job-id",
 		Info: &model.VJob{
-			Data: sqlx.JSONText(`{"name": "test-domain.sexy"}`),
+			JobTypeName: types.ToPointer("provision_domain_transfer_away"),
+			Data:        sqlx.JSONText(`{"name": "test-domain.sexy"}`),
 		},
 	}
 
@@ -173,6 +183,7 @@ func (suite *RyDomainTransferActionHandlerTestSuite) TestRyDomaintransferActionH
 	service := NewWorkerService(suite.mb, suite.db, suite.t)
 	suite.s.On("Context").Return(expectedContext)
 	suite.s.On("Headers").Return(nil)
+	suite.db.On("GetEppResultPolicies", expectedContext, "provision_domain_transfer_away", int32(1000)).Return([]model.VEppResultPolicy(nil), nil)
 	suite.db.On("SetJobStatus", expectedContext, job, types.JobStatus.Completed, mock.Anything).Return(nil)
 
 	err := service.RyDomainTransferActionHandler(suite.s, response, job, suite.db, log.GetLogger())
@@ -16,7 +16,9 @@ import (
 
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/dnssec"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -119,7 +121,7 @@ func (service *WorkerService) RyDomainTransferInHandler(server messagebus.Server
 		})
 
 		epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
+		err = joberrors.FailOrReschedule(ctx, tx, job, joberrors.FromRegistryResponse(registryResponse), &jrd, logger)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
@@ -162,6 +164,18 @@ func processExtensions(ctx context.Context, tx database.Database, pdti *model.Pr
 				}
 				pdti.SecdnsType = types.ToPointer("ds_data")
 
+				warnInvalidSecDNS(ctx, tx, pdti, func(p dnssec.Policy) (errs []error) {
+					for _, dsData := range secDnsData.DsSet.DsData {
+						errs = append(errs, p.ValidateDSData(pdti.DomainName, types.DSData{
+							KeyTag:     int(dsData.KeyTag),
+							Algorithm:  int(dsData.Alg),
+							DigestType: int(dsData.DigestType),
+							Digest:     dsData.Digest,
+						}))
+					}
+					return
+				})
+
 				if err := tx.CreateDsDataSet(ctx, dbDsDataSet); err != nil {
 					return fmt.Errorf("failed to create ds data set: %w", err)
 				}
@@ -180,6 +194,18 @@ func processExtensions(ctx context.Context, tx database.Database, pdti *model.Pr
 
 				pdti.SecdnsType = types.ToPointer("key_data")
 
+				warnInvalidSecDNS(ctx, tx, pdti, func(p dnssec.Policy) (errs []error) {
+					for _, keyData := range secDnsData.KeySet.KeyData {
+						errs = append(errs, p.ValidateKeyData(types.KeyData{
+							Flags:     int(keyData.Flags),
+							Protocol:  int(keyData.Protocol),
+							Algorithm: int(keyData.Alg),
+							PublicKey: keyData.PubKey,
+						}))
+					}
+					return
+				})
+
 				if err := tx.CreateKeyDataSet(ctx, dbKeyDataSet); err != nil {
 					return fmt.Errorf("failed to create key data set: %w", err)
 				}
@@ -203,3 +229,25 @@ func processExtensions(ctx context.Context, tx database.Database, pdti *model.Pr
 
 	return nil
 }
+
+// warnInvalidSecDNS logs DNSSEC data of a transferred domain not allowed by the TLD dnssec policy. The data
+// is kept as the registry holds it, but it would be rejected if sent again with a domain update.
+func warnInvalidSecDNS(ctx context.Context, tx database.Database, pdti *model.ProvisionDomainTransferIn, validate func(dnssec.Policy) []error) {
+	policy, err := dnssec.LoadPolicy(ctx, tx, pdti.AccreditationTldID)
+	if err != nil {
+		log.Warn("Failed to get DNSSEC policy of transferred domain", log.Fields{
+			types.LogFieldKeys.Domain: pdti.DomainName,
+			types.LogFieldKeys.Error:  err,
+		})
+		return
+	}
+
+	for _, err := range validate(policy) {
+		if err != nil {
+			log.Warn("Transferred domain has DNSSEC data not allowed by the TLD", log.Fields{
+				types.LogFieldKeys.Domain: pdti.DomainName,
+				types.LogFieldKeys.Error:  err,
+			})
+		}
+	}
+}
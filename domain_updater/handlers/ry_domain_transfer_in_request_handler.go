@@ -12,6 +12,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -53,12 +54,19 @@ func (service *WorkerService) RyDomainTransferInRequestHandler(server messagebus
 
 	jrd := types.JobResultData{Message: message}
 
-	if registryResponse.GetIsSuccess() {
+	outcome := epp_utils.ResolveJobOutcome(ctx, tx, epp_utils.ResultPolicyInput{
+		JobType:           types.SafeDeref(job.Info.JobTypeName),
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, logger)
+
+	if outcome.IsSuccess() {
 		logger.Info("Transfer in request was successfully created for domain in registry", log.Fields{
 			types.LogFieldKeys.Domain: data.Name,
 		})
 
-		if registryResponse.EppCode == types.EppCode.Pending {
+		// the transfer is pending until the registry or the losing registrar acts on it
+		if outcome.Status == types.JobStatus.CompletedConditionally {
 			err = ProcessRyDomainTransferInResponse(ctx, response, job, tx, logger)
 			if err != nil {
 				logger.Error("Failed to process domain transfer in response", log.Fields{
@@ -70,8 +78,8 @@ func (service *WorkerService) RyDomainTransferInRequestHandler(server messagebus
 				return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
 			}
 
-			// Set the job status to completed conditionally
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.CompletedConditionally, &jrd)
+			outcome.SetJobResult(registryResponse, job, &jrd)
+			err = tx.SetJobStatus(ctx, job, outcome.Status, &jrd)
 			if err != nil {
 				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 					types.LogFieldKeys.Error: err,
@@ -90,8 +98,8 @@ func (service *WorkerService) RyDomainTransferInRequestHandler(server messagebus
 			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
 		})
 
-		epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
+		outcome.SetJobResult(registryResponse, job, &jrd)
+		err = joberrors.FailOrReschedule(ctx, tx, job, joberrors.FromRegistryResponse(registryResponse), &jrd, logger)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
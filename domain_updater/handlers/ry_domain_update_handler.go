@@ -11,6 +11,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -52,7 +53,13 @@ func (service *WorkerService) RyDomainUpdateHandler(server messagebus.Server, me
 
 	jrd := types.JobResultData{Message: message}
 
-	if registryResponse.GetIsSuccess() {
+	outcome := epp_utils.ResolveJobOutcome(ctx, tx, epp_utils.ResultPolicyInput{
+		JobType:           *job.Info.JobTypeName,
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.Name,
+	}, registryResponse, logger)
+
+	if outcome.IsSuccess() {
 		logger.Info("Domain successfully updated on the registry backend", log.Fields{
 			types.LogFieldKeys.Domain: data.Name,
 		})
@@ -81,17 +88,8 @@ func (service *WorkerService) RyDomainUpdateHandler(server messagebus.Server, me
 			}
 		}
 
-		if registryResponse.EppCode == types.EppCode.Pending {
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.CompletedConditionally, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
-			return
-		}
-
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+		outcome.SetJobResult(registryResponse, job, &jrd)
+		err = tx.SetJobStatus(ctx, job, outcome.Status, &jrd)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
@@ -105,8 +103,8 @@ func (service *WorkerService) RyDomainUpdateHandler(server messagebus.Server, me
 			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
 		})
 
-		epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-		err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
+		outcome.SetJobResult(registryResponse, job, &jrd)
+		err = joberrors.FailOrReschedule(ctx, tx, job, joberrors.FromRegistryResponse(registryResponse), &jrd, logger)
 		if err != nil {
 			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
@@ -39,13 +39,21 @@ func (service *WorkerService) RyDomainUpdateRouter(server messagebus.Server, mes
 
 		logger.Info("Starting response processing for domain update job")
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
+		// the job is still dispatching when the response arrives before the worker recorded the send
+		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) &&
+			job.StatusID != tx.GetJobStatusId(types.JobStatus.Dispatching) {
 			logger.Error(types.LogMessages.UnexpectedJobStatus, log.Fields{
 				types.LogFieldKeys.Status: job.Info.JobStatusName,
 			})
 			return
 		}
 
+		// the message is only claimed once the job is in the expected status, within the same transaction
+		claimed, err := service.dedup.Claim(ctx, tx, server, message, logger)
+		if err != nil || !claimed {
+			return
+		}
+
 		err = service.RyDomainUpdateRequestRouter(server, message, job, tx, logger)
 		if err != nil {
 			logger.Error(types.LogMessages.HandleMessageFailed, log.Fields{
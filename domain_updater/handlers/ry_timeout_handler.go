@@ -3,9 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
@@ -16,6 +19,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -95,6 +99,8 @@ func JobReconcilerRouter(service *WorkerService, ctx context.Context, message *t
 		err = redeemDomain(service, ctx, message, job, logger)
 	case "provision_domain_transfer_in":
 		err = transferInInfoDomain(service, ctx, message, job, logger)
+	case "provision_domain_update":
+		err = updateDomain(service, ctx, message, job, logger)
 	default:
 		err = fmt.Errorf("unsupported job type: %s", jobType)
 	}
@@ -636,6 +642,126 @@ func transferInInfoDomain(service *WorkerService, ctx context.Context, message *
 	return
 }
 
+// updateDomain completes the domain update job if the registry already applied the changes,
+// otherwise the job is rescheduled
+func updateDomain(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	// Get the job result data
+	jrd := types.JobResultData{Message: message}
+
+	// Get the database instance
+	db := service.db
+
+	// Get the update data from the job
+	data := new(types.DomainUpdateData)
+	err = json.Unmarshal(job.Info.Data, data)
+	if err != nil {
+		logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+	accName := data.Accreditation.AccreditationName
+
+	// Complete the job if the registry already applied the update
+	domainInfoResp, err := service.getDomainInfo(ctx, data.Name, accName)
+	if err == nil && domainInfoResp.GetRegistryResponse().GetEppCode() == types.EppCode.Success && domainUpdateApplied(data, domainInfoResp) {
+		logger.Info("Domain update already applied in registry", log.Fields{
+			types.LogFieldKeys.Domain:        data.Name,
+			types.LogFieldKeys.Accreditation: accName,
+		})
+
+		jrd := types.JobResultData{Message: domainInfoResp}
+
+		// Set the job status to completed
+		err = db.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+		if err != nil {
+			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+		}
+
+		return
+	}
+
+	logger.Info("Domain update not found in registry, retrying", log.Fields{
+		types.LogFieldKeys.Domain: data.Name,
+	})
+
+	// Reschedule the job, it is failed once out of retries
+	epp_utils.SetJobErrorFromRegistryErrorResponse(message, job, &jrd)
+	err = joberrors.FailOrReschedule(ctx, db, job, joberrors.Transient(errors.New(*getJobResultMsg(*job.Info.JobTypeName))), &jrd, logger)
+	if err != nil {
+		logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+
+	return
+}
+
+// domainUpdateApplied tells if the domain info reflects every change of the update.
+// Changes which can not be read back from the registry (auth info, registrant, secDNS) are never considered applied.
+func domainUpdateApplied(data *types.DomainUpdateData, domainInfo *ryinterface.DomainInfoResponse) bool {
+	if data.Pw != nil || data.SecDNSData != nil {
+		return false
+	}
+
+	if data.Contacts != nil {
+		contacts := make(map[string]struct{}, len(domainInfo.GetContacts()))
+		for _, c := range domainInfo.GetContacts() {
+			contacts[c.GetId()] = struct{}{}
+		}
+
+		for _, added := range [][]types.DomainContact{data.Contacts.All, data.Contacts.Add} {
+			for _, c := range added {
+				if _, ok := contacts[c.Handle]; c.Type == "registrant" || !ok {
+					return false
+				}
+			}
+		}
+
+		for _, c := range data.Contacts.Rem {
+			if c.Type == "registrant" {
+				continue
+			}
+
+			if _, ok := contacts[c.Handle]; c.Handle == "" || ok {
+				return false
+			}
+		}
+	}
+
+	nameservers := make(map[string]struct{}, len(domainInfo.GetNameservers()))
+	for _, ns := range domainInfo.GetNameservers() {
+		nameservers[strings.ToLower(ns)] = struct{}{}
+	}
+
+	for _, ns := range data.Nameservers.Add {
+		if _, ok := nameservers[strings.ToLower(ns.Name)]; !ok {
+			return false
+		}
+	}
+
+	for _, ns := range data.Nameservers.Rem {
+		if _, ok := nameservers[strings.ToLower(ns.Name)]; ok {
+			return false
+		}
+	}
+
+	for lockName, lockValue := range data.Locks {
+		eppStatus, valid := types.LocksToEppStatus[lockName]
+		if !valid {
+			continue
+		}
+
+		if slices.Contains(domainInfo.GetStatuses(), eppStatus) != lockValue {
+			return false
+		}
+	}
+
+	return true
+}
+
 // getJobResultMsg returns the job result message based on the job type
 func getJobResultMsg(jobType string) *string {
 	var msg string
@@ -651,6 +777,8 @@ func getJobResultMsg(jobType string) *string {
 		msg = "Timeout error occurred while deleting domain in registry"
 	case "provision_domain_redeem", "provision_domain_redeem_report":
 		msg = "Timeout error occurred while redeeming domain in registry"
+	case "provision_domain_update":
+		msg = "Timeout error occurred while updating domain in registry"
 	default:
 		msg = "Failed to process job"
 	}
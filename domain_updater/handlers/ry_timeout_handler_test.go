@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
 	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
@@ -18,6 +19,8 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
 	"github.com/tucowsinc/tdp-messages-go/message"
+	"github.com/tucowsinc/tdp-messages-go/message/common"
+	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 )
 
 type RyTimeoutHandlerTestSuite struct {
@@ -163,3 +166,87 @@ func (suite *RyTimeoutHandlerTestSuite) TestRyTimeoutHandler() {
 		})
 	}
 }
+
+func TestDomainUpdateApplied(t *testing.T) {
+	domainInfo := &ryinterface.DomainInfoResponse{
+		Nameservers: []string{"ns1.example.com", "ns2.example.com"},
+		Statuses:    []string{types.EPPStatusCode.ClientTransferProhibited},
+		Contacts: []*common.DomainContact{
+			{Type: common.DomainContact_ADMIN, Id: "admin-handle"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		data    types.DomainUpdateData
+		applied bool
+	}{
+		{
+			name:    "no changes",
+			applied: true,
+		},
+		{
+			name:    "nameservers applied",
+			data:    domainUpdateDataWithNameservers([]string{"NS1.example.com"}, []string{"ns3.example.com"}),
+			applied: true,
+		},
+		{
+			name: "nameserver not added",
+			data: domainUpdateDataWithNameservers([]string{"ns3.example.com"}, nil),
+		},
+		{
+			name: "nameserver not removed",
+			data: domainUpdateDataWithNameservers(nil, []string{"ns2.example.com"}),
+		},
+		{
+			name:    "locks applied",
+			data:    types.DomainUpdateData{Locks: map[string]bool{"transfer": true, "delete": false, "unknown": true}},
+			applied: true,
+		},
+		{
+			name: "lock not applied",
+			data: types.DomainUpdateData{Locks: map[string]bool{"update": true}},
+		},
+		{
+			name: "contacts applied",
+			data: types.DomainUpdateData{Contacts: &types.DomainUpdateContactData{
+				Add: []types.DomainContact{{Type: "admin", Handle: "admin-handle"}},
+				Rem: []types.DomainContact{{Type: "tech", Handle: "tech-handle"}},
+			}},
+			applied: true,
+		},
+		{
+			name: "contact not added",
+			data: types.DomainUpdateData{Contacts: &types.DomainUpdateContactData{
+				Add: []types.DomainContact{{Type: "tech", Handle: "tech-handle"}},
+			}},
+		},
+		{
+			name: "registrant change can not be verified",
+			data: types.DomainUpdateData{Contacts: &types.DomainUpdateContactData{
+				Add: []types.DomainContact{{Type: "registrant", Handle: "registrant-handle"}},
+			}},
+		},
+		{
+			name: "auth info change can not be verified",
+			data: types.DomainUpdateData{Pw: types.ToPointer("secret")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.applied, domainUpdateApplied(&tt.data, domainInfo))
+		})
+	}
+}
+
+func domainUpdateDataWithNameservers(add, rem []string) (data types.DomainUpdateData) {
+	for _, name := range add {
+		data.Nameservers.Add = append(data.Nameservers.Add, &types.Nameserver{Name: name})
+	}
+	for _, name := range rem {
+		data.Nameservers.Rem = append(data.Nameservers.Rem, &types.Nameserver{Name: name})
+	}
+
+	return
+}
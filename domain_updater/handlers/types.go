@@ -17,6 +17,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
 	mb "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -25,6 +26,7 @@ type WorkerService struct {
 	db     database.Database
 	bus    messagebus.MessageBus
 	tracer *oteltrace.Tracer
+	dedup  *jobhandler.Deduplicator
 }
 
 func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *oteltrace.Tracer) *WorkerService {
@@ -32,6 +34,7 @@ func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *o
 		db:     db,
 		bus:    bus,
 		tracer: tracer,
+		dedup:  jobhandler.NewDeduplicator(db, jobhandler.DefaultDedupTTL),
 	}
 }
 
@@ -10,11 +10,13 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
 	"github.com/tucowsinc/tdp-workers-go/host/handlers"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lifecycle"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 )
@@ -45,7 +47,6 @@ func main() {
 			types.LogFieldKeys.Error: err,
 		})
 	}
-	defer messagebusServer.Finalize()
 
 	db, err := database.New(cfg.PostgresPoolConfig(), cfg.GetDBLogLevel())
 
@@ -56,13 +57,20 @@ func main() {
 	}
 	defer db.Close()
 
-	service := handlers.NewWorkerService(messagebusServer, db, tracer)
+	// recover handler panics, failing the job of the message
+	recoverer := jobhandler.NewRecoverer(db, cfg.HandlerPanicAck)
+
+	// drain in-flight handlers on shutdown
+	lc := lifecycle.New(cfg.GetShutdownTimeout())
+
+	service := handlers.NewWorkerService(lc.Bus(recoverer.Bus(messagebusServer)), db, tracer)
 	service.RegisterHandlers()
+	service.Routes().Validate(context.Background(), db, cfg.RmqQueueName)
 
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range service.HealthChecks(cfg) {
+		for _, check := range append(service.HealthChecks(cfg), lc.HealthCheck()) {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -88,11 +96,12 @@ func main() {
 		Prefetch: &cfg.RmqPrefetchCount,
 	}
 
-	err = messagebusServer.Consume(queues, consumerOptions) // block until the messagebus quits
+	err = lc.Run(func() error { return messagebusServer.Consume(queues, consumerOptions) }, nil, func() { messagebusServer.Finalize() }) // block until the messagebus quits or is drained on shutdown
 	if err != nil {
-		log.Fatal(types.LogMessages.ConsumingQueuesFailed, log.Fields{
+		log.Error(types.LogMessages.ConsumingQueuesFailed, log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
+		return
 	}
 
 	log.Info(types.LogMessages.WorkerTerminated)
@@ -12,6 +12,7 @@ import (
 	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
 	mb "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -20,6 +21,7 @@ type WorkerService struct {
 	db     database.Database
 	bus    messagebus.MessageBus
 	tracer *oteltrace.Tracer
+	router *jobhandler.Router
 }
 
 func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *oteltrace.Tracer) *WorkerService {
@@ -32,10 +34,12 @@ func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *o
 
 // RegisterHandlers registers the handlers for the service.
 func (s *WorkerService) RegisterHandlers() {
+	s.router = s.routes()
+
 	// notifications from database
 	s.bus.Register(
 		&job.Notification{}, // go type for the message
-		s.HandlerRouter,     // handler function
+		s.router.Route,      // handler function
 	)
 }
 
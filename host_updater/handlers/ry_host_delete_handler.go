@@ -2,10 +2,8 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
@@ -14,6 +12,8 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -21,132 +21,81 @@ import (
 // RyHostDeleteHandler receives the responses from the registry interface
 // and deletes the database
 func (service *WorkerService) RyHostDeleteHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyHostDeleteHandler")
-	defer service.tracer.FinishSpan(span)
-
-	correlationId := server.Envelope().CorrelationId
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: correlationId,
-	})
+	return jobhandler.NewJobHandler("RyHostDeleteHandler", service.db, service.tracer, service.ryHostDelete).
+		ForRegistryResponse().
+		Deduplicate(service.dedup).
+		Handle(server, message)
+}
 
-	response := message.(*ryinterface.HostDeleteResponse)
+func (service *WorkerService) ryHostDelete(r *jobhandler.JobRequest[types.HostDeleteData]) (err error) {
+	data := r.Data
+	response := r.Message.(*ryinterface.HostDeleteResponse)
 
-	logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
+	r.Logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
 		types.LogFieldKeys.Response: response.String(),
 	})
 
-	data := new(types.HostDeleteData)
+	r.Logger.Info("Starting response processing for host delete job")
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, correlationId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	registryResponse := response.GetRegistryResponse()
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
+	jrd := types.JobResultData{Message: r.Message}
 
-		logger.Info("Starting response processing for host delete job")
+	outcome := epp_utils.ResolveJobOutcome(r.Ctx, r.Tx, epp_utils.ResultPolicyInput{
+		JobType:           *r.Job.Info.JobTypeName,
+		AccreditationName: data.Accreditation.AccreditationName,
+		ObjectName:        data.HostName,
+	}, registryResponse, r.Logger)
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
-			return
-		}
+	if outcome.IsSuccess() {
+		r.Logger.Info("Host deleted successfully on the registry backend", log.Fields{
+			types.LogFieldKeys.Host:    data.HostName,
+			types.LogFieldKeys.EppCode: registryResponse.GetEppCode(),
+		})
 
-		err = json.Unmarshal(job.Info.Data, data)
+		outcome.SetJobResult(registryResponse, r.Job, &jrd)
+		err = r.SetStatus(outcome.Status, &jrd)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
 			return
 		}
-
-		registryResponse := response.GetRegistryResponse()
-
-		jrd := types.JobResultData{Message: message}
-
-		if registryResponse.GetIsSuccess() {
-			logger.Info("Host deleted successfully on the registry backend", log.Fields{
+	} else {
+		if data.HostDeleteRenameAllowed {
+			r.Logger.Info("Host not deleted; renaming host instead", log.Fields{
 				types.LogFieldKeys.Host: data.HostName,
 			})
 
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+			err = RyRenameHost(r.Ctx, r.Server.MessageBus(), r.Tx, data, r.Job, jrd, r.Logger)
 			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				r.Logger.Error("Error renaming host", log.Fields{
 					types.LogFieldKeys.Error: err,
 				})
-				return
+
+				resMsg := "Failed to delete and rename host in registry"
+				r.Job.ResultMessage = &resMsg
+				return r.SetStatus(types.JobStatus.Failed, &jrd)
 			}
-		} else if registryResponse.EppCode == types.EppCode.ObjectDoesNotExist {
-			logger.Info("Host doesn't exist, treating as success", log.Fields{
-				types.LogFieldKeys.Host: data.HostName,
+		} else {
+			r.Logger.Error("Cannot delete host", log.Fields{
+				types.LogFieldKeys.Host:        data.HostName,
+				types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
+				types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
+				types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
 			})
 
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+			outcome.SetJobResult(registryResponse, r.Job, &jrd)
+			err = r.FailOrReschedule(joberrors.FromRegistryResponse(registryResponse), &jrd)
 			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				r.Logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 					types.LogFieldKeys.Error: err,
 				})
 				return
 			}
-		} else {
-			if data.HostDeleteRenameAllowed {
-				logger.Info("Host not deleted; renaming host instead", log.Fields{
-					types.LogFieldKeys.Host: data.HostName,
-				})
-
-				err = RyRenameHost(ctx, server.MessageBus(), tx, data, job, jrd, logger)
-				if err != nil {
-					logger.Error("Error renaming host", log.Fields{
-						types.LogFieldKeys.Error: err,
-					})
-
-					resMsg := "Failed to delete and rename host in registry"
-					job.ResultMessage = &resMsg
-					return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-				}
-			} else {
-				logger.Error("Cannot delete host", log.Fields{
-					types.LogFieldKeys.Host:        data.HostName,
-					types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
-					types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
-					types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
-				})
-
-				epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-				err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-				if err != nil {
-					logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-						types.LogFieldKeys.Error: err,
-					})
-					return
-				}
-			}
 		}
+	}
 
-		logger.Info(types.LogMessages.JobProcessingCompleted)
+	r.Logger.Info(types.LogMessages.JobProcessingCompleted)
 
-		return
-	})
+	return
 }
 
 // RyRenameHost renames the host in the registry
@@ -1,15 +1,13 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
-	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -17,101 +15,54 @@ import (
 // RyHostUpdateHandler receives the responses from the registry interface
 // and updates the database
 func (service *WorkerService) RyHostUpdateHandler(server messagebus.Server, message proto.Message) error {
-	ctx := server.Context()
-	headers := server.Headers()
-	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyHostUpdateHandler")
-	defer service.tracer.FinishSpan(span)
-
-	correlationId := server.Envelope().CorrelationId
-
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.CorrelationID: correlationId,
-	})
+	return jobhandler.NewJobHandler("RyHostUpdateHandler", service.db, service.tracer, service.ryHostUpdate).
+		ForRegistryResponse().
+		Deduplicate(service.dedup).
+		Handle(server, message)
+}
 
-	response := message.(*ryinterface.HostUpdateResponse)
+func (service *WorkerService) ryHostUpdate(r *jobhandler.JobRequest[types.HostUpdateData]) (err error) {
+	data := r.Data
+	response := r.Message.(*ryinterface.HostUpdateResponse)
 
-	logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
+	r.Logger.Debug(types.LogMessages.ReceivedResponseFromRY, log.Fields{
 		types.LogFieldKeys.Response: response.String(),
 	})
 
-	data := new(types.HostUpdateData)
+	r.Logger.Info("Starting response processing for host update job")
 
-	return service.db.WithTransaction(func(tx database.Database) (err error) {
-		job, err := tx.GetJobById(ctx, correlationId, true)
-		if err != nil {
-			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
-				types.LogFieldKeys.Error: err,
-			})
-			return
-		}
+	registryResponse := response.GetRegistryResponse()
 
-		logger = logger.CreateChildLogger(log.Fields{
-			types.LogFieldKeys.LogID:   uuid.NewString(),
-			types.LogFieldKeys.JobType: *job.Info.JobTypeName,
-		})
+	jrd := types.JobResultData{Message: r.Message}
 
-		logger.Info("Starting response processing for host update job")
+	if registryResponse.GetIsSuccess() {
+		r.Logger.Info("Host was successfully updated on the registry backend", log.Fields{
+			types.LogFieldKeys.Host: data.HostName,
+		})
 
-		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Processing) {
-			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
-				types.LogFieldKeys.Status: job.Info.JobStatusName,
-			})
+		err = r.SetStatus(types.JobStatus.Completed, &jrd)
+		if err != nil {
 			return
 		}
+	} else {
+		r.Logger.Error("Failed to update host in registry", log.Fields{
+			types.LogFieldKeys.Host:        data.HostName,
+			types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
+			types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
+			types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
+		})
 
-		err = json.Unmarshal(job.Info.Data, data)
+		epp_utils.SetJobErrorFromRegistryResponse(registryResponse, r.Job, &jrd)
+		err = r.FailOrReschedule(joberrors.FromRegistryResponse(registryResponse), &jrd)
 		if err != nil {
-			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+			r.Logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
-
-			resMsg := err.Error()
-			job.ResultMessage = &resMsg
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-			}
 			return
 		}
+	}
 
-		registryResponse := response.GetRegistryResponse()
-
-		jrd := types.JobResultData{Message: message}
-
-		if registryResponse.GetIsSuccess() {
-			logger.Info("Host was successfully updated on the registry backend", log.Fields{
-				types.LogFieldKeys.Host: data.HostName,
-			})
-
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		} else {
-			logger.Error("Failed to update host in registry", log.Fields{
-				types.LogFieldKeys.Host:        data.HostName,
-				types.LogFieldKeys.EppCode:     registryResponse.GetEppCode(),
-				types.LogFieldKeys.EppMessage:  registryResponse.GetEppMessage(),
-				types.LogFieldKeys.XmlResponse: registryResponse.GetXml(),
-			})
-
-			epp_utils.SetJobErrorFromRegistryResponse(registryResponse, job, &jrd)
-			err = tx.SetJobStatus(ctx, job, types.JobStatus.Failed, &jrd)
-			if err != nil {
-				logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
-					types.LogFieldKeys.Error: err,
-				})
-				return
-			}
-		}
-
-		logger.Info(types.LogMessages.JobProcessingCompleted)
+	r.Logger.Info(types.LogMessages.JobProcessingCompleted)
 
-		return
-	})
+	return
 }
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	tcwire "github.com/tucowsinc/tdp-messages-go/message"
+	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
+	"github.com/tucowsinc/tdp-shared-go/logger"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/epp_utils"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	mb "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// RyTimeoutHandler reconciles host jobs whose registry command timed out
+func (service *WorkerService) RyTimeoutHandler(server messagebus.Server, message proto.Message) (err error) {
+	ctx := server.Context()
+	headers := server.Headers()
+	span, ctx := service.tracer.CreateSpanFromHeaders(ctx, headers, "RyTimeoutHandler")
+	defer service.tracer.FinishSpan(span)
+
+	correlationId := server.Envelope().CorrelationId
+
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.CorrelationID: correlationId,
+	})
+
+	response := message.(*tcwire.ErrorResponse)
+
+	logger.Info("Received timeout error response from RY interface", log.Fields{
+		types.LogFieldKeys.Response: response.GetMessage(),
+	})
+
+	job, err := service.db.GetJobById(ctx, correlationId, true)
+	if err != nil {
+		logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	jobType := *job.Info.JobTypeName
+
+	logger = logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.LogID:   uuid.NewString(),
+		types.LogFieldKeys.JobType: jobType,
+	})
+
+	// a timeout received again, or after the job was already finished, must not reschedule the job
+	if job.StatusID != service.db.GetJobStatusId(types.JobStatus.Processing) &&
+		job.StatusID != service.db.GetJobStatusId(types.JobStatus.Dispatching) {
+		logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
+			types.LogFieldKeys.Status: job.Info.JobStatusName,
+		})
+		return
+	}
+
+	logger.Info("Starting reconciliation process for job")
+
+	err = JobReconcilerRouter(service, ctx, response, job, logger)
+	if err != nil {
+		logger.Error("Reconciliation process failed for job", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+
+		job.ResultMessage = getJobResultMsg(jobType)
+		err = service.db.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
+		if err != nil {
+			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return
+		}
+	}
+
+	logger.Info("Reconciliation process was successful for job")
+
+	return
+}
+
+// JobReconcilerRouter routes the job to the appropriate reconciliation handler
+func JobReconcilerRouter(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	jobType := *job.Info.JobTypeName
+
+	switch jobType {
+	case "provision_host_create":
+		err = createHost(service, ctx, message, job, logger)
+	case "provision_host_update":
+		err = updateHost(service, ctx, message, job, logger)
+	case "provision_host_delete":
+		err = deleteHost(service, ctx, message, job, logger)
+	default:
+		err = fmt.Errorf("unsupported job type: %s", jobType)
+	}
+
+	return
+}
+
+// createHost completes the host create job if the host already exists in the registry
+func createHost(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	data := new(types.HostData)
+	err = json.Unmarshal(job.Info.Data, data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	hostInfoResp, err := service.getHostInfo(ctx, data.HostName, data.Accreditation.AccreditationName)
+	applied := err == nil &&
+		hostInfoResp.GetRegistryResponse().GetEppCode() == types.EppCode.Success &&
+		hostInfoResp.GetClid() == data.Accreditation.RegistrarID
+
+	return reconcileJob(service, ctx, message, job, applied, hostInfoResp, logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.Host: data.HostName,
+	}))
+}
+
+// updateHost completes the host update job if the host already has the new addresses in the registry
+func updateHost(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	data := new(types.HostUpdateData)
+	err = json.Unmarshal(job.Info.Data, data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	hostInfoResp, err := service.getHostInfo(ctx, data.HostName, data.Accreditation.AccreditationName)
+	applied := err == nil &&
+		hostInfoResp.GetRegistryResponse().GetEppCode() == types.EppCode.Success &&
+		sameAddresses(hostInfoResp.GetAddresses(), data.HostNewAddrs)
+
+	return reconcileJob(service, ctx, message, job, applied, hostInfoResp, logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.Host: data.HostName,
+	}))
+}
+
+// deleteHost completes the host delete job if the host no longer exists in the registry
+func deleteHost(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, logger logger.ILogger) (err error) {
+	data := new(types.HostDeleteData)
+	err = json.Unmarshal(job.Info.Data, data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	hostInfoResp, err := service.getHostInfo(ctx, data.HostName, data.Accreditation.AccreditationName)
+	applied := err == nil && hostInfoResp.GetRegistryResponse().GetEppCode() == types.EppCode.ObjectDoesNotExist
+
+	return reconcileJob(service, ctx, message, job, applied, hostInfoResp, logger.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.Host: data.HostName,
+	}))
+}
+
+// reconcileJob completes the job when the registry already applied the command,
+// otherwise the job is rescheduled and failed once out of retries
+func reconcileJob(service *WorkerService, ctx context.Context, message *tcwire.ErrorResponse, job *model.Job, applied bool, hostInfoResp *ryinterface.HostInfoResponse, logger logger.ILogger) (err error) {
+	db := service.db
+
+	if applied {
+		logger.Info("Host command already applied in registry")
+
+		jrd := types.JobResultData{Message: hostInfoResp}
+		err = db.SetJobStatus(ctx, job, types.JobStatus.Completed, &jrd)
+		if err != nil {
+			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+		}
+
+		return
+	}
+
+	logger.Info("Host command not applied in registry, retrying")
+
+	jrd := types.JobResultData{Message: message}
+	epp_utils.SetJobErrorFromRegistryErrorResponse(message, job, &jrd)
+	err = joberrors.FailOrReschedule(ctx, db, job, joberrors.Transient(errors.New(*getJobResultMsg(*job.Info.JobTypeName))), &jrd, logger)
+	if err != nil {
+		logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+
+	return
+}
+
+// sameAddresses tells if both lists hold the same IP addresses regardless of order and notation
+func sameAddresses(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	addrs := make(map[string]int, len(a))
+	for _, addr := range a {
+		addrs[normalizeAddress(addr)]++
+	}
+
+	for _, addr := range b {
+		addr = normalizeAddress(addr)
+		if addrs[addr] == 0 {
+			return false
+		}
+		addrs[addr]--
+	}
+
+	return true
+}
+
+func normalizeAddress(addr string) string {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip.String()
+	}
+
+	return addr
+}
+
+// getJobResultMsg returns the job result message based on the job type
+func getJobResultMsg(jobType string) *string {
+	var msg string
+
+	switch jobType {
+	case "provision_host_create":
+		msg = "Timeout error occurred while creating host in registry"
+	case "provision_host_update":
+		msg = "Timeout error occurred while updating host in registry"
+	case "provision_host_delete":
+		msg = "Timeout error occurred while deleting host in registry"
+	default:
+		msg = "Failed to process job"
+	}
+
+	return &msg
+}
+
+func (service *WorkerService) getHostInfo(ctx context.Context, hostName string, accName string) (*ryinterface.HostInfoResponse, error) {
+	hostInfoMsg := &ryinterface.HostInfoRequest{Name: hostName}
+	response, err := mb.Call(ctx, service.bus, types.GetQueryQueue(accName), hostInfoMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	hostInfoResp, ok := response.(*ryinterface.HostInfoResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type received for host info response: %T", response)
+	}
+
+	return hostInfoResp, nil
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
+	"github.com/tucowsinc/tdp-messages-go/message"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+func TestSameAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want bool
+	}{
+		{name: "empty", want: true},
+		{name: "same order", a: []string{"192.168.0.1", "192.168.0.2"}, b: []string{"192.168.0.1", "192.168.0.2"}, want: true},
+		{name: "different order", a: []string{"192.168.0.2", "192.168.0.1"}, b: []string{"192.168.0.1", "192.168.0.2"}, want: true},
+		{name: "ipv6 notation", a: []string{"2001:db8:0:0:0:0:0:1"}, b: []string{"2001:DB8::1"}, want: true},
+		{name: "missing address", a: []string{"192.168.0.1"}, b: []string{"192.168.0.1", "192.168.0.2"}},
+		{name: "different address", a: []string{"192.168.0.1", "192.168.0.3"}, b: []string{"192.168.0.1", "192.168.0.2"}},
+		{name: "duplicates", a: []string{"192.168.0.1", "192.168.0.1"}, b: []string{"192.168.0.1", "192.168.0.2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sameAddresses(tt.a, tt.b))
+		})
+	}
+}
+
+func TestGetJobResultMsg(t *testing.T) {
+	assert.Equal(t, "Timeout error occurred while updating host in registry", *getJobResultMsg("provision_host_update"))
+	assert.Equal(t, "Failed to process job", *getJobResultMsg("provision_domain_update"))
+}
+
+func TestRyTimeoutHandlerUnexpectedStatus(t *testing.T) {
+	cfg := config.Config{LogLevel: "mute"}
+	log.Setup(cfg)
+
+	tracer, _, err := tracing.Setup(context.Background(), cfg)
+	assert.NoError(t, err)
+
+	db := &database.MockDatabase{}
+	s := &mocks.MockMessageBusServer{}
+
+	s.On("Context").Return(context.Background())
+	s.On("Headers").Return(nil)
+	s.On("Envelope").Return(&message.TcWire{CorrelationId: "job-id"})
+
+	job := &model.Job{
+		ID:       "job-id",
+		StatusID: "failed-id",
+		Info: &model.VJob{
+			JobTypeName:   types.ToPointer("provision_host_update"),
+			JobStatusName: types.ToPointer(types.JobStatus.Failed),
+		},
+	}
+	db.On("GetJobById", mock.Anything, "job-id", true).Return(job, nil)
+	db.On("GetJobStatusId", types.JobStatus.Processing).Return("processing-id")
+	db.On("GetJobStatusId", types.JobStatus.Dispatching).Return("dispatching-id")
+
+	service := NewWorkerService(&mocks.MockMessageBus{}, db, tracer)
+
+	// the failed job is neither reconciled nor rescheduled
+	err = service.RyTimeoutHandler(s, &message.ErrorResponse{Code: message.ErrorResponse_TIMEOUT})
+	assert.NoError(t, err)
+
+	db.AssertExpectations(t)
+	db.AssertNotCalled(t, "RescheduleJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	db.AssertNotCalled(t, "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
-	defaulthandlers "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
 	mb "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 )
 
@@ -16,6 +16,7 @@ type WorkerService struct {
 	db     database.Database
 	bus    messagebus.MessageBus
 	tracer *oteltrace.Tracer
+	dedup  *jobhandler.Deduplicator
 }
 
 func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *oteltrace.Tracer) *WorkerService {
@@ -23,6 +24,7 @@ func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *o
 		db:     db,
 		bus:    bus,
 		tracer: tracer,
+		dedup:  jobhandler.NewDeduplicator(db, jobhandler.DefaultDedupTTL),
 	}
 }
 
@@ -50,7 +52,7 @@ func (s *WorkerService) RegisterHandlers() {
 
 	s.bus.Register(
 		&message.ErrorResponse{},
-		defaulthandlers.ErrorResponseHandler(s.db),
+		s.RyErrorResponseRouter(),
 	)
 }
 
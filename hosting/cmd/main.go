@@ -6,12 +6,14 @@ import (
 	"time"
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
 	"github.com/tucowsinc/tdp-workers-go/hosting/handlers"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/dns"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lifecycle"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
@@ -47,13 +49,20 @@ func main() {
 		log.Fatal("Failed to create DNS resolver", log.Fields{"error": err})
 	}
 
-	service := handlers.NewWorkerService(messageBusServer, db, resolver, cfg)
+	// recover handler panics, failing the job of the message
+	recoverer := jobhandler.NewRecoverer(db, cfg.HandlerPanicAck)
+
+	// drain in-flight handlers on shutdown
+	lc := lifecycle.New(cfg.GetShutdownTimeout())
+
+	service := handlers.NewWorkerService(lc.Bus(recoverer.Bus(messageBusServer)), db, resolver, cfg)
 	service.RegisterHandlers()
+	service.Routes().Validate(context.Background(), db, cfg.RmqQueueName)
 
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range service.HealthChecks(cfg) {
+		for _, check := range append(service.HealthChecks(cfg), lc.HealthCheck()) {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -79,10 +88,11 @@ func main() {
 		Prefetch: &cfg.RmqPrefetchCount,
 	}
 
-	if err = messageBusServer.Consume(queues, consumerOptions); err != nil {
-		log.Fatal(types.LogMessages.ConsumingQueuesFailed, log.Fields{
+	if err = lc.Run(func() error { return messageBusServer.Consume(queues, consumerOptions) }, nil, func() { messageBusServer.Finalize() }); err != nil {
+		log.Error(types.LogMessages.ConsumingQueuesFailed, log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
+		return
 	}
 
 	log.Info(types.LogMessages.WorkerTerminated)
@@ -15,6 +15,7 @@ import (
 	"github.com/tucowsinc/tdp-shared-go/dns"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
@@ -41,6 +42,7 @@ type WorkerService struct {
 	ACMEChallengeDomain string
 	CertBotApiTimeout   time.Duration
 	resolver            dns.IDnsResolver
+	router              *jobhandler.Router
 }
 
 // NewWorkerService creates a new instance of WorkerService and configures
@@ -81,10 +83,12 @@ func NewWorkerService(bus messagebus.MessageBus, db database.Database, resolver
 
 // RegisterHandlers registers the handlers for the service.
 func (s *WorkerService) RegisterHandlers() {
+	s.router = s.routes()
+
 	// notifications from database
 	s.bus.Register(
 		&job.Notification{},
-		s.HandlerRouter,
+		s.router.Route,
 	)
 }
 
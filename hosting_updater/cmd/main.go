@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
 	"github.com/tucowsinc/tdp-workers-go/hosting_updater/handlers"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lifecycle"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -34,15 +36,25 @@ func main() {
 	}
 	defer db.Close()
 
-	sqsConsumer := handlers.SetupSQSConsumer(context.Background(), cfg)
+	// the consumer stops handling messages once ctx is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	service := handlers.NewWorkerService(sqsConsumer, db)
+	sqsConsumer := handlers.SetupSQSConsumer(ctx, cfg)
+
+	// recover handler panics, failing the job of the message
+	recoverer := jobhandler.NewRecoverer(db, cfg.HandlerPanicAck)
+
+	// drain in-flight handlers on shutdown
+	lc := lifecycle.New(cfg.GetShutdownTimeout())
+
+	service := handlers.NewWorkerService(lc.Consumer(recoverer.Consumer(sqsConsumer)), db)
 	service.RegisterHandlers()
 
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range service.HealthChecks() {
+		for _, check := range append(service.HealthChecks(), lc.HealthCheck()) {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -62,7 +74,7 @@ func main() {
 	log.Info(types.LogMessages.ConsumingQueuesStarted, log.Fields{
 		types.LogFieldKeys.Queue: cfg.AWSSqsQueueName,
 	})
-	sqsConsumer.Consume()
+	lc.Run(func() error { sqsConsumer.Consume(); return nil }, sqsConsumer.Stop, cancel) // block until the consumer is drained on shutdown
 
 	log.Info(types.LogMessages.WorkerTerminated)
 }
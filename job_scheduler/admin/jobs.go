@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+type actionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// routeJob routes /admin/jobs/{id} and /admin/jobs/{id}/{action}
+func (s *Server) routeJob(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/jobs/"), "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.showJob(w, r, parts[0])
+	case len(parts) == 2:
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.operateJob(w, r, parts[0], parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := database.JobFilter{
+		Status:           query.Get("status"),
+		Type:             query.Get("type"),
+		TenantCustomerID: query.Get("tenant_customer_id"),
+		ReferenceID:      query.Get("reference_id"),
+		Limit:            DefaultListLimit,
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > MaxListLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", MaxListLimit))
+			return
+		}
+		filter.Limit = n
+	}
+
+	jobs, err := ListJobs(r.Context(), s.db, filter, actorFromContext(r.Context()))
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+func (s *Server) showJob(w http.ResponseWriter, r *http.Request, jobId string) {
+	details, err := ShowJob(r.Context(), s.db, jobId, actorFromContext(r.Context()))
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, details)
+}
+
+func (s *Server) operateJob(w http.ResponseWriter, r *http.Request, jobId string, action string) {
+	var req actionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	actor := actorFromContext(r.Context())
+
+	job, err := OperateJob(r.Context(), s.db, jobId, action, actor, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnknownAction):
+			writeError(w, http.StatusNotFound, "not found")
+		case errors.Is(err, ErrReasonRequired):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, database.ErrNotFound):
+			writeError(w, http.StatusNotFound, "job not found")
+		case errors.Is(err, ErrConflict):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			s.internalError(w, err)
+		}
+		return
+	}
+
+	log.Info("Admin operation done on job", log.Fields{
+		types.LogFieldKeys.JobID: job.ID,
+		"action":                 action,
+		"actor":                  actor,
+	})
+
+	writeJSON(w, http.StatusOK, job.Info)
+}
+
+func (s *Server) internalError(w http.ResponseWriter, err error) {
+	log.Error("Error handling admin API request", log.Fields{
+		types.LogFieldKeys.Error: err,
+	})
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
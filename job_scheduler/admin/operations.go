@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+const (
+	ActionList    = "list"
+	ActionShow    = "show"
+	ActionRequeue = "requeue"
+	ActionFail    = "fail"
+	ActionCancel  = "cancel"
+)
+
+var (
+	ErrUnknownAction  = errors.New("unknown action")
+	ErrReasonRequired = errors.New("reason is required")
+	// ErrConflict is returned when the action is not allowed in the current job status
+	ErrConflict = errors.New("conflict")
+)
+
+// JobDetails is a job with its child jobs and admin audit entries
+type JobDetails struct {
+	Job      *model.VJob           `json:"job"`
+	Children []model.VJob          `json:"children"`
+	Audit    []model.JobAdminAudit `json:"audit"`
+}
+
+// ListJobs returns jobs matching the filter, the listing is recorded in the audit trail
+func ListJobs(ctx context.Context, db database.Database, filter database.JobFilter, actor string) (jobs []model.VJob, err error) {
+	jobs, err = db.GetJobs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing jobs: %w", err)
+	}
+
+	details, _ := json.Marshal(filter)
+
+	err = db.CreateJobAdminAudit(ctx, &model.JobAdminAudit{Action: ActionList, Actor: actor, Details: details})
+	if err != nil {
+		return nil, fmt.Errorf("error writing admin audit: %w", err)
+	}
+
+	return
+}
+
+// ShowJob returns the job with its child jobs and audit entries, the lookup is recorded in the audit trail
+func ShowJob(ctx context.Context, db database.Database, jobId string, actor string) (*JobDetails, error) {
+	job, err := db.GetJobById(ctx, jobId, false)
+	if err != nil {
+		return nil, fmt.Errorf("error getting job: %w", err)
+	}
+
+	children, err := db.GetChildJobs(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting child jobs: %w", err)
+	}
+
+	audit, err := db.GetJobAdminAudits(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting job admin audit: %w", err)
+	}
+
+	err = db.CreateJobAdminAudit(ctx, &model.JobAdminAudit{JobID: &job.ID, Action: ActionShow, Actor: actor})
+	if err != nil {
+		return nil, fmt.Errorf("error writing admin audit: %w", err)
+	}
+
+	return &JobDetails{Job: job.Info, Children: children, Audit: audit}, nil
+}
+
+// OperateJob runs the action on a non final job, or requeues a failed job, and records it in the audit trail,
+// in one transaction.
+// The job row is locked with NOWAIT so it fails while a worker is updating the job.
+func OperateJob(ctx context.Context, db database.Database, jobId string, action string, actor string, reason string) (job *model.Job, err error) {
+	var operate func(ctx context.Context, tx database.Database, job *model.Job, reason string) error
+
+	switch action {
+	case ActionRequeue:
+		operate = requeueJob
+	case ActionFail:
+		operate = failJob
+	case ActionCancel:
+		operate = cancelJob
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, action)
+	}
+
+	if reason == "" && action != ActionRequeue {
+		return nil, ErrReasonRequired
+	}
+
+	err = db.WithTransaction(func(tx database.Database) (err error) {
+		job, err = tx.GetJobById(ctx, jobId, true)
+		if err != nil {
+			return
+		}
+
+		// a final job can only be requeued when it failed
+		status := types.SafeDeref(job.Info.JobStatusName)
+		if types.SafeDeref(job.Info.JobStatusIsFinal) && (action != ActionRequeue || status != types.JobStatus.Failed) {
+			return fmt.Errorf("%w: job is %s", ErrConflict, status)
+		}
+
+		audit := &model.JobAdminAudit{
+			JobID:          &job.ID,
+			Action:         action,
+			Actor:          actor,
+			PreviousStatus: job.Info.JobStatusName,
+		}
+
+		err = operate(ctx, tx, job, reason)
+		if err != nil {
+			return
+		}
+
+		if reason != "" {
+			audit.Reason = &reason
+		}
+		audit.NewStatus = job.Info.JobStatusName
+
+		return tx.CreateJobAdminAudit(ctx, audit)
+	})
+
+	return
+}
+
+// requeueJob submits the job again to be dispatched now, a failed job gets one more attempt
+func requeueJob(ctx context.Context, tx database.Database, job *model.Job, _ string) (err error) {
+	now := time.Now()
+
+	switch *job.Info.JobStatusName {
+	case types.JobStatus.Failed:
+		// a final job is only let through by job_requeue
+		requeued, err := tx.RequeueJob(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		if !requeued {
+			return fmt.Errorf("%w: the parent job or the reference of the failed job is final", ErrConflict)
+		}
+
+		status := types.JobStatus.Submitted
+		job.StartDate = &now
+		job.Info.JobStatusName = &status
+	case types.JobStatus.Submitted:
+		// already submitted, the status trigger won't fire so the job is notified explicitly;
+		// if the start date lands after the transaction time the scheduled trigger dispatches it instead
+		err = tx.SetJobStartDate(ctx, job.ID, now)
+		if err != nil {
+			return
+		}
+
+		_, err = tx.NotifyDueJobs(ctx, []string{job.ID})
+	default:
+		job.StartDate = &now
+		err = tx.SetJobStatus(ctx, job, types.JobStatus.Submitted, nil)
+	}
+
+	return
+}
+
+// failJob fails the job with the given reason without further retries
+func failJob(ctx context.Context, tx database.Database, job *model.Job, reason string) (err error) {
+	err = tx.DisableJobRetries(ctx, job.ID)
+	if err != nil {
+		return
+	}
+
+	job.ResultMessage = &reason
+
+	return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, nil)
+}
+
+// cancelJob fails a job which was not picked up by a worker yet
+func cancelJob(ctx context.Context, tx database.Database, job *model.Job, reason string) (err error) {
+	status := *job.Info.JobStatusName
+	if status != types.JobStatus.Created && status != types.JobStatus.Submitted {
+		return fmt.Errorf("%w: job is %s, only created or submitted jobs can be cancelled", ErrConflict, status)
+	}
+
+	return failJob(ctx, tx, job, "cancelled: "+reason)
+}
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+const (
+	// DefaultListLimit is the number of jobs listed when no limit is requested
+	DefaultListLimit = 50
+	MaxListLimit     = 1000
+
+	shutdownTimeout = 5 * time.Second
+)
+
+type actorKey struct{}
+
+// Server serves the admin API used by support staff to inspect and operate on jobs.
+// Requests are authenticated with a bearer token mapped to the actor recorded in the audit trail.
+type Server struct {
+	db     database.Database
+	tokens map[string]string
+	server *http.Server
+}
+
+// New creates an admin API server listening on the given port, tokens maps bearer tokens to actors
+func New(port int, db database.Database, tokens map[string]string) *Server {
+	s := &Server{
+		db:     db,
+		tokens: tokens,
+	}
+
+	s.server = &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// Handler returns the http handler of the admin API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/jobs", s.listJobs)
+	mux.HandleFunc("/admin/jobs/", s.routeJob)
+
+	return s.authenticate(mux)
+}
+
+// Start serves the admin API until the context is done
+func (s *Server) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := s.server.Shutdown(shutdownCtx); err != nil {
+			log.Error("Error shutting down admin API server", log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+		}
+	}()
+
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := s.actor(r.Header.Get("Authorization"))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), actorKey{}, actor)))
+	})
+}
+
+// actor returns the actor of the bearer token, all tokens are compared to avoid leaking timing
+func (s *Server) actor(header string) (actor string, ok bool) {
+	if !strings.HasPrefix(header, "Bearer ") {
+		return "", false
+	}
+
+	token := strings.TrimPrefix(header, "Bearer ")
+	if token == "" {
+		return "", false
+	}
+
+	for t, a := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			actor, ok = a, true
+		}
+	}
+
+	return
+}
+
+func actorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Error("Error writing admin API response", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
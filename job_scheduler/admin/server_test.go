@@ -0,0 +1,259 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+type AdminServerTestSuite struct {
+	suite.Suite
+	db      *database.MockDatabase
+	handler http.Handler
+}
+
+func TestAdminServerTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminServerTestSuite))
+}
+
+func (suite *AdminServerTestSuite) SetupSuite() {
+	log.Setup(config.Config{LogLevel: "mute"})
+}
+
+func (suite *AdminServerTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.handler = New(0, suite.db, map[string]string{"t0ken": "alice"}).Handler()
+
+	// return the error of the transaction function like the database does
+	call := suite.db.On("WithTransaction", mock.Anything)
+	call.Run(func(args mock.Arguments) {
+		transactionFunc := args.Get(0).(func(database.Database) error)
+		call.ReturnArguments = mock.Arguments{transactionFunc(suite.db)}
+	})
+}
+
+func (suite *AdminServerTestSuite) request(method string, path string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer t0ken")
+
+	rec := httptest.NewRecorder()
+	suite.handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func job(status string, isFinal bool) *model.Job {
+	return &model.Job{
+		ID: "job1",
+		Info: &model.VJob{
+			JobID:            types.ToPointer("job1"),
+			JobStatusName:    &status,
+			JobStatusIsFinal: &isFinal,
+		},
+	}
+}
+
+func auditFor(action string) interface{} {
+	return mock.MatchedBy(func(a *model.JobAdminAudit) bool {
+		return a.Action == action && a.Actor == "alice"
+	})
+}
+
+func (suite *AdminServerTestSuite) TestUnauthorized() {
+	for _, header := range []string{"", "Bearer", "Bearer wrong", "t0ken"} {
+		req := httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)
+		req.Header.Set("Authorization", header)
+
+		rec := httptest.NewRecorder()
+		suite.handler.ServeHTTP(rec, req)
+
+		suite.Equal(http.StatusUnauthorized, rec.Code, header)
+	}
+
+	suite.db.AssertNotCalled(suite.T(), "GetJobs", mock.Anything, mock.Anything)
+}
+
+func (suite *AdminServerTestSuite) TestListJobs() {
+	filter := database.JobFilter{Status: "failed", ReferenceID: "ref1", Limit: 10}
+
+	suite.db.On("GetJobs", mock.Anything, filter).Return([]model.VJob{{JobID: types.ToPointer("job1")}}, nil)
+	suite.db.On("CreateJobAdminAudit", mock.Anything, auditFor(ActionList)).Return(nil)
+
+	rec := suite.request(http.MethodGet, "/admin/jobs?status=failed&reference_id=ref1&limit=10", "")
+	suite.Equal(http.StatusOK, rec.Code)
+
+	var jobs []model.VJob
+	suite.NoError(json.Unmarshal(rec.Body.Bytes(), &jobs))
+	suite.Len(jobs, 1)
+
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *AdminServerTestSuite) TestListJobsInvalidLimit() {
+	rec := suite.request(http.MethodGet, "/admin/jobs?limit=-1", "")
+	suite.Equal(http.StatusBadRequest, rec.Code)
+}
+
+func (suite *AdminServerTestSuite) TestShowJob() {
+	j := job(types.JobStatus.Failed, true)
+	j.Info.Data = []byte(`{"name":"example.com"}`)
+
+	suite.db.On("GetJobById", mock.Anything, "job1", false).Return(j, nil)
+	suite.db.On("GetChildJobs", mock.Anything, "job1").Return([]model.VJob{{JobID: types.ToPointer("job2")}}, nil)
+	suite.db.On("GetJobAdminAudits", mock.Anything, "job1").Return([]model.JobAdminAudit{}, nil)
+	suite.db.On("CreateJobAdminAudit", mock.Anything, auditFor(ActionShow)).Return(nil)
+
+	rec := suite.request(http.MethodGet, "/admin/jobs/job1", "")
+	suite.Equal(http.StatusOK, rec.Code)
+
+	var details struct {
+		Job struct {
+			Data map[string]string `json:"data"`
+		} `json:"job"`
+		Children []model.VJob `json:"children"`
+	}
+	suite.NoError(json.Unmarshal(rec.Body.Bytes(), &details))
+	suite.Equal("example.com", details.Job.Data["name"])
+	suite.Len(details.Children, 1)
+
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *AdminServerTestSuite) TestShowJobNotFound() {
+	suite.db.On("GetJobById", mock.Anything, "job1", false).Return((*model.Job)(nil), database.ErrNotFound)
+
+	rec := suite.request(http.MethodGet, "/admin/jobs/job1", "")
+	suite.Equal(http.StatusNotFound, rec.Code)
+}
+
+func (suite *AdminServerTestSuite) TestFailJob() {
+	j := job(types.JobStatus.Processing, false)
+
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(j, nil)
+	suite.db.On("DisableJobRetries", mock.Anything, "job1").Return(nil)
+	suite.db.On("SetJobStatus", mock.Anything, j, types.JobStatus.Failed, (*types.JobResultData)(nil)).Return(nil)
+	suite.db.On("CreateJobAdminAudit", mock.Anything, mock.MatchedBy(func(a *model.JobAdminAudit) bool {
+		return a.Action == ActionFail && *a.Reason == "stuck at registry" && *a.PreviousStatus == types.JobStatus.Processing
+	})).Return(nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/fail", `{"reason":"stuck at registry"}`)
+	suite.Equal(http.StatusOK, rec.Code)
+	suite.Equal("stuck at registry", *j.ResultMessage)
+
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *AdminServerTestSuite) TestFailJobReasonRequired() {
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/fail", `{}`)
+	suite.Equal(http.StatusBadRequest, rec.Code)
+}
+
+func (suite *AdminServerTestSuite) TestFailFinalJob() {
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(job(types.JobStatus.Completed, true), nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/fail", `{"reason":"test"}`)
+	suite.Equal(http.StatusConflict, rec.Code)
+
+	suite.db.AssertNotCalled(suite.T(), "CreateJobAdminAudit", mock.Anything, mock.Anything)
+}
+
+func (suite *AdminServerTestSuite) TestCancelProcessingJob() {
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(job(types.JobStatus.Processing, false), nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/cancel", `{"reason":"duplicate order"}`)
+	suite.Equal(http.StatusConflict, rec.Code)
+
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AdminServerTestSuite) TestCancelJob() {
+	j := job(types.JobStatus.Submitted, false)
+
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(j, nil)
+	suite.db.On("DisableJobRetries", mock.Anything, "job1").Return(nil)
+	suite.db.On("SetJobStatus", mock.Anything, j, types.JobStatus.Failed, (*types.JobResultData)(nil)).Return(nil)
+	suite.db.On("CreateJobAdminAudit", mock.Anything, auditFor(ActionCancel)).Return(nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/cancel", `{"reason":"duplicate order"}`)
+	suite.Equal(http.StatusOK, rec.Code)
+	suite.Equal("cancelled: duplicate order", *j.ResultMessage)
+
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *AdminServerTestSuite) TestRequeueSubmittedJob() {
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(job(types.JobStatus.Submitted, false), nil)
+	suite.db.On("SetJobStartDate", mock.Anything, "job1", mock.Anything).Return(nil)
+	suite.db.On("NotifyDueJobs", mock.Anything, []string{"job1"}).Return([]model.StaleJob{}, nil)
+	suite.db.On("CreateJobAdminAudit", mock.Anything, auditFor(ActionRequeue)).Return(nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/requeue", "")
+	suite.Equal(http.StatusOK, rec.Code)
+
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *AdminServerTestSuite) TestRequeueProcessingJob() {
+	j := job(types.JobStatus.Processing, false)
+
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(j, nil)
+	suite.db.On("SetJobStatus", mock.Anything, j, types.JobStatus.Submitted, (*types.JobResultData)(nil)).Return(nil)
+	suite.db.On("CreateJobAdminAudit", mock.Anything, auditFor(ActionRequeue)).Return(nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/requeue", "")
+	suite.Equal(http.StatusOK, rec.Code)
+	suite.NotNil(j.StartDate)
+
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *AdminServerTestSuite) TestRequeueFailedJob() {
+	j := job(types.JobStatus.Failed, true)
+
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(j, nil)
+	suite.db.On("RequeueJob", mock.Anything, "job1").Return(true, nil)
+	suite.db.On("CreateJobAdminAudit", mock.Anything, mock.MatchedBy(func(a *model.JobAdminAudit) bool {
+		return a.Action == ActionRequeue && *a.PreviousStatus == types.JobStatus.Failed && *a.NewStatus == types.JobStatus.Submitted
+	})).Return(nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/requeue", "")
+	suite.Equal(http.StatusOK, rec.Code)
+
+	suite.db.AssertExpectations(suite.T())
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AdminServerTestSuite) TestRequeueFailedJobRefused() {
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(job(types.JobStatus.Failed, true), nil)
+	suite.db.On("RequeueJob", mock.Anything, "job1").Return(false, nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/requeue", "")
+	suite.Equal(http.StatusConflict, rec.Code)
+
+	suite.db.AssertNotCalled(suite.T(), "CreateJobAdminAudit", mock.Anything, mock.Anything)
+}
+
+func (suite *AdminServerTestSuite) TestRequeueCompletedJob() {
+	suite.db.On("GetJobById", mock.Anything, "job1", true).Return(job(types.JobStatus.Completed, true), nil)
+
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/requeue", "")
+	suite.Equal(http.StatusConflict, rec.Code)
+
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AdminServerTestSuite) TestUnknownAction() {
+	rec := suite.request(http.MethodPost, "/admin/jobs/job1/delete", "")
+	suite.Equal(http.StatusNotFound, rec.Code)
+}
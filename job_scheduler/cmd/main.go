@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -13,16 +14,25 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
+	"github.com/tucowsinc/tdp-workers-go/job_scheduler/admin"
 	"github.com/tucowsinc/tdp-workers-go/job_scheduler/handler"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/leader"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	pgevent "github.com/tucowsinc/tdp-workers-go/pkg/pgevents"
 )
 
 const (
-	JobCheckInterval = 30 * time.Second
+	// jobs are dispatched at their start date by the delayed job scheduler, this sweep is only a fallback
+	JobCheckInterval      = 5 * time.Minute
+	DispatchRetryInterval = 5 * time.Second
+	ClaimRecoveryInterval = time.Minute
+	// a job stays dispatching only while its worker sends the command, longer claims are left behind by a stopped worker
+	JobClaimTimeout = 5 * time.Minute
+	LeaderLockName  = "job_scheduler"
 )
 
 func main() {
@@ -45,15 +55,18 @@ func main() {
 		log.Error("error is: ", log.Fields{"error": err})
 	}()
 
-	ctx := context.Background()
-
-	ticker := time.NewTicker(JobCheckInterval)
-	defer ticker.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	notificationHandler := handler.NewNotificationHandler(cfg, tracer)
 	defer notificationHandler.Close()
 
-	listener, err := pgevent.New(cfg.DBConnStr())
+	listener, err := pgevent.New(
+		cfg.DBConnStr(),
+		pgevent.WithMessageTimeout(cfg.GetPGEventsMessageTimeout()),
+		pgevent.WithMaxMessageSize(cfg.GetPGEventsMaxMessageSize()),
+		pgevent.WithOnConnect(notificationHandler.ReplayMissedJobs),
+	)
 	if err != nil {
 		log.Fatal("Error creating listener", log.Fields{
 			types.LogFieldKeys.Error: err,
@@ -67,29 +80,81 @@ func main() {
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
 
-	go func() {
-		err := listener.StartListening(ctx)
-		if err != nil {
-			errCh <- fmt.Errorf("error listening for notifications: %v", err)
-		}
-	}()
+	elector, err := leader.New(cfg.DBConnStr(), LeaderLockName, cfg.GetLeaderElectionInterval())
+	if err != nil {
+		log.Fatal("Error creating leader elector", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
 
+	// only the leader forwards job events and re-queues stale jobs, other replicas stay on standby
 	go func() {
-		for {
-			select {
-			case t := <-ticker.C:
-				if err := notificationHandler.Service.CheckStaleJobs(t); err != nil {
-					errCh <- fmt.Errorf("error checking stale jobs: %v", err)
-					break
+		err := elector.Run(ctx, func(leaderCtx context.Context) {
+			log.Info("Elected as leader, starting job dispatch")
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				err := listener.StartListening(leaderCtx)
+				if err != nil && leaderCtx.Err() == nil {
+					errCh <- fmt.Errorf("error listening for notifications: %v", err)
+				}
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				notificationHandler.Delayed.Run(leaderCtx)
+			}()
+
+			ticker := time.NewTicker(JobCheckInterval)
+			defer ticker.Stop()
+
+			retryTicker := time.NewTicker(DispatchRetryInterval)
+			defer retryTicker.Stop()
+
+			claimTicker := time.NewTicker(ClaimRecoveryInterval)
+			defer claimTicker.Stop()
+
+			for {
+				select {
+				case <-leaderCtx.Done():
+					wg.Wait()
+					log.Info("Leadership lost, stopped job dispatch")
+					return
+				case t := <-ticker.C:
+					if err := notificationHandler.Service.CheckStaleJobs(t); err != nil {
+						errCh <- fmt.Errorf("error checking stale jobs: %v", err)
+					}
+				case t := <-retryTicker.C:
+					if err := notificationHandler.Service.RetryFailedDispatches(t); err != nil {
+						log.Error("Error retrying failed job dispatches", log.Fields{
+							types.LogFieldKeys.Error: err,
+						})
+					}
+				case t := <-claimTicker.C:
+					if err := notificationHandler.Service.RecoverJobClaims(t, JobClaimTimeout); err != nil {
+						log.Error("Error recovering job claims", log.Fields{
+							types.LogFieldKeys.Error: err,
+						})
+					}
 				}
 			}
+		})
+		if err != nil && ctx.Err() == nil {
+			errCh <- fmt.Errorf("error running leader election: %v", err)
 		}
 	}()
 
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range notificationHandler.HealthChecks(cfg) {
+		checks := append(notificationHandler.HealthChecks(cfg), leader.HealthCheck(elector), leader.RoleCheck(elector))
+
+		for _, check := range checks {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -106,6 +171,26 @@ func main() {
 		}()
 	}
 
+	if cfg.AdminAPIEnabled {
+		db, err := database.New(cfg.PostgresPoolConfig(), cfg.GetDBLogLevel())
+		if err != nil {
+			log.Fatal(types.LogMessages.DatabaseConnectionFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+		}
+		defer db.Close()
+
+		adminServer := admin.New(cfg.AdminAPIPort, db, cfg.GetAdminAPITokens())
+
+		go func() {
+			log.Info("Starting admin API server for job scheduler worker")
+			err := adminServer.Start(ctx)
+			if err != nil {
+				errCh <- fmt.Errorf("error occurred while starting admin API server for job scheduler worker: %v", err)
+			}
+		}()
+	}
+
 	select {
 	case err := <-errCh:
 		log.Error("Error occurred", log.Fields{"error": err})
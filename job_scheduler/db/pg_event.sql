@@ -6,16 +6,17 @@ DECLARE
     part        INT;
     total       INT;
     size        INT;
-    chunk_size  INT DEFAULT (8000-10); 
+    chunk_size  INT DEFAULT (8000-50); 
     envelope    TEXT;
     msg         TEXT;
+    msg_id      UUID DEFAULT gen_random_uuid();
 BEGIN
 
 
     envelope := JSONB_BUILD_OBJECT(
         'payload',_payload,
         'type',_msg_type,
-        'id', gen_random_uuid()
+        'id', msg_id
     )::TEXT;
 
     size := LENGTH(envelope);
@@ -24,7 +25,7 @@ BEGIN
     FOR part IN SELECT generate_series FROM generate_series(1,total,1)
     LOOP 
         msg := SUBSTRING(envelope,(chunk_size * (part - 1)) + 1 ,chunk_size );
-        PERFORM PG_NOTIFY(_channel,FORMAT('%s:%s:%s',part,total,msg));
+        PERFORM PG_NOTIFY(_channel,FORMAT('%s:%s:%s:%s',msg_id,part,total,msg));
     END LOOP;
 
     RETURN TRUE;
@@ -41,6 +42,10 @@ Since the PG_NOTIFY() function is limited to a maximum of 8000 bytes per message
 and considering that some messages may be larger than that, this function will 
 split the payload in chunks and sent in a frame-like approach to ensure that the
 listener can re-assemble the payload.
+
+Each chunk is prefixed with the message id, part and total number of parts
+(id:part:total:chunk), so chunks of messages sent concurrently on the same channel
+can be re-assembled independently.
 ';
 
 
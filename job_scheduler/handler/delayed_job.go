@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tucowsinc/tdp-shared-go/logger"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/timerwheel"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+const (
+	// DelayedJobHorizon is how far ahead upcoming jobs are loaded into the timer wheel
+	DelayedJobHorizon = 2 * time.Minute
+	// DelayedJobSweepInterval is how often upcoming jobs are re-loaded from the database,
+	// it must be shorter than DelayedJobHorizon so no job falls between two sweeps
+	DelayedJobSweepInterval = time.Minute
+)
+
+// DelayedJobScheduler dispatches submitted jobs with a future start date exactly at their start date.
+//
+// Upcoming jobs are kept in a timer wheel which is filled from job_scheduled_notify events
+// and re-loaded by a low frequency sweep, in case an event was missed.
+type DelayedJobScheduler struct {
+	db    database.Database
+	wheel *timerwheel.Wheel
+
+	Horizon       time.Duration
+	SweepInterval time.Duration
+}
+
+func NewDelayedJobScheduler(db database.Database) *DelayedJobScheduler {
+	return &DelayedJobScheduler{
+		db:            db,
+		wheel:         timerwheel.New(timerwheel.DefaultTick, timerwheel.DefaultSlots),
+		Horizon:       DelayedJobHorizon,
+		SweepInterval: DelayedJobSweepInterval,
+	}
+}
+
+// Schedule adds job to the timer wheel. Jobs starting beyond the horizon are skipped,
+// they are picked up by one of the next sweeps.
+func (d *DelayedJobScheduler) Schedule(jobId string, startDate time.Time) bool {
+	if time.Until(startDate) > d.Horizon {
+		return false
+	}
+
+	d.wheel.Schedule(jobId, startDate)
+
+	return true
+}
+
+// Refresh loads jobs starting within the horizon into the timer wheel
+func (d *DelayedJobScheduler) Refresh(ctx context.Context) error {
+	jobs, err := d.db.GetUpcomingJobs(ctx, time.Now().Add(d.Horizon))
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		d.wheel.Schedule(job.JobID, job.StartDate)
+	}
+
+	return nil
+}
+
+// Run fires scheduled jobs and periodically refreshes the timer wheel until ctx is done
+func (d *DelayedJobScheduler) Run(ctx context.Context) error {
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.LogID: uuid.NewString(),
+		"component":              "DelayedJobScheduler",
+	})
+
+	if err := d.Refresh(ctx); err != nil {
+		logger.Error("Error loading upcoming jobs", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+
+	go d.wheel.Run(ctx, func(jobIds []string) {
+		d.dispatch(ctx, logger, jobIds)
+	})
+
+	ticker := time.NewTicker(d.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := d.Refresh(ctx); err != nil {
+				logger.Error("Error loading upcoming jobs", log.Fields{
+					types.LogFieldKeys.Error: err,
+				})
+			}
+		}
+	}
+}
+
+// dispatch notifies due jobs; jobs which were cancelled or already dispatched meanwhile are skipped by the database
+func (d *DelayedJobScheduler) dispatch(ctx context.Context, logger logger.ILogger, jobIds []string) {
+	jobs, err := d.db.NotifyDueJobs(ctx, jobIds)
+	if err != nil {
+		logger.Error("Error dispatching delayed jobs", log.Fields{
+			types.LogFieldKeys.Error: err,
+			"jobs":                   jobIds,
+		})
+		return
+	}
+
+	for _, job := range jobs {
+		logger.Info("Delayed job dispatched", log.Fields{
+			types.LogFieldKeys.JobID:  job.JobID,
+			types.LogFieldKeys.Status: job.JobStatusName,
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	pgevent "github.com/tucowsinc/tdp-workers-go/pkg/pgevents"
+)
+
+type DelayedJobSchedulerTestSuite struct {
+	suite.Suite
+	db        *database.MockDatabase
+	scheduler *DelayedJobScheduler
+}
+
+func TestDelayedJobSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(DelayedJobSchedulerTestSuite))
+}
+
+func (suite *DelayedJobSchedulerTestSuite) SetupSuite() {
+	log.Setup(config.Config{LogLevel: "mute"})
+}
+
+func (suite *DelayedJobSchedulerTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.scheduler = NewDelayedJobScheduler(suite.db)
+}
+
+func (suite *DelayedJobSchedulerTestSuite) TestSchedule() {
+	suite.True(suite.scheduler.Schedule("job1", time.Now().Add(time.Second)))
+	suite.False(suite.scheduler.Schedule("job2", time.Now().Add(2*DelayedJobHorizon)))
+
+	suite.Equal(1, suite.scheduler.wheel.Len())
+}
+
+func (suite *DelayedJobSchedulerTestSuite) TestRefresh() {
+	startDate := time.Now().Add(time.Minute)
+
+	suite.db.On("GetUpcomingJobs", mock.Anything, mock.AnythingOfType("time.Time")).
+		Return([]model.UpcomingJob{{JobID: "job1", StartDate: startDate}}, nil)
+
+	err := suite.scheduler.Refresh(context.Background())
+	suite.NoError(err)
+
+	at, ok := suite.scheduler.wheel.Scheduled("job1")
+	suite.True(ok)
+	suite.Equal(startDate, at)
+}
+
+func (suite *DelayedJobSchedulerTestSuite) TestRunDispatchesDueJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	dispatched := make(chan struct{})
+
+	suite.db.On("GetUpcomingJobs", mock.Anything, mock.AnythingOfType("time.Time")).
+		Return([]model.UpcomingJob{{JobID: "job1", StartDate: time.Now().Add(200 * time.Millisecond)}}, nil)
+	suite.db.On("NotifyDueJobs", mock.Anything, []string{"job1"}).
+		Return([]model.StaleJob{{JobID: "job1", JobStatusName: "submitted", NotifyEvent: true}}, nil).
+		Run(func(args mock.Arguments) { close(dispatched) })
+
+	go suite.scheduler.Run(ctx)
+
+	select {
+	case <-dispatched:
+	case <-ctx.Done():
+		suite.Fail("delayed job was not dispatched")
+	}
+}
+
+func (suite *DelayedJobSchedulerTestSuite) TestHandleJobScheduledNotification() {
+	handler := &NotificationHandler{Service: &WorkerService{db: suite.db}, Delayed: suite.scheduler}
+	startDate := time.Now().Add(time.Minute).UTC().Truncate(time.Microsecond)
+
+	err := handler.HandleNotification(&pgevent.Notification{
+		Type:    "job_scheduled_notify",
+		Payload: `{"job_id":"job1","start_date":"` + startDate.Format(time.RFC3339Nano) + `"}`,
+	})
+	suite.NoError(err)
+
+	at, ok := suite.scheduler.wheel.Scheduled("job1")
+	suite.True(ok)
+	suite.True(startDate.Equal(at))
+}
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/tucowsinc/tdp-messages-go/message/job"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
@@ -29,11 +31,58 @@ func (s *WorkerService) JobEventNotifyHandler(event *types.JobEvent) (err error)
 		ReferenceTable: event.ReferenceTable,
 	}
 
-	err = s.bus.Send(context.Background(), event.RoutingKey, &jobNotification, headers)
+	ctx := context.Background()
+
+	// hold the job until the registry maintenance ends, it is dispatched again at its new start date
+	window, err := s.db.GetJobActiveMaintenance(ctx, event.JobId)
+	if err == nil {
+		err = s.db.SetJobStartDate(ctx, event.JobId, window.EndDate)
+		if err != nil {
+			logger.Error("Failed to hold job for maintenance window", log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return
+		}
+
+		logger.Info("Accreditation is in maintenance, job held", log.Fields{
+			"maintenance_id": window.ID,
+			"start_date":     window.EndDate,
+		})
+		return nil
+	} else if !errors.Is(err, database.ErrNotFound) {
+		logger.Warn("Failed to check accreditation maintenance window, sending anyway", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+
+	err = s.bus.Send(ctx, event.RoutingKey, &jobNotification, headers)
 	if err != nil {
 		logger.Error("Failed to send job notification", log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
+
+		// job stays submitted, schedule another publish attempt
+		dispatch, dbErr := s.db.SetJobDispatchFailed(ctx, event.JobId, err.Error(), DispatchRetryBase, DispatchRetryMax)
+		if dbErr != nil {
+			logger.Error("Failed to record job dispatch failure", log.Fields{
+				types.LogFieldKeys.Error: dbErr,
+			})
+			return
+		}
+
+		logger.Warn("Job dispatch retry scheduled", log.Fields{
+			"attempt":      dispatch.AttemptCount,
+			"next_attempt": dispatch.NextAttemptDate,
+		})
+		return
+	}
+
+	// record dispatch only once the message bus has confirmed the publish
+	_, err = s.db.SetJobDispatched(ctx, event.JobId)
+	if err != nil {
+		logger.Error("Failed to record job dispatch", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
 		return
 	}
 
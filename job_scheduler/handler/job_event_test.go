@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
+	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+type JobEventTestSuite struct {
+	suite.Suite
+	db      *database.MockDatabase
+	mb      *mocks.MockMessageBus
+	t       *oteltrace.Tracer
+	service *WorkerService
+}
+
+func TestJobEventTestSuite(t *testing.T) {
+	suite.Run(t, new(JobEventTestSuite))
+}
+
+func (suite *JobEventTestSuite) SetupSuite() {
+	cfg := config.Config{LogLevel: "mute"}
+	log.Setup(cfg)
+
+	tracer, _, err := tracing.Setup(context.Background(), cfg)
+	suite.NoError(err, "Error setting up tracing")
+	suite.t = tracer
+}
+
+func (suite *JobEventTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.mb = &mocks.MockMessageBus{}
+	suite.service = &WorkerService{db: suite.db, bus: suite.mb, tracer: suite.t}
+}
+
+func (suite *JobEventTestSuite) TestJobEventNotifyHandlerDispatched() {
+	event := &types.JobEvent{JobId: "job1", Type: "provision_domain", RoutingKey: "WorkerJobDomainProvision"}
+
+	suite.db.On("GetJobActiveMaintenance", mock.Anything, event.JobId).Return((*model.AccreditationMaintenance)(nil), database.ErrNotFound)
+	suite.mb.On("Send", mock.Anything, event.RoutingKey, mock.Anything, mock.Anything).Return(nil)
+	suite.db.On("SetJobDispatched", mock.Anything, event.JobId).Return(&model.JobDispatch{JobID: event.JobId, AttemptCount: 1}, nil)
+
+	err := suite.service.JobEventNotifyHandler(event)
+	suite.NoError(err)
+
+	suite.mb.AssertExpectations(suite.T())
+	suite.db.AssertExpectations(suite.T())
+	suite.db.AssertNotCalled(suite.T(), "SetJobDispatchFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *JobEventTestSuite) TestJobEventNotifyHandlerSendFailed() {
+	event := &types.JobEvent{JobId: "job1", Type: "provision_domain", RoutingKey: "WorkerJobDomainProvision"}
+	sendErr := errors.New("channel closed")
+	next := time.Now().Add(DispatchRetryBase)
+
+	suite.db.On("GetJobActiveMaintenance", mock.Anything, event.JobId).Return((*model.AccreditationMaintenance)(nil), database.ErrNotFound)
+	suite.mb.On("Send", mock.Anything, event.RoutingKey, mock.Anything, mock.Anything).Return(sendErr)
+	suite.db.On("SetJobDispatchFailed", mock.Anything, event.JobId, sendErr.Error(), DispatchRetryBase, DispatchRetryMax).
+		Return(&model.JobDispatch{JobID: event.JobId, AttemptCount: 1, NextAttemptDate: &next}, nil)
+
+	err := suite.service.JobEventNotifyHandler(event)
+	suite.ErrorIs(err, sendErr)
+
+	suite.db.AssertExpectations(suite.T())
+	suite.db.AssertNotCalled(suite.T(), "SetJobDispatched", mock.Anything, mock.Anything)
+}
+
+func (suite *JobEventTestSuite) TestJobEventNotifyHandlerMaintenance() {
+	event := &types.JobEvent{JobId: "job1", Type: "provision_domain", RoutingKey: "WorkerJobDomainProvision"}
+	window := &model.AccreditationMaintenance{ID: "m1", EndDate: time.Now().Add(time.Hour)}
+
+	suite.db.On("GetJobActiveMaintenance", mock.Anything, event.JobId).Return(window, nil)
+	suite.db.On("SetJobStartDate", mock.Anything, event.JobId, window.EndDate).Return(nil)
+
+	err := suite.service.JobEventNotifyHandler(event)
+	suite.NoError(err)
+
+	suite.db.AssertExpectations(suite.T())
+	suite.mb.AssertNotCalled(suite.T(), "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *JobEventTestSuite) TestRetryFailedDispatches() {
+	suite.db.On("GetFailedDispatchJobs", mock.Anything).
+		Return([]model.StaleJob{{JobID: "job1", JobStatusName: "submitted", NotifyEvent: true}}, nil)
+
+	err := suite.service.RetryFailedDispatches(time.Now())
+	suite.NoError(err)
+
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobEventTestSuite) TestRecoverJobClaims() {
+	suite.db.On("RecoverJobClaims", mock.Anything, 5*time.Minute).
+		Return([]model.StaleJob{{JobID: "job1", JobStatusName: "failed"}}, nil)
+
+	err := suite.service.RecoverJobClaims(time.Now(), 5*time.Minute)
+	suite.NoError(err)
+
+	suite.db.AssertExpectations(suite.T())
+}
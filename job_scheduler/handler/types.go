@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexliesenfeld/health"
@@ -26,8 +27,23 @@ type WorkerService struct {
 
 type NotificationHandler struct {
 	Service *WorkerService
+	Delayed *DelayedJobScheduler
+
+	// unix nano time of the last received notification
+	lastSeen atomic.Int64
 }
 
+// ReplayLookback is subtracted from the last seen notification time when replaying missed jobs
+// to cover clock skew between the scheduler and the database
+const ReplayLookback = 5 * time.Second
+
+const (
+	// DispatchRetryBase is the delay before the first retry of a failed job notification publish,
+	// it is doubled on every following attempt up to DispatchRetryMax
+	DispatchRetryBase = 5 * time.Second
+	DispatchRetryMax  = 5 * time.Minute
+)
+
 func NewWorkerService(cfg config.Config, tracer *oteltrace.Tracer) *WorkerService {
 
 	// Instantiate a messagebus
@@ -56,7 +72,10 @@ func NewWorkerService(cfg config.Config, tracer *oteltrace.Tracer) *WorkerServic
 func NewNotificationHandler(config config.Config, tracer *oteltrace.Tracer) *NotificationHandler {
 	service := NewWorkerService(config, tracer)
 
-	return &NotificationHandler{Service: service}
+	return &NotificationHandler{
+		Service: service,
+		Delayed: NewDelayedJobScheduler(service.db),
+	}
 }
 
 // CheckStaleJobs checks for stale jobs.
@@ -85,8 +104,101 @@ func (s *WorkerService) CheckStaleJobs(t time.Time) (err error) {
 	return nil
 }
 
+// RetryFailedDispatches re-queues jobs whose notification could not be published
+// once their retry backoff has elapsed.
+func (s *WorkerService) RetryFailedDispatches(t time.Time) (err error) {
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.LogID: uuid.NewString(),
+		"component":              "RetryFailedDispatches",
+	})
+	jobs, err := s.db.GetFailedDispatchJobs(context.Background())
+	if err != nil {
+		logger.Error("Error querying failed dispatch jobs", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	for _, job := range jobs {
+		logger.Info("Failed dispatch job re-queued",
+			log.Fields{
+				types.LogFieldKeys.JobID:  job.JobID,
+				types.LogFieldKeys.Status: job.JobStatusName,
+				"checked":                 t,
+			},
+		)
+	}
+	return nil
+}
+
+// RecoverJobClaims fails jobs claimed by a worker for longer than timeout, so a worker which
+// stopped while sending a command does not leave its job dispatching. The jobs are not sent
+// again as the command may already have reached the registry.
+func (s *WorkerService) RecoverJobClaims(t time.Time, timeout time.Duration) (err error) {
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.LogID: uuid.NewString(),
+		"component":              "RecoverJobClaims",
+	})
+	jobs, err := s.db.RecoverJobClaims(context.Background(), timeout)
+	if err != nil {
+		logger.Error("Error recovering job claims", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	for _, job := range jobs {
+		logger.Warn("Stuck job claim failed",
+			log.Fields{
+				types.LogFieldKeys.JobID:  job.JobID,
+				types.LogFieldKeys.Status: job.JobStatusName,
+				"checked":                 t,
+			},
+		)
+	}
+	return nil
+}
+
+// ReplayMissedJobs re-dispatches submitted jobs created since the last seen notification.
+// It is called every time the listener (re)connects, as notifications sent while it was
+// disconnected are lost.
+func (h *NotificationHandler) ReplayMissedJobs(ctx context.Context) {
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.LogID: uuid.NewString(),
+		"component":              "ReplayMissedJobs",
+	})
+
+	lastSeen := h.lastSeen.Load()
+	if lastSeen == 0 {
+		// first connection, nothing could have been missed yet
+		h.lastSeen.CompareAndSwap(0, time.Now().UnixNano())
+		return
+	}
+
+	since := time.Unix(0, lastSeen).Add(-ReplayLookback)
+
+	jobs, err := h.Service.db.GetSubmittedJobsSince(ctx, since)
+	if err != nil {
+		logger.Error("Error replaying missed jobs", log.Fields{
+			types.LogFieldKeys.Error: err,
+			"since":                  since,
+		})
+		return
+	}
+
+	for _, job := range jobs {
+		logger.Info("Missed job re-queued", log.Fields{
+			types.LogFieldKeys.JobID:  job.JobID,
+			types.LogFieldKeys.Status: job.JobStatusName,
+			"since":                   since,
+		})
+	}
+}
+
 // HandleNotification handles a notification event.
 func (h *NotificationHandler) HandleNotification(notification *pgevent.Notification) error {
+	h.lastSeen.Store(time.Now().UnixNano())
+
 	logger := log.CreateChildLogger(log.Fields{
 		types.LogFieldKeys.LogID: uuid.NewString(),
 		"component":              "HandleNotification",
@@ -106,6 +218,21 @@ func (h *NotificationHandler) HandleNotification(notification *pgevent.Notificat
 			"event_type":             event.Type,
 		})
 		return h.Service.JobEventNotifyHandler(event)
+	case "job_scheduled_notify":
+		event := new(types.JobScheduledEvent)
+		if err := json.Unmarshal([]byte(notification.Payload), event); err != nil {
+			logger.Error("Failed to unmarshal notification payload", log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return fmt.Errorf("failed to unmarshal JSON: %s", err.Error())
+		}
+		if h.Delayed != nil && h.Delayed.Schedule(event.JobId, event.StartDate) {
+			logger.Debug("Delayed job scheduled", log.Fields{
+				types.LogFieldKeys.JobID: event.JobId,
+				"start_date":             event.StartDate,
+			})
+		}
+		return nil
 	default:
 		errMsg := fmt.Sprintf("Unknown event type: %s", notification.Type)
 		logger.Warn(errMsg)
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+)
+
+type NotificationHandlerTestSuite struct {
+	suite.Suite
+	db      *database.MockDatabase
+	handler *NotificationHandler
+	ctx     context.Context
+}
+
+func TestNotificationHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationHandlerTestSuite))
+}
+
+func (suite *NotificationHandlerTestSuite) SetupSuite() {
+	cfg := config.Config{}
+	log.Setup(cfg)
+	suite.ctx = context.Background()
+}
+
+func (suite *NotificationHandlerTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.handler = &NotificationHandler{Service: &WorkerService{db: suite.db}}
+}
+
+func (suite *NotificationHandlerTestSuite) TestReplayMissedJobsFirstConnect() {
+	suite.handler.ReplayMissedJobs(suite.ctx)
+
+	suite.db.AssertNotCalled(suite.T(), "GetSubmittedJobsSince", mock.Anything, mock.Anything)
+	suite.NotZero(suite.handler.lastSeen.Load())
+}
+
+func (suite *NotificationHandlerTestSuite) TestReplayMissedJobsReconnect() {
+	lastSeen := time.Now().Add(-time.Minute)
+	suite.handler.lastSeen.Store(lastSeen.UnixNano())
+
+	expectedSince := time.Unix(0, lastSeen.UnixNano()).Add(-ReplayLookback)
+
+	suite.db.On("GetSubmittedJobsSince", suite.ctx, expectedSince).
+		Return([]model.StaleJob{{JobID: "job1", JobStatusName: "submitted", NotifyEvent: true}}, nil)
+
+	suite.handler.ReplayMissedJobs(suite.ctx)
+
+	suite.db.AssertExpectations(suite.T())
+}
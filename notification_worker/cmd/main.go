@@ -11,10 +11,12 @@ import (
 	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
 	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
 
-	"github.com/tucowsinc/tdp-shared-go/healthcheck"
+	"github.com/tucowsinc/tdp-workers-go/pkg/healthcheck"
 
 	"github.com/tucowsinc/tdp-workers-go/notification_worker/handlers"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	jobhandler "github.com/tucowsinc/tdp-workers-go/pkg/handlers"
+	"github.com/tucowsinc/tdp-workers-go/pkg/lifecycle"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
 	message_bus "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/repository/database"
@@ -48,7 +50,6 @@ func main() {
 			types.LogFieldKeys.Error: err,
 		})
 	}
-	defer bus.Finalize()
 
 	db, err := database.New(cfg.PostgresPoolConfig(), cfg.GetDBLogLevel())
 	if err != nil {
@@ -58,7 +59,13 @@ func main() {
 	}
 	defer db.Close()
 
-	service, err := handlers.NewWorkerService(bus, db, tracer)
+	// recover handler panics, the notification worker has no jobs to fail
+	recoverer := jobhandler.NewRecoverer(nil, cfg.HandlerPanicAck)
+
+	// drain in-flight handlers on shutdown
+	lc := lifecycle.New(cfg.GetShutdownTimeout())
+
+	service, err := handlers.NewWorkerService(lc.Bus(recoverer.Bus(bus)), db, tracer)
 	if err != nil {
 		log.Error("Error creating worker service", log.Fields{
 			types.LogFieldKeys.Error: err,
@@ -70,7 +77,7 @@ func main() {
 	if cfg.HealthcheckEnabled {
 		healthCheckServer := healthcheck.New(cfg.HealthcheckPort)
 
-		for _, check := range service.HealthChecks(cfg) {
+		for _, check := range append(service.HealthChecks(cfg), lc.HealthCheck()) {
 			healthCheckServer.RegisterHealthCheck(
 				check,
 				healthcheck.WithFrequency(time.Duration(cfg.HealthcheckInterval)*time.Second),
@@ -96,11 +103,12 @@ func main() {
 		Prefetch: &cfg.RmqPrefetchCount,
 	}
 
-	err = bus.Consume(queues, consumerOptions) // block until the messagebus quits
+	err = lc.Run(func() error { return bus.Consume(queues, consumerOptions) }, nil, func() { bus.Finalize() }) // block until the messagebus quits or is drained on shutdown
 	if err != nil {
-		log.Fatal(types.LogMessages.ConsumingQueuesFailed, log.Fields{
+		log.Error(types.LogMessages.ConsumingQueuesFailed, log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
+		return
 	}
 
 	log.Info(types.LogMessages.WorkerTerminated)
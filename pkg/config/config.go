@@ -45,6 +45,12 @@ type Config struct {
 
 	MbReadersCount int `mapstructure:"MESSAGEBUS_READERS_COUNT"`
 
+	// HandlerPanicAck acks messages which handler panicked instead of nacking them
+	HandlerPanicAck bool `mapstructure:"HANDLER_PANIC_ACK"`
+
+	// ShutdownTimeout is the number of seconds in-flight handlers are waited for on shutdown
+	ShutdownTimeout int `mapstructure:"SHUTDOWN_TIMEOUT"`
+
 	DatabaseURL string `mapstructure:"DATABASE_URL"`
 	DBPort      int    `mapstructure:"DBPORT"`
 	DBMaxConn   int    `mapstructure:"DBMAXCONN"`
@@ -84,6 +90,15 @@ type Config struct {
 	HealthcheckPort     int  `mapstructure:"HEALTHCHECK_PORT"`
 	HealthcheckInterval int  `mapstructure:"HEALTHCHECK_INTERVAL"`
 	HealthcheckTimeout  int  `mapstructure:"HEALTHCHECK_TIMEOUT"`
+
+	PGEventsMessageTimeout int `mapstructure:"PGEVENTS_MESSAGE_TIMEOUT"`
+	PGEventsMaxMessageSize int `mapstructure:"PGEVENTS_MAX_MESSAGE_SIZE"`
+
+	LeaderElectionInterval int `mapstructure:"LEADER_ELECTION_INTERVAL"`
+
+	AdminAPIEnabled bool   `mapstructure:"ADMIN_API_ENABLED"`
+	AdminAPIPort    int    `mapstructure:"ADMIN_API_PORT"`
+	AdminAPITokens  string `mapstructure:"ADMIN_API_TOKENS" secret:"true"`
 }
 
 // IsDebugEnabled returns a boolean flag indicating if log debug level is enabled
@@ -181,6 +196,14 @@ func (c *Config) PostgresPoolConfig() *pgxpool.Config {
 	return config
 }
 
+func (c *Config) GetShutdownTimeout() time.Duration {
+	if c.ShutdownTimeout == 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(c.ShutdownTimeout) * time.Second
+}
+
 func (c *Config) GetAPIRetryCount() int {
 	if c.APIRetryCount == 0 {
 		return 3
@@ -213,6 +236,48 @@ func (c *Config) GetCertBotApiTimeout() time.Duration {
 	return time.Duration(c.CertBotApiTimeout) * time.Second
 }
 
+// GetPGEventsMessageTimeout returns the time after which incomplete chunked notifications are dropped
+func (c *Config) GetPGEventsMessageTimeout() time.Duration {
+	if c.PGEventsMessageTimeout == 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(c.PGEventsMessageTimeout) * time.Second
+}
+
+// GetPGEventsMaxMessageSize returns the max size in bytes of a reassembled notification
+func (c *Config) GetPGEventsMaxMessageSize() int {
+	if c.PGEventsMaxMessageSize == 0 {
+		return 1024 * 1024
+	}
+
+	return c.PGEventsMaxMessageSize
+}
+
+// GetLeaderElectionInterval returns how often the leader checks its lock and standby retries to acquire it
+func (c *Config) GetLeaderElectionInterval() time.Duration {
+	if c.LeaderElectionInterval == 0 {
+		return 5 * time.Second
+	}
+
+	return time.Duration(c.LeaderElectionInterval) * time.Second
+}
+
+// GetAdminAPITokens returns the admin API actors by token, parsed from comma separated actor:token pairs
+func (c *Config) GetAdminAPITokens() map[string]string {
+	tokens := make(map[string]string)
+
+	for _, pair := range strings.Split(c.AdminAPITokens, ",") {
+		actor, token, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || actor == "" || token == "" {
+			continue
+		}
+		tokens[token] = actor
+	}
+
+	return tokens
+}
+
 func (c *Config) GetDBLogLevel() logger.LogLevel {
 	switch strings.ToLower(c.LogLevel) {
 	case "debug":
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -57,3 +58,24 @@ func TestLoadConfigurationNonExistingFile(t *testing.T) {
 	_, err := LoadConfiguration("non_existing_file.env")
 	assert.NotNil(t, err, "Expected error when loading non-existing file")
 }
+
+func TestGetPGEventsDefaults(t *testing.T) {
+	config := Config{}
+	assert.Equal(t, 30*time.Second, config.GetPGEventsMessageTimeout())
+	assert.Equal(t, 1024*1024, config.GetPGEventsMaxMessageSize())
+
+	config = Config{
+		PGEventsMessageTimeout: 5,
+		PGEventsMaxMessageSize: 2048,
+	}
+	assert.Equal(t, 5*time.Second, config.GetPGEventsMessageTimeout())
+	assert.Equal(t, 2048, config.GetPGEventsMaxMessageSize())
+}
+
+func TestGetAdminAPITokens(t *testing.T) {
+	config := Config{}
+	assert.Empty(t, config.GetAdminAPITokens())
+
+	config = Config{AdminAPITokens: "alice:s3cret, bob:t0ken,invalid,:missing"}
+	assert.Equal(t, map[string]string{"s3cret": "alice", "t0ken": "bob"}, config.GetAdminAPITokens())
+}
@@ -28,6 +28,15 @@ var (
 	ErrPollMessageInsert = errors.New("poll message insertion failed")
 )
 
+// JobFilter filters jobs listed by GetJobs, empty fields are not filtered on
+type JobFilter struct {
+	Status           string
+	Type             string
+	TenantCustomerID string
+	ReferenceID      string
+	Limit            int
+}
+
 // Database represents the database layer
 type Database interface {
 	Ping(ctx context.Context) error
@@ -44,6 +53,26 @@ type Database interface {
 	//General
 	GetAccreditationByName(ctx context.Context, name string) (acc *model.Accreditation, err error)
 	GetAccreditationById(ctx context.Context, id string) (acc *model.Accreditation, err error)
+	AcquireRateLimitToken(ctx context.Context, accreditationName string) (wait time.Duration, err error)
+
+	// Maintenance
+	GetActiveMaintenance(ctx context.Context, accreditationName string) (*model.AccreditationMaintenance, error)
+	GetJobActiveMaintenance(ctx context.Context, jobId string) (*model.AccreditationMaintenance, error)
+	GetMaintenanceById(ctx context.Context, id string) (*model.AccreditationMaintenance, error)
+	GetMaintenances(ctx context.Context, accreditationName string, includeEnded bool) ([]model.AccreditationMaintenance, error)
+	CreateMaintenance(ctx context.Context, m *model.AccreditationMaintenance) error
+	DeleteMaintenance(ctx context.Context, id string) error
+	EndMaintenance(ctx context.Context, id string) ([]model.StaleJob, error)
+
+	// EPP result policy
+	GetEppResultPolicies(ctx context.Context, jobType string, eppCode int32) ([]model.VEppResultPolicy, error)
+
+	// Object leases
+	AcquireObjectLease(ctx context.Context, objectType string, objectName string, accreditationName string, jobId string, ttl time.Duration) (wait time.Duration, err error)
+
+	// Processed messages
+	ClaimMessage(ctx context.Context, key string, messageType string, ttl time.Duration) (claimed bool, err error)
+	PurgeProcessedMessages(ctx context.Context) (int64, error)
 
 	// Order Plan
 	UpdateOrderItemPlan(ctx context.Context, pd *model.OrderItemPlan) error
@@ -53,6 +82,16 @@ type Database interface {
 	GetJobByEventId(ctx context.Context, eventId string, lock bool) (job *model.Job, err error)
 	SetJobStatus(ctx context.Context, job *model.Job, status string, jrd *types.JobResultData) error
 	UpdateJob(ctx context.Context, job *model.Job) error
+	SetJobStartDate(ctx context.Context, jobId string, startDate time.Time) error
+	GetJobs(ctx context.Context, filter JobFilter) ([]model.VJob, error)
+	GetChildJobs(ctx context.Context, parentId string) ([]model.VJob, error)
+	DisableJobRetries(ctx context.Context, jobId string) error
+	RescheduleJob(ctx context.Context, jobId string, message *string, maxRetries int, retryBase time.Duration, retryMax time.Duration) (bool, error)
+	RequeueJob(ctx context.Context, jobId string) (bool, error)
+	ClaimJob(ctx context.Context, job *model.Job) error
+	CreateJobAdminAudit(ctx context.Context, audit *model.JobAdminAudit) error
+	GetJobAdminAudits(ctx context.Context, jobId string) ([]model.JobAdminAudit, error)
+	GetJobTypes(ctx context.Context) ([]model.JobType, error)
 
 	// Contact
 	SetProvisionContactHandle(ctx context.Context, id string, handle string) error
@@ -79,6 +118,8 @@ type Database interface {
 	UpdateProvisionDomainTransferInRequest(ctx context.Context, pdtr *model.ProvisionDomainTransferInRequest) error
 	UpdateProvisionDomainTransferIn(ctx context.Context, pdti *model.ProvisionDomainTransferIn) error
 	CreateDomainRgpStatus(ctx context.Context, drs *model.DomainRgpStatus) (err error)
+	AutoRenewDomain(ctx context.Context, domainId string, exDate time.Time) (renewed bool, err error)
+	CreateDomainAutoRenewEvent(ctx context.Context, domainId string, exDate time.Time) error
 	GetActionableTransferAwayOrders(ctx context.Context, batchSize int) (result []model.VOrderTransferAwayDomain, err error)
 	GetDomainAccreditation(ctx context.Context, domainName string) (*model.DomainWithAccreditation, error)
 	GetPurgeableDomains(ctx context.Context, batchSize int) (result []model.VDomain, err error)
@@ -125,6 +166,7 @@ type Database interface {
 
 	// Poll
 	CreatePollMessage(ctx context.Context, message *model.PollMessage) (err error)
+	GetPollMessageById(ctx context.Context, id string) (*model.PollMessage, error)
 	UpdatePollMessageStatus(ctx context.Context, messageId string, status string) error
 
 	// Host
@@ -132,6 +174,13 @@ type Database interface {
 
 	// job_scheduler
 	GetStaleJobs(ctx context.Context) ([]model.StaleJob, error)
+	GetSubmittedJobsSince(ctx context.Context, since time.Time) ([]model.StaleJob, error)
+	GetUpcomingJobs(ctx context.Context, until time.Time) ([]model.UpcomingJob, error)
+	NotifyDueJobs(ctx context.Context, jobIds []string) ([]model.StaleJob, error)
+	GetFailedDispatchJobs(ctx context.Context) ([]model.StaleJob, error)
+	RecoverJobClaims(ctx context.Context, timeout time.Duration) ([]model.StaleJob, error)
+	SetJobDispatched(ctx context.Context, jobId string) (*model.JobDispatch, error)
+	SetJobDispatchFailed(ctx context.Context, jobId string, reason string, retryBase time.Duration, retryMax time.Duration) (*model.JobDispatch, error)
 
 	// Order
 	TransferAwayDomainOrder(ctx context.Context, order *model.Order) (err error)
@@ -340,6 +389,19 @@ func (db *database) WithTransaction(f func(Database) error) (err error) {
 	log.Debug("Transaction started")
 
 	defer func() {
+		// a panicking f must not commit its partial changes
+		if p := recover(); p != nil {
+			e := tx.Rollback()
+			if e != nil {
+				log.Error("error rolling back transaction", log.Fields{
+					types.LogFieldKeys.Error: e.Error(),
+				})
+			}
+
+			log.Debug("Transaction rolled back")
+			panic(p)
+		}
+
 		if err != nil {
 			e := tx.Rollback()
 			if e != nil {
@@ -397,6 +459,249 @@ func (db *database) GetAccreditationById(ctx context.Context, id string) (acc *m
 	return
 }
 
+// AcquireRateLimitToken takes a token from the accreditation outbound command bucket.
+// It returns the time to wait until a token becomes available, zero means the token was taken.
+func (db *database) AcquireRateLimitToken(ctx context.Context, accreditationName string) (wait time.Duration, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	var seconds float64
+	err = tx.Raw("SELECT EXTRACT(EPOCH FROM rate_limit_acquire(?))", accreditationName).Scan(&seconds).Error
+	if err != nil {
+		return
+	}
+
+	wait = time.Duration(seconds * float64(time.Second))
+
+	return
+}
+
+// GetActiveMaintenance returns the maintenance window of accreditation active now.
+// If several windows overlap the one ending last is returned.
+func (db *database) GetActiveMaintenance(ctx context.Context, accreditationName string) (m *model.AccreditationMaintenance, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	m = &model.AccreditationMaintenance{}
+	res := tx.Raw(`
+		SELECT m.*
+		FROM accreditation_maintenance m
+			JOIN accreditation a ON a.id = m.accreditation_id
+		WHERE a.name = ? AND m.start_date <= NOW() AND m.end_date > NOW()
+		ORDER BY m.end_date DESC
+		LIMIT 1
+	`, accreditationName).Scan(m)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return
+}
+
+// GetJobActiveMaintenance returns the active maintenance window of the accreditation the job is sent to
+func (db *database) GetJobActiveMaintenance(ctx context.Context, jobId string) (m *model.AccreditationMaintenance, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	m = &model.AccreditationMaintenance{}
+	res := tx.Raw(`
+		SELECT m.*
+		FROM job j
+			JOIN accreditation a ON a.name = j.data->'accreditation'->>'accreditation_name'
+			JOIN accreditation_maintenance m ON m.accreditation_id = a.id
+		WHERE j.id = ? AND m.start_date <= NOW() AND m.end_date > NOW()
+		ORDER BY m.end_date DESC
+		LIMIT 1
+	`, jobId).Scan(m)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return
+}
+
+func (db *database) GetMaintenanceById(ctx context.Context, id string) (m *model.AccreditationMaintenance, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Where("id = ?", id).First(&m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+// GetMaintenances returns maintenance windows ordered by start date, optionally filtered by accreditation name
+func (db *database) GetMaintenances(ctx context.Context, accreditationName string, includeEnded bool) (result []model.AccreditationMaintenance, err error) {
+	tx := db.GetDB().WithContext(ctx).
+		Model(&model.AccreditationMaintenance{}).
+		Select("accreditation_maintenance.*")
+
+	if accreditationName != "" {
+		tx = tx.Joins("JOIN accreditation a ON a.id = accreditation_maintenance.accreditation_id").
+			Where("a.name = ?", accreditationName)
+	}
+
+	if !includeEnded {
+		tx = tx.Where("accreditation_maintenance.end_date > NOW()")
+	}
+
+	err = tx.Order("accreditation_maintenance.start_date").Find(&result).Error
+
+	return
+}
+
+func (db *database) CreateMaintenance(ctx context.Context, m *model.AccreditationMaintenance) (err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Create(m).Error
+
+	return
+}
+
+func (db *database) DeleteMaintenance(ctx context.Context, id string) (err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	res := tx.Delete(&model.AccreditationMaintenance{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		err = ErrNotFound
+	}
+
+	return
+}
+
+// GetEppResultPolicies returns the EPP result policies of a job type and EPP code
+func (db *database) GetEppResultPolicies(ctx context.Context, jobType string, eppCode int32) (result []model.VEppResultPolicy, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Where("job_type_name = ? AND epp_code = ?", jobType, eppCode).Find(&result).Error
+
+	return
+}
+
+// AcquireObjectLease takes the lease of a registry object for the job until the job is no longer processing or the ttl expires.
+// It returns the time left until the lease held by another job expires, zero means the lease was taken.
+func (db *database) AcquireObjectLease(ctx context.Context, objectType string, objectName string, accreditationName string, jobId string, ttl time.Duration) (wait time.Duration, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	var seconds float64
+	err = tx.Raw(
+		"SELECT EXTRACT(EPOCH FROM object_lease_acquire(?, ?, ?, ?, ? * INTERVAL '1 second'))",
+		objectType, objectName, accreditationName, jobId, ttl.Seconds(),
+	).Scan(&seconds).Error
+	if err != nil {
+		return
+	}
+
+	wait = time.Duration(seconds * float64(time.Second))
+
+	return
+}
+
+// ClaimMessage records a message as processed until its ttl expires.
+// It returns false when the message was already processed and its record has not expired.
+func (db *database) ClaimMessage(ctx context.Context, key string, messageType string, ttl time.Duration) (claimed bool, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	res := tx.Exec(`
+		INSERT INTO processed_message(message_key, message_type, expiry_date)
+		VALUES (?, ?, NOW() + ? * INTERVAL '1 second')
+		ON CONFLICT (message_key) DO UPDATE
+		SET message_type = EXCLUDED.message_type,
+			processed_date = NOW(),
+			expiry_date = EXCLUDED.expiry_date
+		WHERE processed_message.expiry_date <= NOW()
+	`, key, messageType, ttl.Seconds())
+	if res.Error != nil {
+		if errors.Is(res.Error, &pgconn.ConnectError{}) {
+			log.Fatal("error claiming message, exiting...", log.Fields{
+				types.LogFieldKeys.Error: res.Error.Error(),
+			})
+		}
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// PurgeProcessedMessages deletes expired processed message records
+func (db *database) PurgeProcessedMessages(ctx context.Context) (int64, error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	res := tx.Exec(`DELETE FROM processed_message WHERE expiry_date <= NOW()`)
+
+	return res.RowsAffected, res.Error
+}
+
+// EndMaintenance ends an active maintenance window now and releases the jobs held until its end,
+// in the order they were created.
+func (db *database) EndMaintenance(ctx context.Context, id string) (result []model.StaleJob, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Raw(`
+		WITH w AS (
+			SELECT m.id, m.end_date AS held_until, a.name AS accreditation_name
+			FROM accreditation_maintenance m
+				JOIN accreditation a ON a.id = m.accreditation_id
+			WHERE m.id = ? AND m.start_date <= NOW() AND m.end_date > NOW()
+			FOR UPDATE OF m
+		), ended AS (
+			UPDATE accreditation_maintenance m
+			SET end_date = NOW()
+			FROM w
+			WHERE m.id = w.id
+		), released AS (
+			UPDATE job j
+			SET start_date = NOW()
+			FROM w, job_status js
+			WHERE js.id = j.status_id
+				AND js.name = 'submitted'
+				AND j.start_date = w.held_until
+				AND j.data->'accreditation'->>'accreditation_name' = w.accreditation_name
+			RETURNING j.id
+		)
+		SELECT
+			j.job_id,
+			j.job_status_name,
+			NOTIFY_EVENT(
+				'job_event',
+				'job_event_notify',
+				JSONB_BUILD_OBJECT(
+					'job_id',j.job_id,
+					'type',j.job_type_name,
+					'status',j.job_status_name,
+					'reference_id',j.reference_id,
+					'reference_table',j.reference_table,
+					'routing_key',j.routing_key,
+					'metadata',
+					CASE WHEN j.data ? 'metadata' 
+					THEN
+					(j.data -> 'metadata')
+					ELSE
+					'{}'::JSONB
+					END
+				)::TEXT
+			)
+		FROM v_job j
+		WHERE job_id IN (SELECT id FROM released)
+		ORDER BY j.created_date
+	`, id).Scan(&result).Error
+
+	return
+}
+
 func (db *database) UpdateOrderItemPlan(ctx context.Context, oip *model.OrderItemPlan) (err error) {
 	tx := db.GetDB().WithContext(ctx)
 
@@ -484,7 +789,12 @@ func (db *database) SetJobStatus(ctx context.Context, job *model.Job, status str
 		job.Info.JobStatusName = &status
 	}
 
-	return db.UpdateJob(ctx, job)
+	err = db.UpdateJob(ctx, job)
+	if err == nil {
+		observeJobStatus(job, status, jrd)
+	}
+
+	return
 }
 
 func (db *database) UpdateJob(ctx context.Context, job *model.Job) (err error) {
@@ -508,6 +818,182 @@ func (db *database) UpdateJob(ctx context.Context, job *model.Job) (err error) {
 	return
 }
 
+// SetJobStartDate moves start date of a submitted job
+func (db *database) SetJobStartDate(ctx context.Context, jobId string, startDate time.Time) (err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Exec(`
+		UPDATE job SET start_date = ?
+		WHERE id = ? AND status_id = tc_id_from_name('job_status', 'submitted')
+	`, startDate, jobId).Error
+	if errors.Is(err, &pgconn.ConnectError{}) {
+		log.Fatal("error updating job start date, exiting...", log.Fields{
+			types.LogFieldKeys.JobID: jobId,
+			types.LogFieldKeys.Error: err.Error(),
+		})
+	}
+
+	return
+}
+
+// GetJobs returns jobs matching the filter, most recent first
+func (db *database) GetJobs(ctx context.Context, filter JobFilter) (result []model.VJob, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	if filter.Status != "" {
+		tx = tx.Where("job_status_name = ?", filter.Status)
+	}
+
+	if filter.Type != "" {
+		tx = tx.Where("job_type_name = ?", filter.Type)
+	}
+
+	if filter.TenantCustomerID != "" {
+		tx = tx.Where("tenant_customer_id = ?", filter.TenantCustomerID)
+	}
+
+	if filter.ReferenceID != "" {
+		tx = tx.Where("reference_id = ?", filter.ReferenceID)
+	}
+
+	if filter.Limit > 0 {
+		tx = tx.Limit(filter.Limit)
+	}
+
+	err = tx.Order("created_date DESC").Find(&result).Error
+
+	return
+}
+
+func (db *database) GetChildJobs(ctx context.Context, parentId string) (result []model.VJob, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Where("job_parent_id = ?", parentId).Order("created_date").Find(&result).Error
+
+	return
+}
+
+// DisableJobRetries prevents a job from being retried when it fails
+func (db *database) DisableJobRetries(ctx context.Context, jobId string) (err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Exec(`UPDATE job SET max_retries = 0 WHERE id = ?`, jobId).Error
+	if errors.Is(err, &pgconn.ConnectError{}) {
+		log.Fatal("error disabling job retries, exiting...", log.Fields{
+			types.LogFieldKeys.JobID: jobId,
+			types.LogFieldKeys.Error: err.Error(),
+		})
+	}
+
+	return
+}
+
+// RescheduleJob resubmits a job after an exponential backoff of its retry interval, or retryBase when it has none,
+// doubled per retry and capped by retryMax. The job is only rescheduled while its retry count is below its own
+// retry budget (max_retries counts attempts), or below maxRetries when it is higher.
+func (db *database) RescheduleJob(ctx context.Context, jobId string, message *string, maxRetries int, retryBase time.Duration, retryMax time.Duration) (rescheduled bool, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	result := tx.Exec(`
+		UPDATE job SET
+			status_id = tc_id_from_name('job_status', 'submitted'),
+			retry_count = retry_count + 1,
+			start_date = NOW() + LEAST(
+				COALESCE(EXTRACT(EPOCH FROM retry_interval), @base) * POWER(2, retry_count),
+				@max
+			) * INTERVAL '1 second',
+			result_message = COALESCE(@message, result_message)
+		WHERE id = @job_id AND retry_count < GREATEST(max_retries - 1, @max_retries)
+	`, map[string]interface{}{
+		"job_id":      jobId,
+		"message":     message,
+		"max_retries": maxRetries,
+		"base":        retryBase.Seconds(),
+		"max":         retryMax.Seconds(),
+	})
+
+	err = result.Error
+	if errors.Is(err, &pgconn.ConnectError{}) {
+		log.Fatal("error rescheduling job, exiting...", log.Fields{
+			types.LogFieldKeys.JobID: jobId,
+			types.LogFieldKeys.Error: err.Error(),
+		})
+	}
+
+	rescheduled = err == nil && result.RowsAffected > 0
+
+	return
+}
+
+// RequeueJob submits a failed job again to be dispatched now, it returns false when the job is not failed
+func (db *database) RequeueJob(ctx context.Context, jobId string) (requeued bool, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Raw(`SELECT job_requeue(?)`, jobId).Scan(&requeued).Error
+	if errors.Is(err, &pgconn.ConnectError{}) {
+		log.Fatal("error requeueing job, exiting...", log.Fields{
+			types.LogFieldKeys.JobID: jobId,
+			types.LogFieldKeys.Error: err.Error(),
+		})
+	}
+
+	return
+}
+
+// ClaimJob moves the job to dispatching and records when it was claimed, claims older than
+// the job scheduler claim timeout are failed by RecoverJobClaims
+func (db *database) ClaimJob(ctx context.Context, job *model.Job) (err error) {
+	status := types.JobStatus.Dispatching
+	statusId := db.GetJobStatusId(status)
+	if statusId == "" {
+		return fmt.Errorf("invalid status %q", status)
+	}
+
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Exec(`UPDATE job SET status_id = ?, claimed_date = NOW() WHERE id = ?`, statusId, job.ID).Error
+	if err != nil {
+		if errors.Is(err, &pgconn.ConnectError{}) {
+			log.Fatal("error claiming job, exiting...", log.Fields{
+				types.LogFieldKeys.JobID: job.ID,
+				types.LogFieldKeys.Error: err.Error(),
+			})
+		}
+
+		return
+	}
+
+	job.StatusID = statusId
+	job.Info.JobStatusName = &status
+
+	return
+}
+
+func (db *database) CreateJobAdminAudit(ctx context.Context, audit *model.JobAdminAudit) (err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Create(audit).Error
+
+	return
+}
+
+func (db *database) GetJobAdminAudits(ctx context.Context, jobId string) (result []model.JobAdminAudit, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Where("job_id = ?", jobId).Order("created_date").Find(&result).Error
+
+	return
+}
+
+// GetJobTypes returns all job types with their routing keys
+func (db *database) GetJobTypes(ctx context.Context) (result []model.JobType, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Order("name").Find(&result).Error
+
+	return
+}
+
 func (db *database) SetProvisionContactHandle(ctx context.Context, id string, handle string) (err error) {
 	tx := db.GetDB().WithContext(ctx)
 
@@ -672,6 +1158,45 @@ func (db *database) CreateDomainRgpStatus(ctx context.Context, drs *model.Domain
 	return
 }
 
+// AutoRenewDomain moves the domain expiry forward to exDate, it reports false when the domain
+// already expires at or after exDate so an auto-renew is only recorded once per expiry
+func (db *database) AutoRenewDomain(ctx context.Context, domainId string, exDate time.Time) (renewed bool, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	res := tx.Model(&model.Domain{}).
+		Where("id = ? AND ry_expiry_date < ?", domainId, exDate).
+		Updates(map[string]any{"ry_expiry_date": exDate, "expiry_date": exDate})
+	if res.Error != nil {
+		if errors.Is(res.Error, &pgconn.ConnectError{}) {
+			log.Fatal("error auto-renewing domain, exiting...", log.Fields{
+				"domain_id":              domainId,
+				types.LogFieldKeys.Error: res.Error.Error(),
+			})
+		}
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// CreateDomainAutoRenewEvent inserts a domain_autorenew event for the tenant owning the domain
+func (db *database) CreateDomainAutoRenewEvent(ctx context.Context, domainId string, exDate time.Time) error {
+	tx := db.GetDB().WithContext(ctx)
+
+	return tx.Exec(`
+		SELECT insert_event(
+			tc.tenant_id,
+			tc_id_from_name('event_type', 'domain_autorenew'),
+			jsonb_build_object('name', d.name, 'expiryDate', ?::TIMESTAMPTZ),
+			d.id,
+			jsonb_build_object('version', '1.0')
+		)
+		FROM domain d
+		JOIN tenant_customer tc ON tc.id = d.tenant_customer_id
+		WHERE d.id = ?
+	`, exDate, domainId).Error
+}
+
 // UpdateProvisionHostingCreate updates provision_hosting_create record based on provided condition
 // Returns ErrNotFound if record not found based on provided condition
 func (db *database) UpdateProvisionHostingCreate(ctx context.Context, upd *model.ProvisionHostingCreate, cond interface{}) error {
@@ -975,6 +1500,20 @@ func (db *database) CreatePollMessage(ctx context.Context, message *model.PollMe
 	return
 }
 
+func (db *database) GetPollMessageById(ctx context.Context, id string) (message *model.PollMessage, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Where("id = ?", id).First(&message).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
 func (db *database) UpdatePollMessageStatus(ctx context.Context, messageId string, status string) (err error) {
 	tx := db.GetDB().WithContext(ctx)
 
@@ -1139,13 +1678,247 @@ func (db *database) GetStaleJobs(ctx context.Context) (result []model.StaleJob,
 					FROM job j 
 						JOIN job_status js ON js.id=j.status_id
 					WHERE js.name = 'submitted' AND j.start_date < NOW()
+						-- jobs which failed to publish are retried with backoff
+						AND NOT EXISTS (
+							SELECT 1 FROM job_dispatch jd WHERE jd.job_id = j.id AND jd.last_error IS NOT NULL
+						)
 					FOR UPDATE SKIP LOCKED
 				)
+				-- held jobs are released in the order they were created
+				ORDER BY j.created_date
 			`).Scan(&result).Error
 
 	return
 }
 
+// GetSubmittedJobsSince re-notifies submitted jobs that became due since the given time.
+// Used to replay job events that were missed while the listener was disconnected.
+func (db *database) GetSubmittedJobsSince(ctx context.Context, since time.Time) (result []model.StaleJob, err error) {
+	tx := db.GetDB().WithContext(ctx)
+	err = tx.Raw(`
+				  	SELECT
+						j.job_id,
+						j.job_status_name,
+						NOTIFY_EVENT(
+							'job_event',
+							'job_event_notify',
+							JSONB_BUILD_OBJECT(
+								'job_id',j.job_id,
+								'type',j.job_type_name,
+								'status',j.job_status_name,
+								'reference_id',j.reference_id,
+								'reference_table',j.reference_table,
+								'routing_key',j.routing_key,
+								'metadata',
+								CASE WHEN j.data ? 'metadata' 
+								THEN
+								(j.data -> 'metadata')
+								ELSE
+								'{}'::JSONB
+								END
+							)::TEXT
+						)
+					FROM v_job j
+					WHERE job_id IN (
+						SELECT 
+							j.id
+						FROM job j 
+							JOIN job_status js ON js.id=j.status_id
+						WHERE js.name = 'submitted'
+							AND j.start_date <= NOW()
+							AND (j.created_date >= ? OR j.updated_date >= ?)
+						FOR UPDATE SKIP LOCKED
+					)
+				`, since, since).Scan(&result).Error
+
+	return
+}
+
+// GetUpcomingJobs returns submitted jobs which start in the future, up to the given time
+func (db *database) GetUpcomingJobs(ctx context.Context, until time.Time) (result []model.UpcomingJob, err error) {
+	tx := db.GetDB().WithContext(ctx)
+	err = tx.Raw(`
+				SELECT
+					j.id AS job_id,
+					j.start_date
+				FROM job j
+					JOIN job_status js ON js.id=j.status_id
+				WHERE js.name = 'submitted' AND j.start_date > NOW() AND j.start_date <= ?
+				ORDER BY j.start_date
+			`, until).Scan(&result).Error
+
+	return
+}
+
+// NotifyDueJobs re-notifies the given jobs if they are still submitted and their start date has been reached
+func (db *database) NotifyDueJobs(ctx context.Context, jobIds []string) (result []model.StaleJob, err error) {
+	if len(jobIds) == 0 {
+		return
+	}
+
+	tx := db.GetDB().WithContext(ctx)
+	err = tx.Raw(`
+				  	SELECT
+						j.job_id,
+						j.job_status_name,
+						NOTIFY_EVENT(
+							'job_event',
+							'job_event_notify',
+							JSONB_BUILD_OBJECT(
+								'job_id',j.job_id,
+								'type',j.job_type_name,
+								'status',j.job_status_name,
+								'reference_id',j.reference_id,
+								'reference_table',j.reference_table,
+								'routing_key',j.routing_key,
+								'metadata',
+								CASE WHEN j.data ? 'metadata' 
+								THEN
+								(j.data -> 'metadata')
+								ELSE
+								'{}'::JSONB
+								END
+							)::TEXT
+						)
+					FROM v_job j
+					WHERE job_id IN (
+						SELECT 
+							j.id
+						FROM job j 
+							JOIN job_status js ON js.id=j.status_id
+						WHERE j.id IN ? AND js.name = 'submitted' AND j.start_date <= NOW()
+						FOR UPDATE SKIP LOCKED
+					)
+					ORDER BY j.created_date
+				`, jobIds).Scan(&result).Error
+
+	return
+}
+
+// GetFailedDispatchJobs re-notifies submitted jobs whose last publish to the message bus failed
+// and whose backoff has elapsed.
+func (db *database) GetFailedDispatchJobs(ctx context.Context) (result []model.StaleJob, err error) {
+	tx := db.GetDB().WithContext(ctx)
+	err = tx.Raw(`
+				  	SELECT
+						j.job_id,
+						j.job_status_name,
+						NOTIFY_EVENT(
+							'job_event',
+							'job_event_notify',
+							JSONB_BUILD_OBJECT(
+								'job_id',j.job_id,
+								'type',j.job_type_name,
+								'status',j.job_status_name,
+								'reference_id',j.reference_id,
+								'reference_table',j.reference_table,
+								'routing_key',j.routing_key,
+								'metadata',
+								CASE WHEN j.data ? 'metadata' 
+								THEN
+								(j.data -> 'metadata')
+								ELSE
+								'{}'::JSONB
+								END
+							)::TEXT
+						)
+					FROM v_job j
+					WHERE job_id IN (
+						SELECT 
+							j.id
+						FROM job j 
+							JOIN job_status js ON js.id=j.status_id
+							JOIN job_dispatch jd ON jd.job_id=j.id
+						WHERE js.name = 'submitted'
+							AND jd.last_error IS NOT NULL
+							AND jd.next_attempt_date <= NOW()
+						FOR UPDATE OF j SKIP LOCKED
+					)
+				`).Scan(&result).Error
+
+	return
+}
+
+// RecoverJobClaims fails jobs claimed for longer than timeout without retrying them. A claim is left
+// behind when its worker stops before recording the outcome of the command, which may already have
+// reached the registry, so the job is not sent again.
+func (db *database) RecoverJobClaims(ctx context.Context, timeout time.Duration) (result []model.StaleJob, err error) {
+	tx := db.GetDB().WithContext(ctx)
+	err = tx.Raw(`
+		UPDATE job SET
+			status_id = tc_id_from_name('job_status', 'failed'),
+			max_retries = 0,
+			result_message = 'job claim expired, the command may have reached the registry',
+			claimed_date = NULL
+		WHERE id IN (
+			SELECT j.id
+			FROM job j
+				JOIN job_status js ON js.id=j.status_id
+			WHERE js.name = 'dispatching'
+				AND j.claimed_date <= NOW() - ? * INTERVAL '1 second'
+			FOR UPDATE OF j SKIP LOCKED
+		)
+		RETURNING id AS job_id, 'failed' AS job_status_name
+	`, timeout.Seconds()).Scan(&result).Error
+
+	return
+}
+
+// SetJobDispatched records that job notification was confirmed by the message bus
+func (db *database) SetJobDispatched(ctx context.Context, jobId string) (jd *model.JobDispatch, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Raw(`
+		INSERT INTO job_dispatch (job_id, attempt_count, dispatched_date, last_attempt_date, next_attempt_date, last_error)
+		VALUES (?, 1, NOW(), NOW(), NULL, NULL)
+		ON CONFLICT (job_id) DO UPDATE SET
+			attempt_count = job_dispatch.attempt_count + 1,
+			dispatched_date = NOW(),
+			last_attempt_date = NOW(),
+			next_attempt_date = NULL,
+			last_error = NULL
+		RETURNING *
+	`, jobId).Scan(&jd).Error
+	if errors.Is(err, &pgconn.ConnectError{}) {
+		log.Fatal("error recording job dispatch, exiting...", log.Fields{
+			types.LogFieldKeys.JobID: jobId,
+			types.LogFieldKeys.Error: err.Error(),
+		})
+	}
+
+	return
+}
+
+// SetJobDispatchFailed records failed publish of job notification and schedules next attempt
+// with exponential backoff: retryBase * 2^(attempt-1), capped by retryMax
+func (db *database) SetJobDispatchFailed(ctx context.Context, jobId string, reason string, retryBase time.Duration, retryMax time.Duration) (jd *model.JobDispatch, err error) {
+	tx := db.GetDB().WithContext(ctx)
+
+	err = tx.Raw(`
+		INSERT INTO job_dispatch (job_id, attempt_count, last_attempt_date, next_attempt_date, last_error)
+		VALUES (@job_id, 1, NOW(), NOW() + LEAST(@base, @max) * INTERVAL '1 second', @reason)
+		ON CONFLICT (job_id) DO UPDATE SET
+			attempt_count = job_dispatch.attempt_count + 1,
+			last_attempt_date = NOW(),
+			next_attempt_date = NOW() + LEAST(@base * POWER(2, job_dispatch.attempt_count), @max) * INTERVAL '1 second',
+			last_error = @reason
+		RETURNING *
+	`, map[string]interface{}{
+		"job_id": jobId,
+		"reason": reason,
+		"base":   retryBase.Seconds(),
+		"max":    retryMax.Seconds(),
+	}).Scan(&jd).Error
+	if errors.Is(err, &pgconn.ConnectError{}) {
+		log.Fatal("error recording job dispatch failure, exiting...", log.Fields{
+			types.LogFieldKeys.JobID: jobId,
+			types.LogFieldKeys.Error: err.Error(),
+		})
+	}
+
+	return
+}
+
 func (db *database) GetProvisionDomainTransferInRequest(ctx context.Context, pdtr *model.ProvisionDomainTransferInRequest) (result *model.ProvisionDomainTransferInRequest, err error) {
 	tx := db.gorm.WithContext(ctx)
 
@@ -352,6 +352,101 @@ func (s *DatabaseTestSuite) TestSetJobStatusInvalidStatus() {
 
 }
 
+func (s *DatabaseTestSuite) TestRequeueJob() {
+	// the reference row of the test job does not exist
+	jobId, err := insertTestJob(s.db, getTestJobData(), types.JobStatus.Failed)
+	s.NoError(err, "error inserting test job")
+
+	// a failed job is only submitted again through job_requeue
+	err = s.db.GetDB().Exec(`UPDATE job SET status_id = tc_id_from_name('job_status', 'submitted') WHERE id = ?`, jobId).Error
+	s.Error(err, "failed job must not be updated")
+
+	requeued, err := s.db.RequeueJob(s.ctx, jobId)
+	s.NoError(err, "error requeueing job")
+	s.True(requeued)
+
+	job, err := s.db.GetJobById(s.ctx, jobId, false)
+	s.NoError(err, "error getting job by id")
+	s.Equal(types.JobStatus.Submitted, *job.Info.JobStatusName)
+	s.Nil(job.EndDate)
+
+	// the job is no longer failed
+	requeued, err = s.db.RequeueJob(s.ctx, jobId)
+	s.NoError(err, "error requeueing job")
+	s.False(requeued)
+}
+
+func (s *DatabaseTestSuite) TestRequeueJobFinalReference() {
+	provisionContact, err := insertTestProvisionContact(s.db)
+	s.NoError(err, "error inserting test provision contact record")
+
+	tx := s.db.GetDB()
+
+	var jobId string
+	err = tx.Raw(`SELECT id FROM job WHERE reference_id = ?`, provisionContact.ID).Scan(&jobId).Error
+	s.NoError(err, "error getting provision contact job")
+
+	// failing the job fails the provision contact as well
+	err = tx.Exec(`UPDATE job SET status_id = tc_id_from_name('job_status', 'failed') WHERE id = ?`, jobId).Error
+	s.NoError(err, "error failing job")
+
+	requeued, err := s.db.RequeueJob(s.ctx, jobId)
+	s.NoError(err, "error requeueing job")
+	s.False(requeued)
+
+	job, err := s.db.GetJobById(s.ctx, jobId, false)
+	s.NoError(err, "error getting job by id")
+	s.Equal(types.JobStatus.Failed, *job.Info.JobStatusName)
+}
+
+func (s *DatabaseTestSuite) TestRequeueJobFinalParent() {
+	parentId, err := insertTestJob(s.db, getTestJobData(), "")
+	s.NoError(err, "error inserting test job")
+
+	tenantCustomerId, err := getTenantCustomerId(s.db)
+	s.NoError(err, "error getting tenant customer")
+
+	tx := s.db.GetDB()
+
+	var childId string
+	err = tx.Raw(
+		`INSERT INTO job(tenant_customer_id, type_id, parent_id) VALUES (?, tc_id_from_name('job_type', 'provision_domain_contact_update'), ?) RETURNING id`,
+		tenantCustomerId,
+		parentId,
+	).Scan(&childId).Error
+	s.NoError(err, "error inserting child job")
+
+	// the child fails hard, failing its parent
+	err = tx.Exec(`UPDATE job SET status_id = tc_id_from_name('job_status', 'failed') WHERE id = ?`, childId).Error
+	s.NoError(err, "error failing child job")
+
+	parent, err := s.db.GetJobById(s.ctx, parentId, false)
+	s.NoError(err, "error getting job by id")
+	s.Equal(types.JobStatus.Failed, *parent.Info.JobStatusName)
+
+	requeued, err := s.db.RequeueJob(s.ctx, childId)
+	s.NoError(err, "error requeueing job")
+	s.False(requeued)
+}
+
+func (s *DatabaseTestSuite) TestRecoverJobClaims() {
+	jobId, err := insertTestJob(s.db, getTestJobData(), types.JobStatus.Dispatching)
+	s.NoError(err, "error inserting test job")
+
+	err = s.db.GetDB().Exec(`UPDATE job SET claimed_date = NOW() - INTERVAL '10 minutes' WHERE id = ?`, jobId).Error
+	s.NoError(err, "error setting job claimed date")
+
+	jobs, err := s.db.RecoverJobClaims(s.ctx, 5*time.Minute)
+	s.NoError(err, "error recovering job claims")
+	s.Contains(jobs, model.StaleJob{JobID: jobId, JobStatusName: types.JobStatus.Failed})
+
+	// the command may have reached the registry, the job is not retried
+	job, err := s.db.GetJobById(s.ctx, jobId, false)
+	s.NoError(err, "error getting job by id")
+	s.Equal(types.JobStatus.Failed, *job.Info.JobStatusName)
+	s.NotNil(job.ResultMessage)
+}
+
 func (s *DatabaseTestSuite) TestSetProvisionContactHandle() {
 	testHandle := "qwertyuiop"
 
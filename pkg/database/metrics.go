@@ -0,0 +1,63 @@
+package database
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	commonmessages "github.com/tucowsinc/tdp-messages-go/message/common"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// registryResponder is implemented by registry response messages stored as job result data
+type registryResponder interface {
+	GetRegistryResponse() *commonmessages.RegistryResponse
+}
+
+// observeJobStatus records metrics for a job which status was updated
+func observeJobStatus(job *model.Job, status string, jrd *types.JobResultData) {
+	if job.Info == nil {
+		return
+	}
+
+	if jrd != nil {
+		if msg, ok := jrd.Message.(registryResponder); ok && msg.GetRegistryResponse() != nil {
+			metrics.EppResponses.WithLabelValues(
+				jobAccreditationName(job.Info),
+				strconv.Itoa(int(msg.GetRegistryResponse().GetEppCode())),
+			).Inc()
+		}
+	}
+
+	switch status {
+	case types.JobStatus.Completed, types.JobStatus.Failed, types.JobStatus.CompletedConditionally:
+	default:
+		return
+	}
+
+	jobType := types.SafeDeref(job.Info.JobTypeName)
+
+	metrics.JobsHandled.WithLabelValues(jobType, status).Inc()
+
+	if job.Info.StartDate != nil {
+		metrics.JobDuration.WithLabelValues(jobType, status).Observe(time.Since(*job.Info.StartDate).Seconds())
+	}
+}
+
+// jobAccreditationName returns the accreditation name from job data if present
+func jobAccreditationName(job *model.VJob) string {
+	var data struct {
+		Accreditation struct {
+			AccreditationName string `json:"accreditation_name"`
+		} `json:"accreditation"`
+	}
+
+	if err := json.Unmarshal(job.Data, &data); err != nil {
+		return ""
+	}
+
+	return data.Accreditation.AccreditationName
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -78,6 +79,66 @@ func (m *MockDatabase) GetAccreditationByName(ctx context.Context, name string)
 	return args.Get(0).(*model.Accreditation), args.Error(1)
 }
 
+func (m *MockDatabase) AcquireRateLimitToken(ctx context.Context, accreditationName string) (wait time.Duration, err error) {
+	args := m.Called(ctx, accreditationName)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockDatabase) GetActiveMaintenance(ctx context.Context, accreditationName string) (*model.AccreditationMaintenance, error) {
+	args := m.Called(ctx, accreditationName)
+	return args.Get(0).(*model.AccreditationMaintenance), args.Error(1)
+}
+
+func (m *MockDatabase) GetJobActiveMaintenance(ctx context.Context, jobId string) (*model.AccreditationMaintenance, error) {
+	args := m.Called(ctx, jobId)
+	return args.Get(0).(*model.AccreditationMaintenance), args.Error(1)
+}
+
+func (m *MockDatabase) GetMaintenanceById(ctx context.Context, id string) (*model.AccreditationMaintenance, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.AccreditationMaintenance), args.Error(1)
+}
+
+func (m *MockDatabase) GetMaintenances(ctx context.Context, accreditationName string, includeEnded bool) ([]model.AccreditationMaintenance, error) {
+	args := m.Called(ctx, accreditationName, includeEnded)
+	return args.Get(0).([]model.AccreditationMaintenance), args.Error(1)
+}
+
+func (m *MockDatabase) CreateMaintenance(ctx context.Context, maintenance *model.AccreditationMaintenance) error {
+	args := m.Called(ctx, maintenance)
+	return args.Error(0)
+}
+
+func (m *MockDatabase) DeleteMaintenance(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDatabase) EndMaintenance(ctx context.Context, id string) ([]model.StaleJob, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.StaleJob), args.Error(1)
+}
+
+func (m *MockDatabase) GetEppResultPolicies(ctx context.Context, jobType string, eppCode int32) ([]model.VEppResultPolicy, error) {
+	args := m.Called(ctx, jobType, eppCode)
+	return args.Get(0).([]model.VEppResultPolicy), args.Error(1)
+}
+
+func (m *MockDatabase) AcquireObjectLease(ctx context.Context, objectType string, objectName string, accreditationName string, jobId string, ttl time.Duration) (wait time.Duration, err error) {
+	args := m.Called(ctx, objectType, objectName, accreditationName, jobId, ttl)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockDatabase) ClaimMessage(ctx context.Context, key string, messageType string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, key, messageType, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDatabase) PurgeProcessedMessages(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockDatabase) GetJobById(ctx context.Context, id string, lock bool) (job *model.Job, err error) {
 	args := m.Called(ctx, id, lock)
 	return args.Get(0).(*model.Job), args.Error(1)
@@ -98,6 +159,56 @@ func (m *MockDatabase) UpdateJob(ctx context.Context, job *model.Job) error {
 	return args.Error(0)
 }
 
+func (m *MockDatabase) SetJobStartDate(ctx context.Context, jobId string, startDate time.Time) error {
+	args := m.Called(ctx, jobId, startDate)
+	return args.Error(0)
+}
+
+func (m *MockDatabase) GetJobs(ctx context.Context, filter JobFilter) ([]model.VJob, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.VJob), args.Error(1)
+}
+
+func (m *MockDatabase) GetChildJobs(ctx context.Context, parentId string) ([]model.VJob, error) {
+	args := m.Called(ctx, parentId)
+	return args.Get(0).([]model.VJob), args.Error(1)
+}
+
+func (m *MockDatabase) DisableJobRetries(ctx context.Context, jobId string) error {
+	args := m.Called(ctx, jobId)
+	return args.Error(0)
+}
+
+func (m *MockDatabase) RescheduleJob(ctx context.Context, jobId string, message *string, maxRetries int, retryBase time.Duration, retryMax time.Duration) (bool, error) {
+	args := m.Called(ctx, jobId, message, maxRetries, retryBase, retryMax)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDatabase) RequeueJob(ctx context.Context, jobId string) (bool, error) {
+	args := m.Called(ctx, jobId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDatabase) ClaimJob(ctx context.Context, job *model.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockDatabase) CreateJobAdminAudit(ctx context.Context, audit *model.JobAdminAudit) error {
+	args := m.Called(ctx, audit)
+	return args.Error(0)
+}
+
+func (m *MockDatabase) GetJobAdminAudits(ctx context.Context, jobId string) ([]model.JobAdminAudit, error) {
+	args := m.Called(ctx, jobId)
+	return args.Get(0).([]model.JobAdminAudit), args.Error(1)
+}
+
+func (m *MockDatabase) GetJobTypes(ctx context.Context) ([]model.JobType, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.JobType), args.Error(1)
+}
+
 func (m *MockDatabase) SetProvisionContactHandle(ctx context.Context, id string, handle string) error {
 	args := m.Called(ctx, id, handle)
 	return args.Error(0)
@@ -178,6 +289,16 @@ func (m *MockDatabase) CreateDomainRgpStatus(ctx context.Context, drs *model.Dom
 	return args.Error(0)
 }
 
+func (m *MockDatabase) AutoRenewDomain(ctx context.Context, domainId string, exDate time.Time) (bool, error) {
+	args := m.Called(ctx, domainId, exDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDatabase) CreateDomainAutoRenewEvent(ctx context.Context, domainId string, exDate time.Time) error {
+	args := m.Called(ctx, domainId, exDate)
+	return args.Error(0)
+}
+
 func (m *MockDatabase) GetProvisionDomainTransferInRequest(ctx context.Context, domain *model.ProvisionDomainTransferInRequest) (*model.ProvisionDomainTransferInRequest, error) {
 	args := m.Called(ctx, domain)
 	return args.Get(0).(*model.ProvisionDomainTransferInRequest), args.Error(1)
@@ -293,11 +414,23 @@ func (m *MockDatabase) GetTLDSetting(ctx context.Context, accreditationTldID str
 	return args.Get(0).(*model.VAttribute), args.Error(1)
 }
 
+// OnTLDSettings mocks GetTLDSetting to return the settings of the accreditation tld
+func (m *MockDatabase) OnTLDSettings(accreditationTldID string, settings map[string]string) {
+	for key, value := range settings {
+		m.On("GetTLDSetting", mock.Anything, accreditationTldID, key).Return(&model.VAttribute{Value: value}, nil)
+	}
+}
+
 func (m *MockDatabase) CreatePollMessage(ctx context.Context, message *model.PollMessage) (err error) {
 	args := m.Called(ctx, message)
 	return args.Error(0)
 }
 
+func (m *MockDatabase) GetPollMessageById(ctx context.Context, id string) (*model.PollMessage, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.PollMessage), args.Error(1)
+}
+
 func (m *MockDatabase) UpdatePollMessageStatus(ctx context.Context, messageId string, status string) error {
 	args := m.Called(ctx, messageId, status)
 	return args.Error(0)
@@ -313,6 +446,41 @@ func (m *MockDatabase) GetStaleJobs(ctx context.Context) ([]model.StaleJob, erro
 	return args.Get(0).([]model.StaleJob), args.Error(1)
 }
 
+func (m *MockDatabase) GetUpcomingJobs(ctx context.Context, until time.Time) ([]model.UpcomingJob, error) {
+	args := m.Called(ctx, until)
+	return args.Get(0).([]model.UpcomingJob), args.Error(1)
+}
+
+func (m *MockDatabase) NotifyDueJobs(ctx context.Context, jobIds []string) ([]model.StaleJob, error) {
+	args := m.Called(ctx, jobIds)
+	return args.Get(0).([]model.StaleJob), args.Error(1)
+}
+
+func (m *MockDatabase) GetFailedDispatchJobs(ctx context.Context) ([]model.StaleJob, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.StaleJob), args.Error(1)
+}
+
+func (m *MockDatabase) RecoverJobClaims(ctx context.Context, timeout time.Duration) ([]model.StaleJob, error) {
+	args := m.Called(ctx, timeout)
+	return args.Get(0).([]model.StaleJob), args.Error(1)
+}
+
+func (m *MockDatabase) SetJobDispatched(ctx context.Context, jobId string) (*model.JobDispatch, error) {
+	args := m.Called(ctx, jobId)
+	return args.Get(0).(*model.JobDispatch), args.Error(1)
+}
+
+func (m *MockDatabase) SetJobDispatchFailed(ctx context.Context, jobId string, reason string, retryBase time.Duration, retryMax time.Duration) (*model.JobDispatch, error) {
+	args := m.Called(ctx, jobId, reason, retryBase, retryMax)
+	return args.Get(0).(*model.JobDispatch), args.Error(1)
+}
+
+func (m *MockDatabase) GetSubmittedJobsSince(ctx context.Context, since time.Time) ([]model.StaleJob, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]model.StaleJob), args.Error(1)
+}
+
 func (m *MockDatabase) GetTransferStatusId(name string) string {
 	args := m.Called(name)
 	return args.String(0)
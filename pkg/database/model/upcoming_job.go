@@ -0,0 +1,8 @@
+package model
+
+import "time"
+
+type UpcomingJob struct {
+	JobID     string
+	StartDate time.Time
+}
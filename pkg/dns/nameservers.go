@@ -0,0 +1,198 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tucowsinc/tdp-shared-go/dns"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tldsetting"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// TLD settings of the nameserver check
+const (
+	NameserverCheckSetting              = "tld.dns.nameserver_check"
+	NameserverCheckAuthoritativeSetting = "tld.dns.nameserver_check_authoritative"
+)
+
+// Values of the nameserver check TLD setting
+const (
+	NameserverCheckOff  = "off"
+	NameserverCheckWarn = "warn"
+	NameserverCheckFail = "fail"
+)
+
+// ErrNameserverCheck is wrapped by the error of a nameserver check finding nameservers which do not resolve,
+// have no glue address or are not authoritative for the domain
+var ErrNameserverCheck = joberrors.Permanent(errors.New("nameserver check failed"))
+
+// NameserverPolicy is the nameserver check of a TLD
+type NameserverPolicy struct {
+	// Mode is off, warn to flag the job with a warning or fail to fail the job
+	Mode string
+	// Authoritative requires every nameserver address to answer authoritatively for the domain
+	Authoritative bool
+}
+
+// LoadNameserverPolicy reads the nameserver check of the accreditation tld, it is off when not set
+func LoadNameserverPolicy(ctx context.Context, db database.Database, accreditationTldId string) (p NameserverPolicy, err error) {
+	p.Mode = NameserverCheckOff
+
+	mode, err := tldsetting.Get(ctx, db, accreditationTldId, NameserverCheckSetting)
+	if err != nil {
+		return
+	}
+
+	switch mode {
+	case "", NameserverCheckOff:
+		return
+	case NameserverCheckWarn, NameserverCheckFail:
+		p.Mode = mode
+	default:
+		return p, tldsetting.Invalid(NameserverCheckSetting, mode)
+	}
+
+	p.Authoritative, err = tldsetting.Bool(ctx, db, accreditationTldId, NameserverCheckAuthoritativeSetting)
+
+	return
+}
+
+// NameserverChecker checks the nameservers of a domain before they are sent to the registry
+type NameserverChecker struct {
+	// lookup returns the IPv6 addresses of host when ipv6 is set, its IPv4 addresses otherwise
+	lookup func(ctx context.Context, host string, ipv6 bool) ([]string, error)
+	// authoritative tells if the server at address answers for zone
+	authoritative func(ctx context.Context, address string, zone string) (bool, error)
+}
+
+// NewNameserverChecker creates a nameserver checker resolving hosts with resolver. Authoritative answers are
+// checked by querying the SOA of the domain directly from every nameserver address, without recursion.
+func NewNameserverChecker(resolver dns.IDnsResolver, config config.Config) *NameserverChecker {
+	return &NameserverChecker{
+		lookup: func(ctx context.Context, host string, ipv6 bool) ([]string, error) {
+			recordType := dns.RecordTypes.A
+			if ipv6 {
+				recordType = dns.RecordTypes.AAAA
+			}
+
+			records, err := resolver.Resolve(ctx, host, recordType)
+			if err != nil {
+				return nil, err
+			}
+
+			addresses := make([]string, 0, len(records))
+			for _, record := range records {
+				addresses = append(addresses, record.Value)
+			}
+
+			return addresses, nil
+		},
+		authoritative: func(ctx context.Context, address string, zone string) (bool, error) {
+			options := []dns.OptionsFunc{dns.WithServer(address), dns.WithPort("53")}
+			if config.DNSCheckTimeout != 0 {
+				options = append(options, dns.WithTimeout(time.Duration(config.DNSCheckTimeout)*time.Second))
+			}
+
+			server, err := dns.New(options...)
+			if err != nil {
+				return false, err
+			}
+
+			records, err := server.Resolve(ctx, zone, dns.RecordTypes.SOA)
+			if err != nil {
+				return false, err
+			}
+
+			return len(records) > 0, nil
+		},
+	}
+}
+
+// Check checks every nameserver of domain resolves, or has glue addresses when it is within the domain.
+// With authoritative set every nameserver address must also answer for the domain.
+//
+// It returns an error wrapping ErrNameserverCheck and listing the problems found. When no problem is found
+// but a query failed, as on a timeout or a server failure, it returns a transient error so the check is
+// run again later.
+func (c *NameserverChecker) Check(ctx context.Context, domain string, nameservers []types.Nameserver, authoritative bool) error {
+	zone := normalizeName(domain)
+
+	var problems, failures []string
+	for _, ns := range nameservers {
+		host := normalizeName(ns.Name)
+
+		addresses := ns.IpAddresses
+		if host == zone || strings.HasSuffix(host, "."+zone) {
+			if len(addresses) == 0 {
+				problems = append(problems, fmt.Sprintf("%s is within %s and has no glue address", host, zone))
+				continue
+			}
+		} else {
+			resolved, err := c.lookupHost(ctx, host)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", host, err))
+				continue
+			}
+
+			if len(resolved) == 0 {
+				problems = append(problems, fmt.Sprintf("%s does not resolve", host))
+				continue
+			}
+
+			addresses = resolved
+		}
+
+		if !authoritative {
+			continue
+		}
+
+		for _, address := range addresses {
+			ok, err := c.authoritative(ctx, address, zone)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s (%s): %v", host, address, err))
+				continue
+			}
+
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s (%s) is not authoritative for %s", host, address, zone))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrNameserverCheck, strings.Join(problems, "; "))
+	}
+
+	if len(failures) > 0 {
+		return joberrors.Transient(fmt.Errorf("nameserver check could not complete: %s", strings.Join(failures, "; ")))
+	}
+
+	return nil
+}
+
+// lookupHost returns the IPv4 and IPv6 addresses of host. A host is resolved when either family has addresses,
+// the lookup error is only returned when neither has.
+func (c *NameserverChecker) lookupHost(ctx context.Context, host string) ([]string, error) {
+	v4, errV4 := c.lookup(ctx, host, false)
+	v6, errV6 := c.lookup(ctx, host, true)
+
+	if addresses := append(v4, v6...); len(addresses) > 0 {
+		return addresses, nil
+	}
+
+	if errV4 != nil {
+		return nil, errV4
+	}
+
+	return nil, errV6
+}
+
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
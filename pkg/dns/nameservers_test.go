@@ -0,0 +1,129 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// newTestChecker returns a checker resolving hosts from hosts, addresses listed in authoritative answer for the zone.
+// Lookups of timeout.example.net and of the IPv6 addresses of ns1.example.net fail, as does the query of 192.0.2.99.
+func newTestChecker(hosts map[string][]string, authoritative map[string]bool) *NameserverChecker {
+	return &NameserverChecker{
+		lookup: func(ctx context.Context, host string, ipv6 bool) ([]string, error) {
+			if host == "timeout.example.net" || (host == "ns1.example.net" && ipv6) {
+				return nil, errors.New("i/o timeout")
+			}
+
+			var addresses []string
+			for _, address := range hosts[host] {
+				if strings.Contains(address, ":") == ipv6 {
+					addresses = append(addresses, address)
+				}
+			}
+			return addresses, nil
+		},
+		authoritative: func(ctx context.Context, address string, zone string) (bool, error) {
+			if address == "192.0.2.99" {
+				return false, errors.New("server failure")
+			}
+			return authoritative[address], nil
+		},
+	}
+}
+
+func TestNameserverCheck(t *testing.T) {
+	ctx := context.Background()
+	checker := newTestChecker(
+		map[string][]string{
+			"ns1.example.net": {"192.0.2.1"},
+			"ns2.example.net": {"192.0.2.2", "2001:db8::2"},
+		},
+		map[string]bool{"192.0.2.1": true, "192.0.2.2": true, "2001:db8::2": true, "192.0.2.10": true},
+	)
+
+	nameservers := []types.Nameserver{
+		{Name: "NS1.example.net."},
+		{Name: "ns2.example.net"},
+		{Name: "ns1.example.com", IpAddresses: []string{"192.0.2.10"}},
+	}
+
+	// ns1.example.net resolves from its IPv4 addresses although its IPv6 lookup fails
+	require.NoError(t, checker.Check(ctx, "example.com", nameservers, false))
+	require.NoError(t, checker.Check(ctx, "Example.COM.", nameservers, true))
+
+	tests := []struct {
+		name       string
+		nameserver types.Nameserver
+		problem    string
+	}{
+		{"not resolving", types.Nameserver{Name: "ns3.example.net"}, "ns3.example.net does not resolve"},
+		{"missing glue", types.Nameserver{Name: "ns2.example.com"}, "ns2.example.com is within example.com and has no glue address"},
+		{"lame delegation", types.Nameserver{Name: "ns3.example.com", IpAddresses: []string{"192.0.2.30"}}, "ns3.example.com (192.0.2.30) is not authoritative for example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.Check(ctx, "example.com", append(nameservers, tt.nameserver), true)
+			require.ErrorIs(t, err, ErrNameserverCheck)
+			require.False(t, joberrors.IsTransient(err))
+			require.EqualError(t, err, "nameserver check failed: "+tt.problem)
+		})
+	}
+
+	// authoritative answers are only checked when required
+	require.NoError(t, checker.Check(ctx, "example.com", []types.Nameserver{tests[2].nameserver}, false))
+
+	// every problem is reported
+	err := checker.Check(ctx, "example.com", []types.Nameserver{tests[0].nameserver, tests[1].nameserver}, true)
+	require.EqualError(t, err, "nameserver check failed: ns3.example.net does not resolve; ns2.example.com is within example.com and has no glue address")
+
+	// failed queries are transient, unless a problem was found
+	timeout := types.Nameserver{Name: "timeout.example.net"}
+	servfail := types.Nameserver{Name: "ns4.example.com", IpAddresses: []string{"192.0.2.99"}}
+
+	err = checker.Check(ctx, "example.com", append(nameservers, timeout, servfail), true)
+	require.NotErrorIs(t, err, ErrNameserverCheck)
+	require.True(t, joberrors.IsTransient(err))
+	require.EqualError(t, err, "nameserver check could not complete: timeout.example.net: i/o timeout; ns4.example.com (192.0.2.99): server failure")
+
+	err = checker.Check(ctx, "example.com", []types.Nameserver{timeout, tests[0].nameserver}, false)
+	require.ErrorIs(t, err, ErrNameserverCheck)
+	require.False(t, joberrors.IsTransient(err))
+}
+
+func TestLoadNameserverPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	db := &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{
+		NameserverCheckSetting:              NameserverCheckFail,
+		NameserverCheckAuthoritativeSetting: "true",
+	})
+
+	p, err := LoadNameserverPolicy(ctx, db, "acc-tld")
+	require.NoError(t, err)
+	require.Equal(t, NameserverPolicy{Mode: NameserverCheckFail, Authoritative: true}, p)
+
+	db = &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{NameserverCheckSetting: NameserverCheckOff})
+
+	p, err = LoadNameserverPolicy(ctx, db, "acc-tld")
+	require.NoError(t, err)
+	require.Equal(t, NameserverPolicy{Mode: NameserverCheckOff}, p)
+	db.AssertNotCalled(t, "GetTLDSetting", ctx, "acc-tld", NameserverCheckAuthoritativeSetting)
+
+	db = &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{NameserverCheckSetting: "strict"})
+
+	_, err = LoadNameserverPolicy(ctx, db, "acc-tld")
+	require.EqualError(t, err, `invalid TLD setting tld.dns.nameserver_check value "strict"`)
+	require.False(t, joberrors.IsTransient(err))
+}
@@ -0,0 +1,148 @@
+package dnssec
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// ErrInvalid is wrapped by every DNSSEC data validation error
+var ErrInvalid = joberrors.Permanent(errors.New("invalid dnssec data"))
+
+// ErrKeyDataRequired is returned when DS data must be converted to key data but lacks its child key data
+var ErrKeyDataRequired = fmt.Errorf("%w: registry supports key data only and ds data has no key data", ErrInvalid)
+
+// DNSKEY protocol and flags, RFC 4034 section 2.1
+const (
+	Protocol = 3
+	FlagZone = 256
+	FlagSEP  = 1
+)
+
+// DS digest types, RFC 4034, RFC 4509 and RFC 6605
+const (
+	DigestSHA1   = 1
+	DigestSHA256 = 2
+	DigestSHA384 = 4
+)
+
+// DNSKEY algorithm RSAMD5 which key tag is computed differently, RFC 4034 appendix B.1
+const algorithmRSAMD5 = 1
+
+// digestSizes holds the digest length in bytes of the supported digest types
+var digestSizes = map[int]int{
+	DigestSHA1:   sha1.Size,
+	DigestSHA256: sha256.Size,
+	DigestSHA384: sha512.Size384,
+}
+
+// KeyTag computes the key tag of the DNSKEY, RFC 4034 appendix B
+func KeyTag(key types.KeyData) (int, error) {
+	rdata, err := keyRdata(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if key.Algorithm == algorithmRSAMD5 {
+		// the key tag is the most significant 16 of the least significant 24 bits of the modulus
+		if len(rdata) < 7 {
+			return 0, fmt.Errorf("%w: public key too short", ErrInvalid)
+		}
+		return int(rdata[len(rdata)-3])<<8 | int(rdata[len(rdata)-2]), nil
+	}
+
+	ac := 0
+	for i, b := range rdata {
+		if i&1 == 1 {
+			ac += int(b)
+		} else {
+			ac += int(b) << 8
+		}
+	}
+	ac += ac >> 16 & 0xFFFF
+
+	return ac & 0xFFFF, nil
+}
+
+// ComputeDS computes the DS data of the DNSKEY of domain, RFC 4034 section 5.1.4.
+// The key is kept as child key data of the DS.
+func ComputeDS(domain string, key types.KeyData, digestType int) (types.DSData, error) {
+	var h hash.Hash
+	switch digestType {
+	case DigestSHA1:
+		h = sha1.New()
+	case DigestSHA256:
+		h = sha256.New()
+	case DigestSHA384:
+		h = sha512.New384()
+	default:
+		return types.DSData{}, fmt.Errorf("%w: unsupported digest type %d", ErrInvalid, digestType)
+	}
+
+	owner, err := wireName(domain)
+	if err != nil {
+		return types.DSData{}, err
+	}
+
+	rdata, err := keyRdata(key)
+	if err != nil {
+		return types.DSData{}, err
+	}
+
+	keyTag, err := KeyTag(key)
+	if err != nil {
+		return types.DSData{}, err
+	}
+
+	h.Write(owner)
+	h.Write(rdata)
+
+	return types.DSData{
+		KeyTag:     keyTag,
+		Algorithm:  key.Algorithm,
+		DigestType: digestType,
+		Digest:     strings.ToUpper(hex.EncodeToString(h.Sum(nil))),
+		KeyData:    &key,
+	}, nil
+}
+
+// keyRdata returns the DNSKEY RDATA wire format used for key tags and digests
+func keyRdata(key types.KeyData) ([]byte, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(key.PublicKey), ""))
+	if err != nil || len(publicKey) == 0 {
+		return nil, fmt.Errorf("%w: public key is not valid base64", ErrInvalid)
+	}
+
+	rdata := make([]byte, 0, 4+len(publicKey))
+	rdata = append(rdata, byte(key.Flags>>8), byte(key.Flags), byte(key.Protocol), byte(key.Algorithm))
+
+	return append(rdata, publicKey...), nil
+}
+
+// wireName returns the canonical wire format of the domain name, RFC 4034 section 6.2
+func wireName(domain string) ([]byte, error) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if domain == "" {
+		return nil, fmt.Errorf("%w: empty domain name", ErrInvalid)
+	}
+
+	name := make([]byte, 0, len(domain)+2)
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("%w: invalid domain name %q", ErrInvalid, domain)
+		}
+		name = append(name, byte(len(label)))
+		name = append(name, label...)
+	}
+
+	return append(name, 0), nil
+}
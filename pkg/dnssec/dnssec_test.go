@@ -0,0 +1,192 @@
+package dnssec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// DNSKEY and DS records of RFC 4034 section 5.4 and RFC 4509 section 2.3
+var (
+	testDomain = "dskey.example.com"
+	testKey    = types.KeyData{
+		Flags:     256,
+		Protocol:  3,
+		Algorithm: 5,
+		PublicKey: "AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw==",
+	}
+	testPolicy = Policy{Algorithms: []int{5, 8, 13}, DigestTypes: []int{DigestSHA256, DigestSHA1}}
+)
+
+func TestKeyTag(t *testing.T) {
+	keyTag, err := KeyTag(testKey)
+	require.NoError(t, err)
+	require.Equal(t, 60485, keyTag)
+
+	_, err = KeyTag(types.KeyData{Flags: 256, Protocol: 3, Algorithm: 13, PublicKey: "not base64!"})
+	require.ErrorIs(t, err, ErrInvalid)
+	require.False(t, joberrors.IsTransient(err))
+}
+
+func TestComputeDS(t *testing.T) {
+	ds, err := ComputeDS(testDomain+".", testKey, DigestSHA1)
+	require.NoError(t, err)
+	require.Equal(t, 60485, ds.KeyTag)
+	require.Equal(t, 5, ds.Algorithm)
+	require.Equal(t, "2BB183AF5F22588179A53B0A98631FAD1A292118", ds.Digest)
+
+	ds, err = ComputeDS("DSKEY.Example.COM", testKey, DigestSHA256)
+	require.NoError(t, err)
+	require.Equal(t, "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A", ds.Digest)
+	require.Equal(t, &testKey, ds.KeyData)
+
+	_, err = ComputeDS(testDomain, testKey, 3)
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestValidateKeyData(t *testing.T) {
+	require.NoError(t, testPolicy.ValidateKeyData(testKey))
+
+	sep := testKey
+	sep.Flags = 257
+	require.NoError(t, testPolicy.ValidateKeyData(sep))
+
+	tests := []struct {
+		name   string
+		modify func(*types.KeyData)
+	}{
+		{"protocol", func(k *types.KeyData) { k.Protocol = 4 }},
+		{"flags", func(k *types.KeyData) { k.Flags = 0 }},
+		{"algorithm", func(k *types.KeyData) { k.Algorithm = 7 }},
+		{"public key", func(k *types.KeyData) { k.PublicKey = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := testKey
+			tt.modify(&key)
+			require.ErrorIs(t, testPolicy.ValidateKeyData(key), ErrInvalid)
+		})
+	}
+}
+
+func TestValidateDSData(t *testing.T) {
+	ds, err := ComputeDS(testDomain, testKey, DigestSHA256)
+	require.NoError(t, err)
+	require.NoError(t, testPolicy.ValidateDSData(testDomain, ds))
+
+	tests := []struct {
+		name   string
+		modify func(*types.DSData)
+	}{
+		{"algorithm", func(ds *types.DSData) { ds.Algorithm = 10 }},
+		{"digest type", func(ds *types.DSData) { ds.DigestType = DigestSHA384 }},
+		{"digest length", func(ds *types.DSData) { ds.Digest = ds.Digest[:40] }},
+		{"digest hex", func(ds *types.DSData) { ds.Digest = "XYZ" }},
+		{"key tag", func(ds *types.DSData) { ds.KeyTag++ }},
+		{"key digest", func(ds *types.DSData) { ds.Digest = "00" + ds.Digest[2:] }},
+		{"key algorithm", func(ds *types.DSData) {
+			ds.KeyData = &types.KeyData{Flags: 256, Protocol: 3, Algorithm: 8, PublicKey: testKey.PublicKey}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, err := ComputeDS(testDomain, testKey, DigestSHA256)
+			require.NoError(t, err)
+			tt.modify(&ds)
+			require.ErrorIs(t, testPolicy.ValidateDSData(testDomain, ds), ErrInvalid)
+		})
+	}
+
+	// without key data only the digest is checked
+	ds.KeyData = nil
+	ds.KeyTag++
+	require.NoError(t, testPolicy.ValidateDSData(testDomain, ds))
+}
+
+func TestPrepare(t *testing.T) {
+	keys := []types.KeyData{testKey}
+
+	// key data is converted when the registry supports ds data only
+	p := testPolicy
+	p.Interfaces = []string{InterfaceDSData}
+	data, err := p.Prepare(testDomain, &types.SecDNSData{KeyData: &keys, MaxSigLife: types.ToPointer(3600)})
+	require.NoError(t, err)
+	require.Nil(t, data.KeyData)
+	require.Len(t, *data.DsData, 1)
+	require.Equal(t, DigestSHA256, (*data.DsData)[0].DigestType)
+	require.Equal(t, 3600, *data.MaxSigLife)
+
+	// ds data is converted back to its key data when the registry supports key data only
+	p.Interfaces = []string{InterfaceKeyData}
+	data, err = p.Prepare(testDomain, data)
+	require.NoError(t, err)
+	require.Nil(t, data.DsData)
+	require.Equal(t, keys, *data.KeyData)
+
+	// ds data without key data can not be converted
+	ds := []types.DSData{{KeyTag: 60485, Algorithm: 5, DigestType: DigestSHA1, Digest: "2BB183AF5F22588179A53B0A98631FAD1A292118"}}
+	_, err = p.Prepare(testDomain, &types.SecDNSData{DsData: &ds})
+	require.ErrorIs(t, err, ErrKeyDataRequired)
+
+	// data is sent as provided when the registry supports both
+	data, err = testPolicy.Prepare(testDomain, &types.SecDNSData{DsData: &ds})
+	require.NoError(t, err)
+	require.Equal(t, ds, *data.DsData)
+
+	data, err = testPolicy.Prepare(testDomain, nil)
+	require.NoError(t, err)
+	require.Nil(t, data)
+}
+
+func TestPrepareUpdate(t *testing.T) {
+	keys := []types.KeyData{testKey}
+	invalid := []types.KeyData{{Flags: 256, Protocol: 3, Algorithm: 7, PublicKey: testKey.PublicKey}}
+
+	p := testPolicy
+	p.Interfaces = []string{InterfaceDSData}
+	data, err := p.PrepareUpdate(testDomain, &types.SecDNSUpdateData{
+		AddData: &types.SecDNSUpdateAddData{KeyData: &keys},
+		RemData: &types.SecDNSUpdateRemData{KeyData: &keys},
+	})
+	require.NoError(t, err)
+	require.Len(t, *data.AddData.DSData, 1)
+	require.Len(t, *data.RemData.DSData, 1)
+	require.Nil(t, data.AddData.KeyData)
+
+	_, err = p.PrepareUpdate(testDomain, &types.SecDNSUpdateData{
+		AddData: &types.SecDNSUpdateAddData{KeyData: &invalid},
+	})
+	require.ErrorIs(t, err, ErrInvalid)
+
+	// records no longer allowed can still be removed
+	data, err = p.PrepareUpdate(testDomain, &types.SecDNSUpdateData{
+		RemData: &types.SecDNSUpdateRemData{KeyData: &invalid},
+	})
+	require.NoError(t, err)
+	require.Len(t, *data.RemData.DSData, 1)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{
+		AlgorithmsSetting:  "{13,8}",
+		DigestTypesSetting: "{}",
+		SupportedSetting:   "{dsData}",
+	})
+
+	p, err := LoadPolicy(ctx, db, "acc-tld")
+	require.NoError(t, err)
+	require.Equal(t, Policy{
+		Algorithms:  []int{13, 8},
+		DigestTypes: DefaultPolicy.DigestTypes,
+		Interfaces:  []string{InterfaceDSData},
+	}, p)
+}
@@ -0,0 +1,261 @@
+package dnssec
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tldsetting"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// TLD settings of the dnssec policy
+const (
+	AlgorithmsSetting  = "tld.dns.secdns_algorithms"
+	DigestTypesSetting = "tld.dns.secdns_digest_types"
+	SupportedSetting   = "tld.order.secdns_supported"
+)
+
+// Values of the secdns_supported TLD setting
+const (
+	InterfaceDSData  = "dsData"
+	InterfaceKeyData = "keyData"
+)
+
+// Policy holds the DNSSEC data a registry accepts
+type Policy struct {
+	// Algorithms are the allowed DNSKEY algorithms
+	Algorithms []int
+	// DigestTypes are the allowed DS digest types, the first one is used to compute DS data
+	DigestTypes []int
+	// Interfaces are the secDNS interfaces the registry supports, DNSSEC data is sent as provided when empty
+	Interfaces []string
+}
+
+// DefaultPolicy allows the algorithms and digest types recommended for signing by RFC 8624
+var DefaultPolicy = Policy{
+	Algorithms:  []int{8, 10, 13, 14, 15, 16},
+	DigestTypes: []int{DigestSHA256, DigestSHA384},
+}
+
+// LoadPolicy reads the dnssec policy of the accreditation tld, settings which are not set keep the default
+func LoadPolicy(ctx context.Context, db database.Database, accreditationTldId string) (p Policy, err error) {
+	p = DefaultPolicy
+
+	algorithms, err := tldsetting.IntList(ctx, db, accreditationTldId, AlgorithmsSetting)
+	if err != nil {
+		return
+	}
+	if len(algorithms) > 0 {
+		p.Algorithms = algorithms
+	}
+
+	digestTypes, err := tldsetting.IntList(ctx, db, accreditationTldId, DigestTypesSetting)
+	if err != nil {
+		return
+	}
+	if len(digestTypes) > 0 {
+		p.DigestTypes = digestTypes
+	}
+
+	p.Interfaces, err = tldsetting.StringList(ctx, db, accreditationTldId, SupportedSetting)
+
+	return
+}
+
+// ValidateKeyData checks the DNSKEY is a zone key of an allowed algorithm
+func (p Policy) ValidateKeyData(key types.KeyData) error {
+	if key.Protocol != Protocol {
+		return fmt.Errorf("%w: key protocol %d must be %d", ErrInvalid, key.Protocol, Protocol)
+	}
+
+	if key.Flags&^FlagSEP != FlagZone {
+		return fmt.Errorf("%w: key flags %d must be %d or %d", ErrInvalid, key.Flags, FlagZone, FlagZone|FlagSEP)
+	}
+
+	if !slices.Contains(p.Algorithms, key.Algorithm) {
+		return fmt.Errorf("%w: key algorithm %d is not allowed, allowed algorithms are %v", ErrInvalid, key.Algorithm, p.Algorithms)
+	}
+
+	_, err := keyRdata(key)
+
+	return err
+}
+
+// ValidateDSData checks the DS uses an allowed algorithm and digest type and its digest length matches the
+// digest type. When the DS carries its key data, the key tag and digest must be the ones of the key.
+func (p Policy) ValidateDSData(domain string, ds types.DSData) error {
+	if !slices.Contains(p.Algorithms, ds.Algorithm) {
+		return fmt.Errorf("%w: ds algorithm %d is not allowed, allowed algorithms are %v", ErrInvalid, ds.Algorithm, p.Algorithms)
+	}
+
+	if !slices.Contains(p.DigestTypes, ds.DigestType) {
+		return fmt.Errorf("%w: ds digest type %d is not allowed, allowed digest types are %v", ErrInvalid, ds.DigestType, p.DigestTypes)
+	}
+
+	if ds.KeyTag < 0 || ds.KeyTag > 0xFFFF {
+		return fmt.Errorf("%w: ds key tag %d out of range", ErrInvalid, ds.KeyTag)
+	}
+
+	digest, err := hex.DecodeString(ds.Digest)
+	if err != nil {
+		return fmt.Errorf("%w: ds digest is not valid hex", ErrInvalid)
+	}
+
+	if size, ok := digestSizes[ds.DigestType]; ok && len(digest) != size {
+		return fmt.Errorf("%w: ds digest length %d does not match digest type %d, expected %d", ErrInvalid, len(digest), ds.DigestType, size)
+	}
+
+	if ds.KeyData == nil {
+		return nil
+	}
+
+	if err = p.ValidateKeyData(*ds.KeyData); err != nil {
+		return err
+	}
+
+	if ds.KeyData.Algorithm != ds.Algorithm {
+		return fmt.Errorf("%w: ds algorithm %d does not match key algorithm %d", ErrInvalid, ds.Algorithm, ds.KeyData.Algorithm)
+	}
+
+	keyTag, err := KeyTag(*ds.KeyData)
+	if err != nil {
+		return err
+	}
+
+	if keyTag != ds.KeyTag {
+		return fmt.Errorf("%w: ds key tag %d does not match key tag %d of its key", ErrInvalid, ds.KeyTag, keyTag)
+	}
+
+	if _, ok := digestSizes[ds.DigestType]; !ok {
+		return nil
+	}
+
+	computed, err := ComputeDS(domain, *ds.KeyData, ds.DigestType)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(computed.Digest, ds.Digest) {
+		return fmt.Errorf("%w: ds digest does not match the digest of its key", ErrInvalid)
+	}
+
+	return nil
+}
+
+// Prepare validates the DNSSEC data of domain and converts it to the interface the registry supports
+func (p Policy) Prepare(domain string, data *types.SecDNSData) (*types.SecDNSData, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	dsData, keyData, err := p.prepare(domain, data.DsData, data.KeyData, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SecDNSData{MaxSigLife: data.MaxSigLife, DsData: dsData, KeyData: keyData}, nil
+}
+
+// PrepareUpdate validates the DNSSEC data added by a domain update and converts the added and removed data
+// to the interface the registry supports. Removed data is not validated so records no longer allowed can
+// still be removed.
+func (p Policy) PrepareUpdate(domain string, data *types.SecDNSUpdateData) (*types.SecDNSUpdateData, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	prepared := &types.SecDNSUpdateData{MaxSigLife: data.MaxSigLife}
+
+	if data.AddData != nil {
+		dsData, keyData, err := p.prepare(domain, data.AddData.DSData, data.AddData.KeyData, true)
+		if err != nil {
+			return nil, err
+		}
+		prepared.AddData = &types.SecDNSUpdateAddData{DSData: dsData, KeyData: keyData}
+	}
+
+	if data.RemData != nil {
+		dsData, keyData, err := p.prepare(domain, data.RemData.DSData, data.RemData.KeyData, false)
+		if err != nil {
+			return nil, err
+		}
+		prepared.RemData = &types.SecDNSUpdateRemData{DSData: dsData, KeyData: keyData}
+	}
+
+	return prepared, nil
+}
+
+func (p Policy) prepare(domain string, dsData *[]types.DSData, keyData *[]types.KeyData, validate bool) (*[]types.DSData, *[]types.KeyData, error) {
+	if validate && dsData != nil {
+		for _, ds := range *dsData {
+			if err := p.ValidateDSData(domain, ds); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if validate && keyData != nil {
+		for _, key := range *keyData {
+			if err := p.ValidateKeyData(key); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	switch {
+	case dsData != nil && !p.supports(InterfaceDSData):
+		keys, err := toKeyData(*dsData)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, &keys, nil
+	case keyData != nil && !p.supports(InterfaceKeyData):
+		if len(p.DigestTypes) == 0 {
+			return nil, nil, fmt.Errorf("%w: no digest type allowed to compute ds data", ErrInvalid)
+		}
+		ds, err := toDSData(domain, *keyData, p.DigestTypes[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ds, nil, nil
+	}
+
+	return dsData, keyData, nil
+}
+
+// supports tells if the registry supports the secDNS interface, any interface is supported when none is set
+func (p Policy) supports(iface string) bool {
+	return len(p.Interfaces) == 0 || slices.Contains(p.Interfaces, iface)
+}
+
+// toDSData computes the DS data of every key
+func toDSData(domain string, keyData []types.KeyData, digestType int) ([]types.DSData, error) {
+	dsData := make([]types.DSData, 0, len(keyData))
+	for _, key := range keyData {
+		ds, err := ComputeDS(domain, key, digestType)
+		if err != nil {
+			return nil, err
+		}
+		dsData = append(dsData, ds)
+	}
+
+	return dsData, nil
+}
+
+// toKeyData returns the key data carried by every DS
+func toKeyData(dsData []types.DSData) ([]types.KeyData, error) {
+	keyData := make([]types.KeyData, 0, len(dsData))
+	for _, ds := range dsData {
+		if ds.KeyData == nil {
+			return nil, ErrKeyDataRequired
+		}
+		keyData = append(keyData, *ds.KeyData)
+	}
+
+	return keyData, nil
+}
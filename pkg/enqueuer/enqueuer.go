@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 	"google.golang.org/protobuf/proto"
 	"gorm.io/gorm"
@@ -48,6 +49,8 @@ func (e DbMessageEnqueuer[T]) updateRows(ctx context.Context, Ids []string) (err
 func (e DbMessageEnqueuer[T]) processRows(ctx context.Context, rows []T, handler func(T) (proto.Message, error)) (err error) {
 	var ids []string
 
+	metrics.EnqueuerBatchSize.WithLabelValues(e.Config.Queue).Observe(float64(len(rows)))
+
 	if e.Config.UpdateFieldValueMap != nil {
 		defer func(ctx context.Context, Ids *[]string) {
 			err = e.updateRows(ctx, *Ids)
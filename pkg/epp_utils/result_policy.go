@@ -0,0 +1,135 @@
+package epp_utils
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/tucowsinc/tdp-messages-go/message/common"
+	"github.com/tucowsinc/tdp-shared-go/logger"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// ResultPolicyInput identifies the job a registry response belongs to
+type ResultPolicyInput struct {
+	JobType           string
+	AccreditationName string
+	// ObjectName is the domain or host name, it is matched against policy tlds
+	ObjectName string
+}
+
+// JobOutcome is the job status resolved for a registry response
+type JobOutcome struct {
+	Status string
+	// Message is the customer facing message of the matched policy
+	Message *string
+	// Policy is the matched policy, nil when the default outcome applies
+	Policy *model.VEppResultPolicy
+}
+
+// IsSuccess tells if the job outcome is a success
+func (o JobOutcome) IsSuccess() bool {
+	return o.Status != types.JobStatus.Failed
+}
+
+// SetJobResult sets the job result message and error details of a failed outcome
+func (o JobOutcome) SetJobResult(registryResponse *common.RegistryResponse, job *model.Job, jrd *types.JobResultData) {
+	if o.Message != nil {
+		job.ResultMessage = o.Message
+	}
+
+	if o.IsSuccess() {
+		return
+	}
+
+	if o.Message == nil {
+		SetJobErrorFromRegistryResponse(registryResponse, job, jrd)
+		return
+	}
+
+	jrd.SetErrorDetails(&registryResponse.EppCode, o.Message)
+}
+
+// DefaultJobOutcome completes the job on a successful registry response and fails it otherwise
+func DefaultJobOutcome(registryResponse *common.RegistryResponse) JobOutcome {
+	if registryResponse.GetIsSuccess() {
+		return JobOutcome{Status: types.JobStatus.Completed}
+	}
+
+	return JobOutcome{Status: types.JobStatus.Failed}
+}
+
+// ResolveJobOutcome looks up the EPP result policy of the registry response. The most specific
+// matching policy wins: a tld match first, then an accreditation match, then a message pattern match.
+// Without a matching policy the default outcome applies.
+func ResolveJobOutcome(ctx context.Context, db database.Database, input ResultPolicyInput, registryResponse *common.RegistryResponse, logger logger.ILogger) JobOutcome {
+	policies, err := db.GetEppResultPolicies(ctx, input.JobType, registryResponse.GetEppCode())
+	if err != nil {
+		logger.Warn("Failed to get EPP result policies, using default outcome", log.Fields{
+			types.LogFieldKeys.EppCode: registryResponse.GetEppCode(),
+			types.LogFieldKeys.Error:   err,
+		})
+		return DefaultJobOutcome(registryResponse)
+	}
+
+	message := types.SafeDeref(GetMessageFromRegistryResponse(registryResponse))
+
+	var best *model.VEppResultPolicy
+	bestScore := -1
+
+	for i := range policies {
+		score, ok := matchPolicy(&policies[i], input, message, logger)
+		if ok && score > bestScore {
+			best, bestScore = &policies[i], score
+		}
+	}
+
+	if best == nil {
+		return DefaultJobOutcome(registryResponse)
+	}
+
+	logger.Debug("Matched EPP result policy", log.Fields{
+		types.LogFieldKeys.EppCode: registryResponse.GetEppCode(),
+		types.LogFieldKeys.Status:  best.JobStatusName,
+		"policy_id":                best.ID,
+	})
+
+	return JobOutcome{Status: best.JobStatusName, Message: best.ResultMessage, Policy: best}
+}
+
+// matchPolicy tells if the policy applies and scores how specific it is
+func matchPolicy(p *model.VEppResultPolicy, input ResultPolicyInput, message string, logger logger.ILogger) (score int, ok bool) {
+	if p.TldName != nil {
+		if !strings.HasSuffix(strings.ToLower(input.ObjectName), "."+strings.ToLower(*p.TldName)) {
+			return
+		}
+		score += 4
+	}
+
+	if p.AccreditationName != nil {
+		if *p.AccreditationName != input.AccreditationName {
+			return
+		}
+		score += 2
+	}
+
+	if p.MessagePattern != nil {
+		matched, err := regexp.MatchString(*p.MessagePattern, message)
+		if err != nil {
+			logger.Warn("Invalid EPP result policy message pattern", log.Fields{
+				"policy_id":              p.ID,
+				types.LogFieldKeys.Error: err,
+			})
+			return
+		}
+		if !matched {
+			return
+		}
+		score++
+	}
+
+	return score, true
+}
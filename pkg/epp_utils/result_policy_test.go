@@ -0,0 +1,131 @@
+package epp_utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tucowsinc/tdp-messages-go/message/common"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+func TestResolveJobOutcome(t *testing.T) {
+	log.Setup(config.Config{LogLevel: "mute"})
+
+	ctx := context.Background()
+	input := ResultPolicyInput{
+		JobType:           "provision_domain_update",
+		AccreditationName: "opensrs-uniregistry",
+		ObjectName:        "example.sexy",
+	}
+
+	generic := model.VEppResultPolicy{ID: "generic", JobStatusName: types.JobStatus.Failed, ResultMessage: types.ToPointer("generic")}
+	byAccreditation := model.VEppResultPolicy{ID: "accreditation", AccreditationName: types.ToPointer("opensrs-uniregistry"), JobStatusName: types.JobStatus.Completed}
+	byOtherAccreditation := model.VEppResultPolicy{ID: "other-accreditation", AccreditationName: types.ToPointer("enom"), JobStatusName: types.JobStatus.Completed}
+	byTld := model.VEppResultPolicy{ID: "tld", TldName: types.ToPointer("sexy"), JobStatusName: types.JobStatus.CompletedConditionally}
+	byPattern := model.VEppResultPolicy{ID: "pattern", MessagePattern: types.ToPointer("(?i)association"), JobStatusName: types.JobStatus.Completed}
+	byOtherPattern := model.VEppResultPolicy{ID: "other-pattern", MessagePattern: types.ToPointer("^status"), JobStatusName: types.JobStatus.Completed}
+	invalidPattern := model.VEppResultPolicy{ID: "invalid-pattern", MessagePattern: types.ToPointer("("), JobStatusName: types.JobStatus.Completed}
+
+	failed := &common.RegistryResponse{IsSuccess: false, EppCode: types.EppCode.Exists, EppMessage: "Host association exists"}
+
+	tests := []struct {
+		name     string
+		policies []model.VEppResultPolicy
+		dbErr    error
+		response *common.RegistryResponse
+		policyID string
+		status   string
+	}{
+		{
+			name:     "no policy, success",
+			response: &common.RegistryResponse{IsSuccess: true, EppCode: types.EppCode.Success},
+			status:   types.JobStatus.Completed,
+		},
+		{
+			name:     "no policy, failure",
+			response: failed,
+			status:   types.JobStatus.Failed,
+		},
+		{
+			name:     "database error falls back to default",
+			dbErr:    errors.New("db error"),
+			response: failed,
+			status:   types.JobStatus.Failed,
+		},
+		{
+			name:     "generic policy",
+			policies: []model.VEppResultPolicy{generic, byOtherAccreditation, byOtherPattern, invalidPattern},
+			response: failed,
+			policyID: "generic",
+			status:   types.JobStatus.Failed,
+		},
+		{
+			name:     "message pattern overrides generic",
+			policies: []model.VEppResultPolicy{generic, byPattern},
+			response: failed,
+			policyID: "pattern",
+			status:   types.JobStatus.Completed,
+		},
+		{
+			name:     "accreditation overrides message pattern",
+			policies: []model.VEppResultPolicy{byPattern, byAccreditation, generic},
+			response: failed,
+			policyID: "accreditation",
+			status:   types.JobStatus.Completed,
+		},
+		{
+			name:     "tld overrides accreditation",
+			policies: []model.VEppResultPolicy{byAccreditation, byTld, generic},
+			response: failed,
+			policyID: "tld",
+			status:   types.JobStatus.CompletedConditionally,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &database.MockDatabase{}
+			db.On("GetEppResultPolicies", ctx, input.JobType, tt.response.EppCode).Return(tt.policies, tt.dbErr)
+
+			outcome := ResolveJobOutcome(ctx, db, input, tt.response, log.GetLogger())
+
+			assert.Equal(t, tt.status, outcome.Status)
+			if tt.policyID == "" {
+				assert.Nil(t, outcome.Policy)
+			} else if assert.NotNil(t, outcome.Policy) {
+				assert.Equal(t, tt.policyID, outcome.Policy.ID)
+			}
+		})
+	}
+}
+
+func TestJobOutcomeSetJobResult(t *testing.T) {
+	response := &common.RegistryResponse{IsSuccess: false, EppCode: types.EppCode.Exists, EppMessage: "Object exists"}
+
+	job := &model.Job{}
+	jrd := &types.JobResultData{}
+	JobOutcome{Status: types.JobStatus.Failed, Message: types.ToPointer("Host association already exists")}.SetJobResult(response, job, jrd)
+
+	assert.Equal(t, "Host association already exists", *job.ResultMessage)
+	assert.Equal(t, types.EppCode.Exists, *jrd.Error.EppCode)
+
+	job = &model.Job{}
+	jrd = &types.JobResultData{}
+	JobOutcome{Status: types.JobStatus.Failed}.SetJobResult(response, job, jrd)
+
+	assert.Equal(t, "Object exists", *job.ResultMessage)
+
+	job = &model.Job{}
+	jrd = &types.JobResultData{}
+	JobOutcome{Status: types.JobStatus.Completed, Message: types.ToPointer("Host already exists")}.SetJobResult(response, job, jrd)
+
+	assert.Equal(t, "Host already exists", *job.ResultMessage)
+	assert.Nil(t, jrd.Error)
+}
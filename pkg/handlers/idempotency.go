@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	"github.com/tucowsinc/tdp-shared-go/logger"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// DefaultDedupTTL is how long a handled message is remembered
+const DefaultDedupTTL = 24 * time.Hour
+
+// Deduplicator detects redelivered message bus messages. Messages are claimed within the
+// transaction of the handler, so a message is only recorded once its changes are committed.
+type Deduplicator struct {
+	db  database.Database
+	ttl time.Duration
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+// NewDeduplicator creates a deduplicator remembering messages for the ttl
+func NewDeduplicator(db database.Database, ttl time.Duration) *Deduplicator {
+	return &Deduplicator{db: db, ttl: ttl}
+}
+
+// MessageKey identifies a message by its envelope id, or its correlation id when it has none, and its type
+func MessageKey(server messagebus.Server, message proto.Message) string {
+	envelope := server.Envelope()
+
+	id := fmt.Sprint(envelope.Id)
+	if id == "" || id == "0" {
+		id = envelope.CorrelationId
+	}
+
+	return fmt.Sprintf("%s:%s", proto.MessageName(message), id)
+}
+
+// Claim records the message as handled within tx, it returns false and logs when the message is a duplicate
+func (d *Deduplicator) Claim(ctx context.Context, tx database.Database, server messagebus.Server, message proto.Message, logger logger.ILogger) (claimed bool, err error) {
+	d.purge(ctx)
+
+	key := MessageKey(server, message)
+
+	claimed, err = tx.ClaimMessage(ctx, key, string(proto.MessageName(message)), d.ttl)
+	if err != nil {
+		logger.Error("Failed to check message for duplicate delivery", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	if !claimed {
+		logger.Warn("Skipping duplicate message delivery", log.Fields{
+			"message_key": key,
+		})
+	}
+
+	return
+}
+
+// purge deletes expired records at most once per ttl
+func (d *Deduplicator) purge(ctx context.Context) {
+	d.mu.Lock()
+	if time.Since(d.lastPurge) < d.ttl {
+		d.mu.Unlock()
+		return
+	}
+	d.lastPurge = time.Now()
+	d.mu.Unlock()
+
+	count, err := d.db.PurgeProcessedMessages(ctx)
+	if err != nil {
+		log.Warn("Failed to purge processed messages", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	log.Debug("Purged processed messages", log.Fields{"count": count})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
+	"github.com/tucowsinc/tdp-messages-go/message"
+	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+)
+
+type DeduplicatorTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	db  *database.MockDatabase
+	s   *mocks.MockMessageBusServer
+}
+
+func TestDeduplicatorTestSuite(t *testing.T) {
+	suite.Run(t, new(DeduplicatorTestSuite))
+}
+
+func (suite *DeduplicatorTestSuite) SetupSuite() {
+	log.Setup(config.Config{LogLevel: "mute"})
+	suite.ctx = context.Background()
+}
+
+func (suite *DeduplicatorTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.s = &mocks.MockMessageBusServer{}
+
+	suite.s.On("Envelope").Return(&message.TcWire{CorrelationId: "job-id"})
+	suite.db.On("PurgeProcessedMessages", suite.ctx).Return(int64(0), nil)
+}
+
+func (suite *DeduplicatorTestSuite) TestClaim() {
+	msg := &ryinterface.DomainUpdateResponse{}
+	key := MessageKey(suite.s, msg)
+	suite.Contains(key, "job-id")
+
+	suite.db.On("ClaimMessage", suite.ctx, key, mock.Anything, DefaultDedupTTL).Return(true, nil).Once()
+	suite.db.On("ClaimMessage", suite.ctx, key, mock.Anything, DefaultDedupTTL).Return(false, nil).Once()
+
+	dedup := NewDeduplicator(suite.db, DefaultDedupTTL)
+
+	claimed, err := dedup.Claim(suite.ctx, suite.db, suite.s, msg, log.GetLogger())
+	suite.NoError(err)
+	suite.True(claimed)
+
+	claimed, err = dedup.Claim(suite.ctx, suite.db, suite.s, msg, log.GetLogger())
+	suite.NoError(err)
+	suite.False(claimed)
+
+	// expired records are purged at most once per ttl
+	suite.db.AssertNumberOfCalls(suite.T(), "PurgeProcessedMessages", 1)
+}
+
+func (suite *DeduplicatorTestSuite) TestMessageKeyByType() {
+	suite.NotEqual(
+		MessageKey(suite.s, &ryinterface.DomainUpdateResponse{}),
+		MessageKey(suite.s, &ryinterface.DomainInfoResponse{}),
+	)
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	"github.com/tucowsinc/tdp-shared-go/logger"
+	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// JobRequest is passed to a typed job handler once its job is in the expected status and its data decoded
+type JobRequest[T any] struct {
+	Ctx     context.Context
+	Server  messagebus.Server
+	Message proto.Message
+
+	// DB is the database outside of the job transaction
+	DB database.Database
+	// Tx is the transaction holding the job lock, it is DB once the job is claimed
+	Tx database.Database
+
+	Job    *model.Job
+	Data   *T
+	Logger logger.ILogger
+
+	claimed bool
+}
+
+// SetStatus updates the job status and logs the outcome
+func (r *JobRequest[T]) SetStatus(status string, jrd *types.JobResultData) (err error) {
+	recorded, err := r.record(func(tx database.Database) error {
+		return tx.SetJobStatus(r.Ctx, r.Job, status, jrd)
+	})
+	if err != nil {
+		r.Logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	if recorded {
+		r.Logger.Info(types.LogMessages.UpdateStatusInDBSuccess, log.Fields{
+			types.LogFieldKeys.Status: status,
+		})
+	}
+
+	return
+}
+
+// Fail sets the job to failed with the given result message
+func (r *JobRequest[T]) Fail(message string) error {
+	r.Job.ResultMessage = &message
+
+	return r.SetStatus(types.JobStatus.Failed, nil)
+}
+
+// FailOrReschedule reschedules the job when err is transient and it has retries left, otherwise it fails the job
+func (r *JobRequest[T]) FailOrReschedule(err error, jrd *types.JobResultData) error {
+	_, err = r.record(func(tx database.Database) error {
+		return joberrors.FailOrReschedule(r.Ctx, tx, r.Job, err, jrd, r.Logger)
+	})
+
+	return err
+}
+
+// Release puts a claimed job back to submitted so it is dispatched again at its start date,
+// middleware holding or deferring the job calls it once the start date is moved
+func (r *JobRequest[T]) Release() error {
+	if !r.claimed {
+		return nil
+	}
+
+	return r.SetStatus(types.JobStatus.Submitted, nil)
+}
+
+// record runs fn within the job transaction, or once the job is claimed within a new short transaction
+// locking the job. fn is skipped when the claimed job is no longer dispatching, as when the registry
+// response was handled before the outcome of the send is recorded.
+func (r *JobRequest[T]) record(fn func(tx database.Database) error) (recorded bool, err error) {
+	if !r.claimed {
+		return true, fn(r.Tx)
+	}
+
+	err = r.DB.WithTransaction(func(tx database.Database) error {
+		job, err := tx.GetJobById(r.Ctx, r.Job.ID, true)
+		if err != nil {
+			return err
+		}
+
+		if job.StatusID != tx.GetJobStatusId(types.JobStatus.Dispatching) {
+			r.Logger.Warn("Job is no longer dispatching, skipping update", log.Fields{
+				types.LogFieldKeys.Status: job.Info.JobStatusName,
+			})
+			return nil
+		}
+
+		recorded = true
+
+		return fn(tx)
+	})
+
+	return
+}
+
+// JobHandlerFunc handles the registry specific part of a job
+type JobHandlerFunc[T any] func(r *JobRequest[T]) error
+
+// Middleware wraps a JobHandlerFunc, it can stop the chain by not calling next
+type Middleware[T any] func(next JobHandlerFunc[T]) JobHandlerFunc[T]
+
+// JobHandler is a message bus handler for jobs which data decodes to T. It checks the job status and
+// decodes the job data within a transaction holding the job lock.
+//
+// Job notifications claim the job by moving it to dispatching before the transaction commits, the handler
+// then runs without holding the lock so no transaction is kept open across message bus sends. Its status
+// updates are recorded in their own short transaction. Registry responses run the handler within the
+// transaction holding the job lock.
+type JobHandler[T any] struct {
+	name       string
+	db         database.Database
+	tracer     *oteltrace.Tracer
+	statuses   []string
+	jobId      func(server messagebus.Server, message proto.Message) string
+	claim      bool
+	dedup      *Deduplicator
+	middleware []Middleware[T]
+	handle     JobHandlerFunc[T]
+}
+
+// NewJobHandler creates a handler for job notifications expecting the job to be submitted
+func NewJobHandler[T any](name string, db database.Database, tracer *oteltrace.Tracer, handle JobHandlerFunc[T]) *JobHandler[T] {
+	return &JobHandler[T]{
+		name:     name,
+		db:       db,
+		tracer:   tracer,
+		statuses: []string{types.JobStatus.Submitted},
+		jobId:    notificationJobId,
+		claim:    true,
+		handle:   handle,
+	}
+}
+
+// WithStatuses sets the job statuses the handler accepts, jobs in any other status are skipped
+func (h *JobHandler[T]) WithStatuses(statuses ...string) *JobHandler[T] {
+	h.statuses = statuses
+	return h
+}
+
+// ForRegistryResponse makes the handler find its job by the message correlation id and expect it to be processing,
+// as for registry responses replying to a request sent by the job. The job may still be dispatching when the
+// response arrives before the worker which sent the request recorded it.
+func (h *JobHandler[T]) ForRegistryResponse() *JobHandler[T] {
+	h.statuses = []string{types.JobStatus.Processing, types.JobStatus.Dispatching}
+	h.jobId = correlationJobId
+	h.claim = false
+	return h
+}
+
+// Deduplicate makes the handler skip messages which were already handled
+func (h *JobHandler[T]) Deduplicate(dedup *Deduplicator) *JobHandler[T] {
+	h.dedup = dedup
+	return h
+}
+
+// Use appends middleware, the first one added runs first
+func (h *JobHandler[T]) Use(middleware ...Middleware[T]) *JobHandler[T] {
+	h.middleware = append(h.middleware, middleware...)
+	return h
+}
+
+// Handle is the message bus handler function
+func (h *JobHandler[T]) Handle(server messagebus.Server, message proto.Message) error {
+	ctx := server.Context()
+	span, ctx := h.tracer.CreateSpanFromHeaders(ctx, server.Headers(), h.name)
+	defer h.tracer.FinishSpan(span)
+
+	jobId := h.jobId(server, message)
+
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.CorrelationID: jobId,
+	})
+
+	logger.Info("Started " + h.name + " for the job")
+
+	handle := h.handle
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		handle = h.middleware[i](handle)
+	}
+
+	var r *JobRequest[T]
+
+	err := h.db.WithTransaction(func(tx database.Database) (err error) {
+		job, err := tx.GetJobById(ctx, jobId, true)
+		if err != nil {
+			logger.Error(types.LogMessages.FetchJobByIDFromDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return
+		}
+
+		logger = logger.CreateChildLogger(log.Fields{
+			types.LogFieldKeys.LogID:   uuid.NewString(),
+			types.LogFieldKeys.JobType: types.SafeDeref(job.Info.JobTypeName),
+		})
+
+		if !h.expectedStatus(tx, job) {
+			logger.Warn(types.LogMessages.UnexpectedJobStatus, log.Fields{
+				types.LogFieldKeys.Status: job.Info.JobStatusName,
+			})
+			return
+		}
+
+		// the message is only claimed once the job is in the expected status, within the same transaction
+		if h.dedup != nil {
+			claimed, err := h.dedup.Claim(ctx, tx, server, message, logger)
+			if err != nil || !claimed {
+				return err
+			}
+		}
+
+		req := &JobRequest[T]{
+			Ctx:     ctx,
+			Server:  server,
+			Message: message,
+			DB:      h.db,
+			Tx:      tx,
+			Job:     job,
+			Data:    new(T),
+			Logger:  logger,
+		}
+
+		err = json.Unmarshal(job.Info.Data, req.Data)
+		if err != nil {
+			logger.Error(types.LogMessages.JSONDecodeFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return req.Fail(err.Error())
+		}
+
+		if !h.claim {
+			return handle(req)
+		}
+
+		err = tx.ClaimJob(ctx, job)
+		if err != nil {
+			logger.Error(types.LogMessages.UpdateStatusInDBFailed, log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+			return
+		}
+
+		r = req
+
+		return
+	})
+	if err != nil || r == nil {
+		return err
+	}
+
+	// the claim is committed, the handler runs without holding the job lock
+	r.Tx = h.db
+	r.claimed = true
+
+	// an error returned once the job is claimed would leave it dispatching until the claim timeout
+	// resubmits it, it fails or reschedules the job instead
+	err = handle(r)
+	if err != nil {
+		r.Logger.Error("Job handler failed after the job was claimed", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+		return r.FailOrReschedule(err, nil)
+	}
+
+	return nil
+}
+
+func (h *JobHandler[T]) expectedStatus(tx database.Database, job *model.Job) bool {
+	for _, status := range h.statuses {
+		if job.StatusID == tx.GetJobStatusId(status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func notificationJobId(_ messagebus.Server, message proto.Message) string {
+	if n, ok := message.(interface{ GetJobId() string }); ok {
+		return n.GetJobId()
+	}
+
+	return ""
+}
+
+func correlationJobId(server messagebus.Server, _ proto.Message) string {
+	return server.Envelope().CorrelationId
+}
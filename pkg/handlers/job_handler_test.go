@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
+	"github.com/tucowsinc/tdp-messages-go/message"
+	jobmessage "github.com/tucowsinc/tdp-messages-go/message/job"
+	"github.com/tucowsinc/tdp-shared-go/tracing/oteltrace"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tracing"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+type testJobData struct {
+	Name string `json:"name"`
+}
+
+type JobHandlerTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	db  *database.MockDatabase
+	s   *mocks.MockMessageBusServer
+	t   *oteltrace.Tracer
+}
+
+func TestJobHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(JobHandlerTestSuite))
+}
+
+func (suite *JobHandlerTestSuite) SetupSuite() {
+	cfg := config.Config{LogLevel: "mute"}
+	log.Setup(cfg)
+
+	tracer, _, err := tracing.Setup(context.Background(), cfg)
+	suite.NoError(err)
+	suite.t = tracer
+
+	suite.ctx = context.Background()
+}
+
+func (suite *JobHandlerTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.s = &mocks.MockMessageBusServer{}
+
+	suite.s.On("Context").Return(suite.ctx)
+	suite.s.On("Headers").Return(nil)
+
+	call := suite.db.On("WithTransaction", mock.Anything)
+	call.Run(func(args mock.Arguments) {
+		f := args.Get(0).(func(database.Database) error)
+		call.ReturnArguments = mock.Arguments{f(suite.db)}
+	})
+
+	suite.db.On("GetJobStatusId", types.JobStatus.Submitted).Return("submitted-id").Maybe()
+	suite.db.On("GetJobStatusId", types.JobStatus.Dispatching).Return("dispatching-id").Maybe()
+	suite.db.On("GetJobStatusId", types.JobStatus.Processing).Return("processing-id").Maybe()
+}
+
+func (suite *JobHandlerTestSuite) mockJob(statusId string, data string) *model.Job {
+	job := &model.Job{
+		ID:       "job-id",
+		StatusID: statusId,
+		Info: &model.VJob{
+			JobTypeName: types.ToPointer("provision_test"),
+			Data:        []byte(data),
+		},
+	}
+
+	suite.db.On("GetJobById", mock.Anything, "job-id", true).Return(job, nil)
+
+	return job
+}
+
+func (suite *JobHandlerTestSuite) mockClaim() {
+	suite.db.On("ClaimJob", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Job).StatusID = "dispatching-id"
+	}).Return(nil)
+}
+
+func (suite *JobHandlerTestSuite) TestHandle() {
+	job := suite.mockJob("submitted-id", `{"name": "example.com"}`)
+	suite.mockClaim()
+
+	var handled *JobRequest[testJobData]
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		handled = r
+		return nil
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+
+	suite.Require().NotNil(handled)
+	suite.Equal(job, handled.Job)
+	suite.Equal("example.com", handled.Data.Name)
+	suite.Equal(suite.db, handled.Tx)
+	suite.True(handled.claimed)
+	suite.Equal("dispatching-id", job.StatusID)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobHandlerTestSuite) TestHandleRecordsOutcome() {
+	job := suite.mockJob("submitted-id", `{}`)
+	suite.mockClaim()
+	suite.db.On("SetJobStatus", mock.Anything, job, types.JobStatus.Processing, (*types.JobResultData)(nil)).Return(nil)
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		return r.SetStatus(types.JobStatus.Processing, nil)
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+
+	// the claim and the outcome are written in their own transaction
+	suite.db.AssertNumberOfCalls(suite.T(), "WithTransaction", 2)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobHandlerTestSuite) TestHandleOutcomeSkippedWhenNoLongerDispatching() {
+	job := &model.Job{
+		ID:       "job-id",
+		StatusID: "submitted-id",
+		Info: &model.VJob{
+			JobTypeName: types.ToPointer("provision_test"),
+			Data:        []byte(`{}`),
+		},
+	}
+	suite.db.On("GetJobById", mock.Anything, "job-id", true).Return(job, nil).Once()
+	suite.mockClaim()
+
+	// the registry response was handled before the send was recorded
+	suite.db.On("GetJobById", mock.Anything, "job-id", true).Return(&model.Job{
+		ID:       "job-id",
+		StatusID: "processing-id",
+		Info:     &model.VJob{JobStatusName: types.ToPointer(types.JobStatus.Processing)},
+	}, nil).Once()
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		return r.SetStatus(types.JobStatus.Processing, nil)
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *JobHandlerTestSuite) TestHandleErrorAfterClaim() {
+	job := suite.mockJob("submitted-id", `{}`)
+	suite.mockClaim()
+	suite.db.On("SetJobStatus", mock.Anything, job, types.JobStatus.Failed, (*types.JobResultData)(nil)).Return(nil)
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		return errors.New("invalid tld setting")
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+
+	// the claimed job is failed instead of being left dispatching
+	suite.Equal("invalid tld setting", types.SafeDeref(job.ResultMessage))
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobHandlerTestSuite) TestHandleTransientErrorAfterClaim() {
+	job := suite.mockJob("submitted-id", `{}`)
+	suite.mockClaim()
+	suite.db.On("RescheduleJob", mock.Anything, "job-id", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		return joberrors.Transient(errors.New("database unavailable"))
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+
+	suite.Equal("database unavailable", types.SafeDeref(job.ResultMessage))
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobHandlerTestSuite) TestHandleRelease() {
+	job := suite.mockJob("submitted-id", `{}`)
+	suite.mockClaim()
+	suite.db.On("SetJobStatus", mock.Anything, job, types.JobStatus.Submitted, (*types.JobResultData)(nil)).Return(nil)
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		return r.Release()
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobHandlerTestSuite) TestHandleUnexpectedStatus() {
+	suite.mockJob("processing-id", `{}`)
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		suite.Fail("handler must not be called")
+		return nil
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *JobHandlerTestSuite) TestHandleInvalidData() {
+	job := suite.mockJob("submitted-id", `{"name": 1}`)
+	suite.db.On("SetJobStatus", mock.Anything, job, types.JobStatus.Failed, (*types.JobResultData)(nil)).Return(nil)
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		suite.Fail("handler must not be called")
+		return nil
+	})
+
+	err := handler.Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+	suite.NotNil(job.ResultMessage)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobHandlerTestSuite) TestHandleMiddleware() {
+	job := suite.mockJob("submitted-id", `{}`)
+	suite.mockClaim()
+
+	var calls []string
+	middleware := func(name string, proceed bool) Middleware[testJobData] {
+		return func(next JobHandlerFunc[testJobData]) JobHandlerFunc[testJobData] {
+			return func(r *JobRequest[testJobData]) error {
+				calls = append(calls, name)
+				if !proceed {
+					return nil
+				}
+				return next(r)
+			}
+		}
+	}
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	err := handler.Use(middleware("first", true), middleware("second", true)).Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+	suite.Equal([]string{"first", "second", "handler"}, calls)
+
+	calls = nil
+	job.StatusID = "submitted-id"
+
+	err = handler.Use(middleware("third", false)).Handle(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+	suite.Equal([]string{"first", "second", "third"}, calls)
+}
+
+func (suite *JobHandlerTestSuite) TestHandleRegistryResponse() {
+	suite.mockJob("processing-id", `{}`)
+	suite.s.On("Envelope").Return(&message.TcWire{CorrelationId: "job-id"})
+
+	called := false
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		called = true
+		return nil
+	}).ForRegistryResponse()
+
+	err := handler.Handle(suite.s, &message.ErrorResponse{})
+	suite.NoError(err)
+	suite.True(called)
+	suite.db.AssertNotCalled(suite.T(), "ClaimJob", mock.Anything, mock.Anything)
+}
+
+func (suite *JobHandlerTestSuite) TestHandleRegistryResponseDuplicate() {
+	suite.mockJob("processing-id", `{}`)
+	suite.s.On("Envelope").Return(&message.TcWire{CorrelationId: "job-id"})
+	suite.db.On("PurgeProcessedMessages", suite.ctx).Return(int64(0), nil)
+	suite.db.On("ClaimMessage", suite.ctx, mock.Anything, mock.Anything, DefaultDedupTTL).Return(false, nil)
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		suite.Fail("handler must not be called")
+		return nil
+	}).ForRegistryResponse().Deduplicate(NewDeduplicator(suite.db, DefaultDedupTTL))
+
+	err := handler.Handle(suite.s, &message.ErrorResponse{})
+	suite.NoError(err)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *JobHandlerTestSuite) TestHandleRegistryResponseUnexpectedStatusNotClaimed() {
+	// the job was put back to submitted, the response to the command sent again must still be handled
+	suite.mockJob("submitted-id", `{}`)
+	suite.s.On("Envelope").Return(&message.TcWire{CorrelationId: "job-id"})
+
+	handler := NewJobHandler("TestHandler", suite.db, suite.t, func(r *JobRequest[testJobData]) error {
+		suite.Fail("handler must not be called")
+		return nil
+	}).ForRegistryResponse().Deduplicate(NewDeduplicator(suite.db, DefaultDedupTTL))
+
+	err := handler.Handle(suite.s, &message.ErrorResponse{})
+	suite.NoError(err)
+	suite.db.AssertNotCalled(suite.T(), "ClaimMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"github.com/tucowsinc/tdp-workers-go/pkg/lease"
+	"github.com/tucowsinc/tdp-workers-go/pkg/maintenance"
+	"github.com/tucowsinc/tdp-workers-go/pkg/ratelimit"
+)
+
+// RegistryGate holds the job during a maintenance window of its accreditation
+// and defers it when the accreditation rate limit is exceeded
+func RegistryGate[T any](accreditation func(data *T) string) Middleware[T] {
+	return func(next JobHandlerFunc[T]) JobHandlerFunc[T] {
+		return func(r *JobRequest[T]) error {
+			accreditationName := accreditation(r.Data)
+
+			held, err := maintenance.HoldJob(r.Ctx, r.DB, r.Tx, r.Job, accreditationName, r.Logger)
+			if err != nil {
+				return err
+			}
+			if held {
+				return r.Release()
+			}
+
+			deferred, err := ratelimit.DeferJob(r.Ctx, r.DB, r.Tx, r.Job, accreditationName, r.Logger)
+			if err != nil {
+				return err
+			}
+			if deferred {
+				return r.Release()
+			}
+
+			return next(r)
+		}
+	}
+}
+
+// ObjectLease defers the job while another job holds the lease of the registry object it sends a command for
+func ObjectLease[T any](key func(data *T) lease.Key) Middleware[T] {
+	return func(next JobHandlerFunc[T]) JobHandlerFunc[T] {
+		return func(r *JobRequest[T]) error {
+			deferred, err := lease.DeferJob(r.Ctx, r.Tx, r.Job, key(r.Data), r.Logger)
+			if err != nil {
+				return err
+			}
+			if deferred {
+				return r.Release()
+			}
+
+			return next(r)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
+	"github.com/tucowsinc/tdp-workers-go/pkg/sqs"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// PanicResultMessage is the result message of a job failed by a handler panic
+const PanicResultMessage = "Internal error while handling job"
+
+// Recoverer recovers message handlers from panics. The job correlated to the message is failed with
+// an internal error code and the message is acked or nacked according to ack.
+type Recoverer struct {
+	db  database.Database
+	ack bool
+}
+
+// NewRecoverer creates a Recoverer, ack tells if messages which handler panicked are acked.
+// db may be nil for workers without jobs.
+func NewRecoverer(db database.Database, ack bool) *Recoverer {
+	return &Recoverer{db: db, ack: ack}
+}
+
+// Wrap returns the message bus handler recovering from panics of handler
+func (rc *Recoverer) Wrap(handler messagebus.HandlerFuncType) messagebus.HandlerFuncType {
+	return func(server messagebus.Server, message proto.Message) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = rc.recovered(server.Context(), panicJobId(server.Envelope().GetCorrelationId(), message), message, p, debug.Stack())
+			}
+		}()
+
+		return handler(server, message)
+	}
+}
+
+// WrapSQS returns the SQS handler recovering from panics of handler
+func (rc *Recoverer) WrapSQS(handler sqs.HandlerFuncType) sqs.HandlerFuncType {
+	return func(server sqs.Server, message proto.Message) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = rc.recovered(server.Ctx, panicJobId(server.Envelope.GetCorrelationId(), message), message, p, debug.Stack())
+			}
+		}()
+
+		return handler(server, message)
+	}
+}
+
+// Bus returns bus recovering every handler registered on it
+func (rc *Recoverer) Bus(bus messagebus.MessageBus) messagebus.MessageBus {
+	return &recoveringBus{MessageBus: bus, rc: rc}
+}
+
+// Consumer returns consumer recovering every handler registered on it
+func (rc *Recoverer) Consumer(consumer sqs.Consumer) sqs.Consumer {
+	return &recoveringConsumer{Consumer: consumer, rc: rc}
+}
+
+type recoveringBus struct {
+	messagebus.MessageBus
+	rc *Recoverer
+}
+
+func (b *recoveringBus) Register(m proto.Message, h messagebus.HandlerFuncType) {
+	b.MessageBus.Register(m, b.rc.Wrap(h))
+}
+
+type recoveringConsumer struct {
+	sqs.Consumer
+	rc *Recoverer
+}
+
+func (c *recoveringConsumer) Register(m proto.Message, h sqs.HandlerFuncType) {
+	c.Consumer.Register(m, c.rc.WrapSQS(h))
+}
+
+// recovered logs the panic with its stack and fails the correlated job, it returns the handler error
+func (rc *Recoverer) recovered(ctx context.Context, jobId string, message proto.Message, p any, stack []byte) error {
+	messageType := string(message.ProtoReflect().Descriptor().FullName())
+	err := fmt.Errorf("panic while handling message: %v", p)
+
+	metrics.HandlerPanics.WithLabelValues(messageType).Inc()
+
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.CorrelationID: jobId,
+	})
+
+	logger.Error("Message handler panicked", log.Fields{
+		types.LogFieldKeys.Error: err,
+		"message_type":           messageType,
+		"stack":                  string(stack),
+	})
+
+	if rc.db != nil && jobId != "" {
+		rc.failJob(ctx, jobId, err.Error())
+	}
+
+	if rc.ack {
+		return nil
+	}
+
+	return err
+}
+
+// failJob fails the job unless it is already final. Retries are disabled as the handler
+// would most likely panic again.
+func (rc *Recoverer) failJob(ctx context.Context, jobId string, detail string) {
+	logger := log.CreateChildLogger(log.Fields{
+		types.LogFieldKeys.JobID: jobId,
+	})
+
+	err := rc.db.WithTransaction(func(tx database.Database) error {
+		job, err := tx.GetJobById(ctx, jobId, true)
+		if err != nil {
+			return err
+		}
+
+		active := []string{types.JobStatus.Submitted, types.JobStatus.Dispatching, types.JobStatus.Processing}
+		if !slices.Contains(active, types.SafeDeref(job.Info.JobStatusName)) {
+			logger.Warn("Job of panicked handler is already final", log.Fields{
+				types.LogFieldKeys.Status: job.Info.JobStatusName,
+			})
+			return nil
+		}
+
+		err = tx.DisableJobRetries(ctx, jobId)
+		if err != nil {
+			return err
+		}
+
+		code := types.EppCode.CommandFailed
+		jrd := &types.JobResultData{}
+		jrd.SetErrorDetails(&code, &detail)
+
+		job.ResultMessage = types.ToPointer(PanicResultMessage)
+
+		return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, jrd)
+	})
+	if err != nil {
+		logger.Error("Failed to fail job of panicked handler", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+}
+
+// panicJobId returns the job id of a job notification, otherwise the correlation id of a registry response
+func panicJobId(correlationId string, message proto.Message) string {
+	if jobId := notificationJobId(nil, message); jobId != "" {
+		return jobId
+	}
+
+	return correlationId
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
+	"github.com/tucowsinc/tdp-messages-go/message"
+	jobmessage "github.com/tucowsinc/tdp-messages-go/message/job"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/sqs"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+type RecovererTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	db  *database.MockDatabase
+	s   *mocks.MockMessageBusServer
+}
+
+func TestRecovererTestSuite(t *testing.T) {
+	suite.Run(t, new(RecovererTestSuite))
+}
+
+func (suite *RecovererTestSuite) SetupSuite() {
+	log.Setup(config.Config{LogLevel: "mute"})
+	suite.ctx = context.Background()
+}
+
+func (suite *RecovererTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.s = &mocks.MockMessageBusServer{}
+
+	suite.s.On("Context").Return(suite.ctx)
+	suite.s.On("Envelope").Return(&message.TcWire{CorrelationId: "response-job-id"})
+
+	call := suite.db.On("WithTransaction", mock.Anything)
+	call.Run(func(args mock.Arguments) {
+		f := args.Get(0).(func(database.Database) error)
+		call.ReturnArguments = mock.Arguments{f(suite.db)}
+	})
+}
+
+func (suite *RecovererTestSuite) mockJob(id string, status string) *model.Job {
+	job := &model.Job{
+		ID:   id,
+		Info: &model.VJob{JobStatusName: types.ToPointer(status)},
+	}
+
+	suite.db.On("GetJobById", suite.ctx, id, true).Return(job, nil)
+
+	return job
+}
+
+func panicking(server messagebus.Server, message proto.Message) error {
+	var job *model.Job
+	_ = *job.Info.JobTypeName
+	return nil
+}
+
+func (suite *RecovererTestSuite) TestWrapFailsNotificationJob() {
+	job := suite.mockJob("job-id", types.JobStatus.Processing)
+	suite.db.On("DisableJobRetries", suite.ctx, "job-id").Return(nil)
+	suite.db.On("SetJobStatus", suite.ctx, job, types.JobStatus.Failed, mock.MatchedBy(func(jrd *types.JobResultData) bool {
+		return *jrd.Error.EppCode == types.EppCode.CommandFailed
+	})).Return(nil)
+
+	err := NewRecoverer(suite.db, false).Wrap(panicking)(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.ErrorContains(err, "panic while handling message")
+	suite.Equal(PanicResultMessage, *job.ResultMessage)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *RecovererTestSuite) TestWrapFailsCorrelatedJobAndAcks() {
+	job := suite.mockJob("response-job-id", types.JobStatus.Dispatching)
+	suite.db.On("DisableJobRetries", suite.ctx, "response-job-id").Return(nil)
+	suite.db.On("SetJobStatus", suite.ctx, job, types.JobStatus.Failed, mock.Anything).Return(nil)
+
+	err := NewRecoverer(suite.db, true).Wrap(panicking)(suite.s, &message.ErrorResponse{})
+	suite.NoError(err)
+	suite.db.AssertExpectations(suite.T())
+}
+
+func (suite *RecovererTestSuite) TestWrapSkipsFinalJob() {
+	suite.mockJob("job-id", types.JobStatus.Completed)
+
+	err := NewRecoverer(suite.db, false).Wrap(panicking)(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.Error(err)
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RecovererTestSuite) TestWrapWithoutPanic() {
+	err := NewRecoverer(suite.db, false).Wrap(func(server messagebus.Server, message proto.Message) error {
+		return nil
+	})(suite.s, &jobmessage.Notification{JobId: "job-id"})
+	suite.NoError(err)
+	suite.db.AssertNotCalled(suite.T(), "GetJobById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RecovererTestSuite) TestWrapSQS() {
+	err := NewRecoverer(nil, false).WrapSQS(func(server sqs.Server, message proto.Message) error {
+		panic("index out of range")
+	})(sqs.Server{Ctx: suite.ctx}, &message.ErrorResponse{})
+	suite.ErrorContains(err, "index out of range")
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	"github.com/tucowsinc/tdp-messages-go/message/job"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// HandlerFunc is a message bus handler function
+type HandlerFunc func(server messagebus.Server, message proto.Message) error
+
+// Router dispatches job notifications to handlers by job type
+type Router struct {
+	routes map[string]HandlerFunc
+}
+
+// RouteReport lists the differences between the routes of a worker and the job types in the database
+type RouteReport struct {
+	// Unhandled job types are routed to the worker queue but have no handler
+	Unhandled []string
+	// Unknown job types have a handler but do not exist in the database
+	Unknown []string
+	// Misrouted job types have a handler but are routed to another queue
+	Misrouted []string
+}
+
+// NewRouter creates an empty router
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]HandlerFunc)}
+}
+
+// Handle routes the job types to the handler, a job type can only be routed once
+func (r *Router) Handle(handler HandlerFunc, jobTypes ...string) *Router {
+	for _, jobType := range jobTypes {
+		if _, ok := r.routes[jobType]; ok {
+			panic(fmt.Sprintf("job type %q is already routed", jobType))
+		}
+
+		r.routes[jobType] = handler
+	}
+
+	return r
+}
+
+// JobTypes returns the routed job types sorted by name
+func (r *Router) JobTypes() (jobTypes []string) {
+	for jobType := range r.routes {
+		jobTypes = append(jobTypes, jobType)
+	}
+
+	sort.Strings(jobTypes)
+
+	return
+}
+
+// Route is the message bus handler function dispatching job notifications
+func (r *Router) Route(s messagebus.Server, m proto.Message) error {
+	// we need to type-cast the proto.Message to the wanted type
+	request := m.(*job.Notification)
+
+	handler, ok := r.routes[request.Type]
+	if !ok {
+		err := fmt.Errorf("no handlers for type: %s", request.Type)
+
+		log.Error("No handler found for job type", log.Fields{
+			types.LogFieldKeys.Message: request.String(),
+			types.LogFieldKeys.Error:   err,
+		})
+
+		return err
+	}
+
+	return handler(s, m)
+}
+
+// Check compares the routes with the job types in the database for the given worker queue
+func (r *Router) Check(ctx context.Context, db database.Database, queue string) (report RouteReport, err error) {
+	jobTypes, err := db.GetJobTypes(ctx)
+	if err != nil {
+		return
+	}
+
+	known := make(map[string]bool, len(jobTypes))
+
+	for _, jt := range jobTypes {
+		known[jt.Name] = true
+
+		_, handled := r.routes[jt.Name]
+		routingKey := types.SafeDeref(jt.RoutingKey)
+
+		switch {
+		case routingKey == queue && !handled:
+			report.Unhandled = append(report.Unhandled, jt.Name)
+		case routingKey != "" && routingKey != queue && handled:
+			report.Misrouted = append(report.Misrouted, jt.Name)
+		}
+	}
+
+	for _, jobType := range r.JobTypes() {
+		if !known[jobType] {
+			report.Unknown = append(report.Unknown, jobType)
+		}
+	}
+
+	return
+}
+
+// Validate checks the routes at worker startup and logs job types which are not handled as expected
+func (r *Router) Validate(ctx context.Context, db database.Database, queue string) {
+	report, err := r.Check(ctx, db, queue)
+	if err != nil {
+		log.Error("Failed to validate job type routes", log.Fields{
+			types.LogFieldKeys.Queue: queue,
+			types.LogFieldKeys.Error: err,
+		})
+		return
+	}
+
+	if len(report.Unhandled) > 0 {
+		log.Error("Job types routed to the queue have no handler", log.Fields{
+			types.LogFieldKeys.Queue: queue,
+			"job_types":              report.Unhandled,
+		})
+	}
+
+	if len(report.Unknown) > 0 {
+		log.Warn("Handlers are routed for job types which do not exist", log.Fields{
+			types.LogFieldKeys.Queue: queue,
+			"job_types":              report.Unknown,
+		})
+	}
+
+	if len(report.Misrouted) > 0 {
+		log.Warn("Handlers are routed for job types sent to another queue", log.Fields{
+			types.LogFieldKeys.Queue: queue,
+			"job_types":              report.Misrouted,
+		})
+	}
+
+	if len(report.Unhandled)+len(report.Unknown)+len(report.Misrouted) == 0 {
+		log.Info("Job type routes match the database", log.Fields{
+			types.LogFieldKeys.Queue: queue,
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
+	jobmessage "github.com/tucowsinc/tdp-messages-go/message/job"
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+type RouterTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestRouterTestSuite(t *testing.T) {
+	suite.Run(t, new(RouterTestSuite))
+}
+
+func (suite *RouterTestSuite) SetupSuite() {
+	log.Setup(config.Config{LogLevel: "mute"})
+	suite.ctx = context.Background()
+}
+
+func (suite *RouterTestSuite) TestRoute() {
+	var routed []string
+	handler := func(name string) HandlerFunc {
+		return func(messagebus.Server, proto.Message) error {
+			routed = append(routed, name)
+			return nil
+		}
+	}
+
+	router := NewRouter().
+		Handle(handler("create"), "provision_test_create").
+		Handle(handler("update"), "provision_test_update", "provision_test_update_other")
+
+	s := &mocks.MockMessageBusServer{}
+
+	suite.NoError(router.Route(s, &jobmessage.Notification{Type: "provision_test_create"}))
+	suite.NoError(router.Route(s, &jobmessage.Notification{Type: "provision_test_update_other"}))
+	suite.Error(router.Route(s, &jobmessage.Notification{Type: "provision_test_delete"}))
+
+	suite.Equal([]string{"create", "update"}, routed)
+	suite.Equal([]string{"provision_test_create", "provision_test_update", "provision_test_update_other"}, router.JobTypes())
+}
+
+func (suite *RouterTestSuite) TestHandleDuplicate() {
+	handler := func(messagebus.Server, proto.Message) error { return nil }
+
+	suite.Panics(func() {
+		NewRouter().Handle(handler, "provision_test_create").Handle(handler, "provision_test_create")
+	})
+}
+
+func (suite *RouterTestSuite) TestCheck() {
+	handler := func(messagebus.Server, proto.Message) error { return nil }
+
+	db := &database.MockDatabase{}
+	db.On("GetJobTypes", suite.ctx).Return([]model.JobType{
+		{Name: "provision_test_create", RoutingKey: types.ToPointer("WorkerJobTestProvision")},
+		{Name: "provision_test_update", RoutingKey: types.ToPointer("WorkerJobTestProvision")},
+		{Name: "provision_test_other", RoutingKey: types.ToPointer("WorkerJobOtherProvision")},
+		{Name: "validate_test", RoutingKey: nil},
+	}, nil)
+
+	router := NewRouter().
+		Handle(handler, "provision_test_create").
+		Handle(handler, "provision_test_other").
+		Handle(handler, "validate_test").
+		Handle(handler, "provision_test_removed")
+
+	report, err := router.Check(suite.ctx, db, "WorkerJobTestProvision")
+	suite.NoError(err)
+
+	suite.Equal([]string{"provision_test_update"}, report.Unhandled)
+	suite.Equal([]string{"provision_test_removed"}, report.Unknown)
+	suite.Equal([]string{"provision_test_other"}, report.Misrouted)
+}
@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexliesenfeld/health"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
+)
+
+// MetricsPath is the path metrics are exposed on, every other path serves the health status
+const MetricsPath = "/metrics"
+
+// DefaultFrequency is the interval between two runs of a registered check
+const DefaultFrequency = 10 * time.Second
+
+// Server serves worker health checks and Prometheus metrics on a single port
+type Server struct {
+	port    int
+	options []health.CheckerOption
+}
+
+type checkOptions struct {
+	frequency time.Duration
+	timeout   time.Duration
+}
+
+// CheckOption configures a registered health check
+type CheckOption func(*checkOptions)
+
+// WithFrequency sets the interval between two runs of the check
+func WithFrequency(frequency time.Duration) CheckOption {
+	return func(o *checkOptions) {
+		o.frequency = frequency
+	}
+}
+
+// WithTimeout sets the maximum duration of a single run of the check
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(o *checkOptions) {
+		o.timeout = timeout
+	}
+}
+
+// New creates a health check server listening on the given port
+func New(port int) *Server {
+	return &Server{port: port}
+}
+
+// RegisterHealthCheck adds a check which is run periodically in the background
+func (s *Server) RegisterHealthCheck(check health.Check, opts ...CheckOption) {
+	o := checkOptions{frequency: DefaultFrequency}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.timeout > 0 {
+		check.Timeout = o.timeout
+	}
+
+	s.options = append(s.options, health.WithPeriodicCheck(o.frequency, 0, check))
+}
+
+// Handler returns the http handler serving metrics on MetricsPath and the health status on any other path
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, metrics.Handler())
+	mux.Handle("/", health.NewHandler(health.NewChecker(s.options...)))
+
+	return mux
+}
+
+// Start serves health checks and metrics until the context is done
+func (s *Server) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.port),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,66 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexliesenfeld/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
+)
+
+func get(t *testing.T, handler http.Handler, path string) (int, string) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	body, err := io.ReadAll(rec.Result().Body)
+	require.NoError(t, err)
+
+	return rec.Code, string(body)
+}
+
+func TestHealthCheckUp(t *testing.T) {
+	server := New(0)
+	server.RegisterHealthCheck(health.Check{
+		Name:  "test",
+		Check: func(context.Context) error { return nil },
+	}, WithFrequency(time.Minute), WithTimeout(time.Second))
+
+	handler := server.Handler()
+
+	assert.Eventually(t, func() bool {
+		code, _ := get(t, handler, "/health")
+		return code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHealthCheckDown(t *testing.T) {
+	server := New(0)
+	server.RegisterHealthCheck(health.Check{
+		Name:  "test",
+		Check: func(context.Context) error { return errors.New("down") },
+	}, WithFrequency(time.Minute))
+
+	handler := server.Handler()
+
+	assert.Eventually(t, func() bool {
+		code, _ := get(t, handler, "/")
+		return code == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	metrics.JobsHandled.WithLabelValues("provision_domain_create", "completed").Inc()
+
+	code, body := get(t, New(0).Handler(), MetricsPath)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `tdp_workers_jobs_handled_total{job_type="provision_domain_create",status="completed"}`)
+}
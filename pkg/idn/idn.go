@@ -0,0 +1,185 @@
+package idn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/net/idna"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+	"github.com/tucowsinc/tdp-workers-go/pkg/tldsetting"
+)
+
+// SupportedLanguagesSetting is the TLD setting listing the IDN tables or language tags of the registry
+const SupportedLanguagesSetting = "tld.order.supported_idn_lang_tags"
+
+// ErrInvalid is wrapped by the errors of names, unames and languages which are not valid
+var ErrInvalid = joberrors.Permanent(errors.New("invalid internationalized domain name"))
+
+// profile applies the UTS-46 mapping with non-transitional processing, IDNA2008 label validation,
+// the bidi rule and STD3 and DNS length restrictions
+var profile = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.BidiRule(),
+	idna.StrictDomainName(true),
+	idna.VerifyDNSLength(true),
+)
+
+// scriptSets are the combinations of scripts which may be mixed within a label
+var scriptSets = [][]string{
+	{"Han", "Hiragana", "Katakana"},
+	{"Hangul", "Han"},
+	{"Han", "Bopomofo"},
+}
+
+// languageScripts maps language and ISO 15924 script subtags to the scripts their labels may use
+var languageScripts = map[string][]string{
+	// languages
+	"ar": {"Arabic"}, "fa": {"Arabic"}, "ur": {"Arabic"},
+	"be": {"Cyrillic"}, "bg": {"Cyrillic"}, "mk": {"Cyrillic"}, "ru": {"Cyrillic"}, "sr": {"Cyrillic"}, "uk": {"Cyrillic"},
+	"el": {"Greek"},
+	"he": {"Hebrew"},
+	"hi": {"Devanagari"},
+	"th": {"Thai"},
+	"ja": {"Han", "Hiragana", "Katakana"},
+	"ko": {"Hangul", "Han"},
+	"zh": {"Han", "Bopomofo"},
+	"da": {"Latin"}, "de": {"Latin"}, "es": {"Latin"}, "fi": {"Latin"}, "fr": {"Latin"}, "hu": {"Latin"},
+	"is": {"Latin"}, "it": {"Latin"}, "lt": {"Latin"}, "lv": {"Latin"}, "nl": {"Latin"}, "no": {"Latin"},
+	"pl": {"Latin"}, "pt": {"Latin"}, "sv": {"Latin"}, "tr": {"Latin"},
+	// scripts
+	"latn": {"Latin"},
+	"cyrl": {"Cyrillic"},
+	"grek": {"Greek"},
+	"arab": {"Arabic"},
+	"hebr": {"Hebrew"},
+	"deva": {"Devanagari"},
+	"thai": {"Thai"},
+	"hani": {"Han"}, "hans": {"Han"}, "hant": {"Han"},
+	"jpan": {"Han", "Hiragana", "Katakana"},
+	"kore": {"Hangul", "Han"},
+}
+
+// Normalize converts the domain name to its A-label and U-label forms, U-labels are mapped and
+// validated according to IDNA2008 and UTS-46. It fails for invalid labels and labels mixing scripts.
+func Normalize(name string) (ascii string, unicodeName string, err error) {
+	ascii, err = profile.ToASCII(strings.TrimSuffix(name, "."))
+	if err != nil {
+		return "", "", fmt.Errorf("%w %q: %v", ErrInvalid, name, err)
+	}
+
+	unicodeName, err = profile.ToUnicode(ascii)
+	if err != nil {
+		return "", "", fmt.Errorf("%w %q: %v", ErrInvalid, name, err)
+	}
+
+	for _, label := range strings.Split(unicodeName, ".") {
+		scripts := labelScripts(label)
+		if len(scripts) > 1 && !allowedMix(scripts) {
+			return "", "", fmt.Errorf("%w %q: label %q mixes scripts %v", ErrInvalid, name, label, scripts)
+		}
+	}
+
+	return ascii, unicodeName, nil
+}
+
+// CheckLanguage checks the IDN table or language tag is supported by the registry and the registered
+// label, the leftmost label of the U-label name, only uses the scripts of the language. Every tag is
+// allowed when supported is empty, labels of tags which scripts are unknown are not checked.
+func CheckLanguage(unicodeName string, language string, supported []string) error {
+	if language == "" {
+		return nil
+	}
+
+	if len(supported) > 0 && !slices.ContainsFunc(supported, func(s string) bool { return strings.EqualFold(s, language) }) {
+		return fmt.Errorf("%w: language %q is not supported, supported languages are %v", ErrInvalid, language, supported)
+	}
+
+	allowed := tagScripts(language)
+	if allowed == nil {
+		return nil
+	}
+
+	label, _, _ := strings.Cut(unicodeName, ".")
+	for _, script := range labelScripts(label) {
+		if !slices.Contains(allowed, script) {
+			return fmt.Errorf("%w: label %q uses script %s not allowed for language %q", ErrInvalid, label, script, language)
+		}
+	}
+
+	return nil
+}
+
+// LoadSupportedLanguages reads the IDN tables or language tags supported for the accreditation tld
+func LoadSupportedLanguages(ctx context.Context, db database.Database, accreditationTldId string) ([]string, error) {
+	return tldsetting.StringList(ctx, db, accreditationTldId, SupportedLanguagesSetting)
+}
+
+// labelScripts returns the sorted scripts of the letters of label, common and inherited characters
+// such as digits, hyphens and combining marks belong to any script
+func labelScripts(label string) (scripts []string) {
+	for _, r := range label {
+		if unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+
+		for name, table := range unicode.Scripts {
+			if unicode.Is(table, r) {
+				if !slices.Contains(scripts, name) {
+					scripts = append(scripts, name)
+				}
+				break
+			}
+		}
+	}
+
+	sort.Strings(scripts)
+
+	return
+}
+
+// allowedMix tells if scripts may be mixed within a label
+func allowedMix(scripts []string) bool {
+	for _, set := range scriptSets {
+		if containsAll(set, scripts) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagScripts returns the scripts of a language tag, a script subtag takes precedence over the language
+func tagScripts(tag string) []string {
+	subtags := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool { return r == '-' || r == '_' })
+	if len(subtags) == 0 {
+		return nil
+	}
+
+	for _, subtag := range subtags[1:] {
+		if len(subtag) == 4 {
+			if scripts, ok := languageScripts[subtag]; ok {
+				return scripts
+			}
+		}
+	}
+
+	return languageScripts[subtags[0]]
+}
+
+func containsAll(set []string, values []string) bool {
+	for _, v := range values {
+		if !slices.Contains(set, v) {
+			return false
+		}
+	}
+
+	return true
+}
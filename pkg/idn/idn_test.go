@@ -0,0 +1,74 @@
+package idn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		ascii   string
+		unicode string
+	}{
+		{"Bücher.example", "xn--bcher-kva.example", "bücher.example"},
+		{"xn--bcher-kva.example", "xn--bcher-kva.example", "bücher.example"},
+		{"EXAMPLE.com.", "example.com", "example.com"},
+		{"straße.de", "xn--strae-oqa.de", "straße.de"},
+		{"ドメイン名例.jp", "xn--eckwd4c7cu47r2wf.jp", "ドメイン名例.jp"},
+		{"한국어도메인.com", "xn--3e0b73j92f06mw8bq76a.com", "한국어도메인.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ascii, unicodeName, err := Normalize(tt.name)
+			require.NoError(t, err)
+			require.Equal(t, tt.ascii, ascii)
+			require.Equal(t, tt.unicode, unicodeName)
+		})
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	for _, name := range []string{
+		"ex_ample.com",
+		"-example.com",
+		"exa..com",
+		"xn--a.com",
+		"pаypal.com", // cyrillic a
+		"中文abc.com",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := Normalize(name)
+			require.ErrorIs(t, err, ErrInvalid)
+			require.False(t, joberrors.IsTransient(err))
+		})
+	}
+}
+
+func TestCheckLanguage(t *testing.T) {
+	require.NoError(t, CheckLanguage("bücher.example", "", nil))
+	require.NoError(t, CheckLanguage("bücher.example", "DE", []string{"de", "fr"}))
+	require.NoError(t, CheckLanguage("中文.com", "zh-Hant", nil))
+	require.NoError(t, CheckLanguage("ドメイン名例.jp", "ja", nil))
+	require.NoError(t, CheckLanguage("bücher.example", "unknown", nil))
+
+	require.ErrorIs(t, CheckLanguage("bücher.example", "es", []string{"de", "fr"}), ErrInvalid)
+	require.ErrorIs(t, CheckLanguage("bücher.example", "ru", nil), ErrInvalid)
+	require.ErrorIs(t, CheckLanguage("пример.com", "und-Latn", nil), ErrInvalid)
+}
+
+func TestLoadSupportedLanguages(t *testing.T) {
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{SupportedLanguagesSetting: "{de,fr}"})
+
+	languages, err := LoadSupportedLanguages(ctx, db, "acc-tld")
+	require.NoError(t, err)
+	require.Equal(t, []string{"de", "fr"}, languages)
+}
@@ -0,0 +1,89 @@
+package joberrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/tucowsinc/tdp-messages-go/message/common"
+)
+
+// Registry EPP codes for failures which are expected to heal on their own
+var transientEppCodes = map[int32]bool{
+	2400: true, // Command failed
+	2500: true, // Command failed; server closing connection
+	2501: true, // Authentication error; server closing connection
+	2502: true, // Session limit exceeded; server closing connection
+}
+
+// classifiedError marks the wrapped error as transient or permanent
+type classifiedError struct {
+	err       error
+	transient bool
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// Transient marks err as transient, the job failing with it can be retried later
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &classifiedError{err: err, transient: true}
+}
+
+// Permanent marks err as permanent, the job failing with it must not be retried
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &classifiedError{err: err, transient: false}
+}
+
+// IsTransient tells if err is transient. Errors explicitly marked with Transient or Permanent are classified
+// by the outermost mark, timeouts are transient and any other error is permanent.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var ce *classifiedError
+	if errors.As(err, &ce) {
+		return ce.transient
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// FromRegistryResponse returns the error of a failed registry response classified by its EPP code,
+// it returns nil for a successful response
+func FromRegistryResponse(registryResponse *common.RegistryResponse) error {
+	if registryResponse.GetIsSuccess() {
+		return nil
+	}
+
+	err := fmt.Errorf("registry error %d: %s", registryResponse.GetEppCode(), registryResponse.GetEppMessage())
+	if transientEppCodes[registryResponse.GetEppCode()] {
+		return Transient(err)
+	}
+
+	return Permanent(err)
+}
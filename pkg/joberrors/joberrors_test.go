@@ -0,0 +1,87 @@
+package joberrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tucowsinc/tdp-messages-go/message/common"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestIsTransient(t *testing.T) {
+	base := errors.New("error")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "unclassified", err: base, want: false},
+		{name: "transient", err: Transient(base), want: true},
+		{name: "wrapped transient", err: fmt.Errorf("wrapped: %w", Transient(base)), want: true},
+		{name: "permanent", err: Permanent(base), want: false},
+		{name: "permanent timeout", err: Permanent(context.DeadlineExceeded), want: false},
+		{name: "transient over permanent", err: Transient(Permanent(base)), want: true},
+		{name: "deadline exceeded", err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: true},
+		{name: "network timeout", err: &net.OpError{Op: "dial", Err: timeoutError{}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
+
+func TestClassifiedErrorUnwrap(t *testing.T) {
+	base := errors.New("error")
+
+	assert.ErrorIs(t, Transient(base), base)
+	assert.ErrorIs(t, Permanent(base), base)
+	assert.Equal(t, base.Error(), Transient(base).Error())
+	assert.Nil(t, Transient(nil))
+	assert.Nil(t, Permanent(nil))
+}
+
+func TestFromRegistryResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  *common.RegistryResponse
+		wantErr   bool
+		transient bool
+	}{
+		{name: "nil", response: nil, wantErr: true},
+		{name: "success", response: &common.RegistryResponse{IsSuccess: true, EppCode: 1000}},
+		{name: "pending", response: &common.RegistryResponse{IsSuccess: true, EppCode: 1001}},
+		{name: "object exists", response: &common.RegistryResponse{EppCode: 2302, EppMessage: "Object exists"}, wantErr: true},
+		{name: "command failed", response: &common.RegistryResponse{EppCode: 2400, EppMessage: "Command failed"}, wantErr: true, transient: true},
+		{name: "server closing connection", response: &common.RegistryResponse{EppCode: 2500}, wantErr: true, transient: true},
+		{name: "session limit exceeded", response: &common.RegistryResponse{EppCode: 2502}, wantErr: true, transient: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromRegistryResponse(tt.response)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.transient, IsTransient(err))
+		})
+	}
+}
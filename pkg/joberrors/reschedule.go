@@ -0,0 +1,74 @@
+package joberrors
+
+import (
+	"context"
+	"time"
+
+	"github.com/tucowsinc/tdp-shared-go/logger"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// Policy controls how jobs failing with transient errors are rescheduled
+type Policy struct {
+	// MaxRetries raises the number of reschedules allowed above the job own retry budget when set,
+	// by default only the job max_retries applies
+	MaxRetries int
+	// RetryBase is the first backoff for jobs without a retry interval, it doubles on every retry
+	RetryBase time.Duration
+	// RetryMax caps the backoff
+	RetryMax time.Duration
+}
+
+// DefaultPolicy is the policy used by FailOrReschedule
+var DefaultPolicy = Policy{
+	RetryBase: time.Minute,
+	RetryMax:  time.Hour,
+}
+
+// FailOrReschedule reschedules the job with the default policy when err is transient, otherwise it fails the job
+func FailOrReschedule(ctx context.Context, tx database.Database, job *model.Job, err error, jrd *types.JobResultData, logger logger.ILogger) error {
+	return DefaultPolicy.FailOrReschedule(ctx, tx, job, err, jrd, logger)
+}
+
+// FailOrReschedule resubmits the job with an exponential backoff when err is transient and the job has retries left.
+// Otherwise the job is failed, with err as result message unless one is already set.
+func (p Policy) FailOrReschedule(ctx context.Context, tx database.Database, job *model.Job, err error, jrd *types.JobResultData, logger logger.ILogger) error {
+	if job.ResultMessage == nil && err != nil {
+		resMsg := err.Error()
+		job.ResultMessage = &resMsg
+	}
+
+	if IsTransient(err) {
+		rescheduled, rerr := tx.RescheduleJob(ctx, job.ID, job.ResultMessage, p.MaxRetries, p.RetryBase, p.RetryMax)
+		if rerr != nil {
+			logger.Error("Failed to reschedule job", log.Fields{
+				types.LogFieldKeys.Error: rerr,
+			})
+			return rerr
+		}
+
+		if rescheduled {
+			jobType := ""
+			if job.Info != nil {
+				jobType = types.SafeDeref(job.Info.JobTypeName)
+			}
+			metrics.JobsRescheduled.WithLabelValues(jobType).Inc()
+
+			logger.Warn("Rescheduled job after transient error", log.Fields{
+				types.LogFieldKeys.Error: err,
+				"retry_count":            types.SafeDeref(job.RetryCount) + 1,
+			})
+			return nil
+		}
+
+		logger.Warn("Job has no retries left, failing it", log.Fields{
+			types.LogFieldKeys.Error: err,
+		})
+	}
+
+	return tx.SetJobStatus(ctx, job, types.JobStatus.Failed, jrd)
+}
@@ -0,0 +1,86 @@
+package joberrors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+type RescheduleTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	db  *database.MockDatabase
+	job *model.Job
+}
+
+func TestRescheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(RescheduleTestSuite))
+}
+
+func (suite *RescheduleTestSuite) SetupSuite() {
+	log.Setup(config.Config{LogLevel: "mute"})
+	suite.ctx = context.Background()
+}
+
+func (suite *RescheduleTestSuite) SetupTest() {
+	suite.db = &database.MockDatabase{}
+	suite.job = &model.Job{
+		ID:   "job-id",
+		Info: &model.VJob{JobTypeName: types.ToPointer("provision_domain_create")},
+	}
+}
+
+func (suite *RescheduleTestSuite) TestTransientRescheduled() {
+	err := Transient(errors.New("bus unavailable"))
+
+	// only the job own max_retries limits the retries
+	suite.db.On("RescheduleJob", suite.ctx, "job-id", types.ToPointer("bus unavailable"),
+		0, DefaultPolicy.RetryBase, DefaultPolicy.RetryMax).Return(true, nil)
+
+	suite.NoError(FailOrReschedule(suite.ctx, suite.db, suite.job, err, nil, log.GetLogger()))
+
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RescheduleTestSuite) TestTransientWithoutRetriesLeftFailed() {
+	err := Transient(errors.New("bus unavailable"))
+
+	suite.db.On("RescheduleJob", suite.ctx, "job-id", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	suite.db.On("SetJobStatus", suite.ctx, suite.job, types.JobStatus.Failed, (*types.JobResultData)(nil)).Return(nil)
+
+	suite.NoError(FailOrReschedule(suite.ctx, suite.db, suite.job, err, nil, log.GetLogger()))
+
+	suite.db.AssertExpectations(suite.T())
+	suite.Equal("bus unavailable", *suite.job.ResultMessage)
+}
+
+func (suite *RescheduleTestSuite) TestPermanentFailed() {
+	jrd := &types.JobResultData{}
+	suite.job.ResultMessage = types.ToPointer("Object exists")
+
+	suite.db.On("SetJobStatus", suite.ctx, suite.job, types.JobStatus.Failed, jrd).Return(nil)
+
+	suite.NoError(FailOrReschedule(suite.ctx, suite.db, suite.job, Permanent(errors.New("registry error 2302")), jrd, log.GetLogger()))
+
+	suite.db.AssertNotCalled(suite.T(), "RescheduleJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.Equal("Object exists", *suite.job.ResultMessage)
+}
+
+func (suite *RescheduleTestSuite) TestRescheduleError() {
+	dbErr := errors.New("db error")
+
+	suite.db.On("RescheduleJob", suite.ctx, "job-id", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, dbErr)
+
+	suite.ErrorIs(FailOrReschedule(suite.ctx, suite.db, suite.job, Transient(errors.New("timeout")), nil, log.GetLogger()), dbErr)
+
+	suite.db.AssertNotCalled(suite.T(), "SetJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
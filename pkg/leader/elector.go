@@ -0,0 +1,267 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+const DefaultCheckInterval = 5 * time.Second
+
+var (
+	ErrStandby        = errors.New("standby, leader lock is held by another instance")
+	ErrNotConnected   = errors.New("leader election connection is not established")
+	ErrAlreadyRunning = errors.New("leader election is already running")
+)
+
+// Role is the current role of the instance in leader election
+type Role string
+
+const (
+	RoleUnknown Role = "unknown"
+	RoleLeader  Role = "leader"
+	RoleStandby Role = "standby"
+)
+
+// Elector elects a single active instance among replicas using a postgres session advisory lock.
+//
+// The lock is held by a dedicated connection; when that connection dies postgres releases
+// the lock and one of the standby instances takes over.
+type Elector struct {
+	Connect func(ctx context.Context) (*pgx.Conn, error)
+
+	// LockName identifies the lock, all replicas of a service must use the same name
+	LockName string
+	// CheckInterval is how often the leader verifies its connection and standby retries the lock
+	CheckInterval time.Duration
+
+	mu        sync.RWMutex
+	role      Role
+	connected bool
+	running   bool
+}
+
+// New creates leader elector for the given lock name
+func New(connString string, lockName string, checkInterval time.Duration) (*Elector, error) {
+	conf, err := pgx.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert conn string to pgx ParseConfig: %w", err)
+	}
+
+	return &Elector{
+		Connect: func(ctx context.Context) (*pgx.Conn, error) {
+			return pgx.ConnectConfig(ctx, conf)
+		},
+		LockName:      lockName,
+		CheckInterval: checkInterval,
+		role:          RoleUnknown,
+	}, nil
+}
+
+// Run takes part in the election until ctx is done.
+// lead is called every time this instance becomes the leader; its context is cancelled
+// as soon as leadership is lost, and Run waits for lead to return before contending again.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context)) error {
+	if e.Connect == nil {
+		return fmt.Errorf("leader election: Connect is nil")
+	}
+
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	e.running = true
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		e.running = false
+		e.mu.Unlock()
+	}()
+
+	for {
+		err := e.campaign(ctx, lead)
+		if err != nil && ctx.Err() == nil {
+			log.Error("Leader election failed", log.Fields{
+				"lock":                   e.LockName,
+				types.LogFieldKeys.Error: err.Error(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			e.setState(RoleUnknown, false)
+			return ctx.Err()
+		case <-time.After(e.interval()):
+		}
+	}
+}
+
+// campaign connects to the database and tries to acquire the lock until the connection fails
+func (e *Elector) campaign(ctx context.Context, lead func(ctx context.Context)) error {
+	conn, err := e.Connect(ctx)
+	if err != nil {
+		e.setState(RoleUnknown, false)
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	defer func() {
+		// closing the connection releases the lock if we still hold it
+		closeCtx, cancel := context.WithTimeout(context.Background(), e.interval())
+		defer cancel()
+		conn.Close(closeCtx)
+	}()
+
+	e.setState(RoleStandby, true)
+
+	ticker := time.NewTicker(e.interval())
+	defer ticker.Stop()
+
+	for {
+		acquired, err := e.tryLock(ctx, conn)
+		if err != nil {
+			e.setState(RoleUnknown, false)
+			return err
+		}
+
+		if acquired {
+			return e.lead(ctx, conn, lead)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs lead function while the lock connection stays healthy
+func (e *Elector) lead(ctx context.Context, conn *pgx.Conn, lead func(ctx context.Context)) (err error) {
+	e.setState(RoleLeader, true)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		lead(leaderCtx)
+	}()
+
+	defer func() {
+		cancel()
+		<-done
+
+		if err != nil {
+			e.setState(RoleUnknown, false)
+			return
+		}
+
+		e.setState(RoleStandby, true)
+	}()
+
+	ticker := time.NewTicker(e.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			// lead returned on its own, give up the lock so another instance can take over
+			_, err = conn.Exec(ctx, "SELECT pg_advisory_unlock(hashtext($1))", e.LockName)
+			return err
+		case <-ticker.C:
+			pingCtx, pingCancel := context.WithTimeout(ctx, e.interval())
+			err = conn.Ping(pingCtx)
+			pingCancel()
+
+			if err != nil {
+				// the lock is released together with the connection, step down immediately
+				return fmt.Errorf("leader lock connection lost: %w", err)
+			}
+		}
+	}
+}
+
+func (e *Elector) tryLock(ctx context.Context, conn *pgx.Conn) (acquired bool, err error) {
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", e.LockName).Scan(&acquired)
+	if err != nil {
+		return false, fmt.Errorf("try advisory lock %q: %w", e.LockName, err)
+	}
+
+	return
+}
+
+func (e *Elector) setState(role Role, connected bool) {
+	e.mu.Lock()
+	previous := e.role
+	e.role = role
+	e.connected = connected
+	e.mu.Unlock()
+
+	if previous != role {
+		log.Info("Leadership changed", log.Fields{
+			"lock":     e.LockName,
+			"previous": string(previous),
+			"role":     string(role),
+		})
+	}
+}
+
+func (e *Elector) interval() time.Duration {
+	if e.CheckInterval <= 0 {
+		return DefaultCheckInterval
+	}
+
+	return e.CheckInterval
+}
+
+// Role returns the current role of this instance
+func (e *Elector) Role() Role {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.role == "" {
+		return RoleUnknown
+	}
+
+	return e.role
+}
+
+// IsLeader returns true if this instance currently holds the leader lock
+func (e *Elector) IsLeader() bool {
+	return e.Role() == RoleLeader
+}
+
+// Ping returns an error if the elector has no live connection to take part in the election
+func (e *Elector) Ping(ctx context.Context) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if !e.connected {
+		return ErrNotConnected
+	}
+
+	return nil
+}
+
+// CheckRole returns ErrStandby if this instance is not the leader
+func (e *Elector) CheckRole(ctx context.Context) error {
+	switch role := e.Role(); role {
+	case RoleLeader:
+		return nil
+	case RoleStandby:
+		return ErrStandby
+	default:
+		return fmt.Errorf("role %s: %w", role, ErrNotConnected)
+	}
+}
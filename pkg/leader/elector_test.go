@@ -0,0 +1,108 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+)
+
+func newTestElector(t *testing.T) *Elector {
+	configuration, err := config.LoadConfiguration("../../.env")
+	require.NoError(t, err)
+
+	configuration.LogLevel = "mute" // suppress log output
+	log.Setup(configuration)
+
+	elector, err := New(configuration.DBConnStr(), "leader_test", 100*time.Millisecond)
+	require.NoError(t, err)
+
+	return elector
+}
+
+func TestElectorSingleLeader(t *testing.T) {
+	first := newTestElector(t)
+	second := newTestElector(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	firstCtx, firstCancel := context.WithCancel(ctx)
+	defer firstCancel()
+
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		first.Run(firstCtx, func(ctx context.Context) { <-ctx.Done() })
+	}()
+
+	require.Eventually(t, first.IsLeader, 5*time.Second, 50*time.Millisecond)
+
+	go second.Run(ctx, func(ctx context.Context) { <-ctx.Done() })
+
+	require.Eventually(t, func() bool { return second.Role() == RoleStandby }, 5*time.Second, 50*time.Millisecond)
+	require.ErrorIs(t, second.CheckRole(ctx), ErrStandby)
+	require.NoError(t, second.Ping(ctx))
+
+	// first instance goes away, its connection is closed and the lock released
+	firstCancel()
+	<-firstDone
+
+	require.Eventually(t, second.IsLeader, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, RoleUnknown, first.Role())
+}
+
+func TestElectorLeadReturns(t *testing.T) {
+	elector := newTestElector(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	elected := make(chan struct{}, 10)
+
+	go elector.Run(ctx, func(ctx context.Context) {
+		elected <- struct{}{}
+	})
+
+	// lock is released after lead returns and acquired again on the next campaign
+	for i := 0; i < 2; i++ {
+		select {
+		case <-elected:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected to be elected")
+		}
+	}
+}
+
+func TestElectorRun_NoConnectFunc(t *testing.T) {
+	elector := &Elector{}
+
+	err := elector.Run(context.Background(), func(ctx context.Context) {})
+	require.Error(t, err)
+}
+
+func TestElectorRun_ConnectError(t *testing.T) {
+	elector := &Elector{
+		Connect: func(ctx context.Context) (*pgx.Conn, error) {
+			return nil, context.DeadlineExceeded
+		},
+		CheckInterval: 10 * time.Millisecond,
+	}
+
+	log.Setup(config.Config{LogLevel: "mute"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := elector.Run(ctx, func(ctx context.Context) {
+		t.Fatalf("must not be elected")
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, elector.Ping(ctx), ErrNotConnected)
+	require.Equal(t, RoleUnknown, elector.Role())
+}
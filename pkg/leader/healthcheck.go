@@ -0,0 +1,25 @@
+package leader
+
+import (
+	"math"
+
+	"github.com/alexliesenfeld/health"
+)
+
+// HealthCheck creates a new leader election connection health check.
+func HealthCheck(elector *Elector) health.Check {
+	return health.Check{
+		Name:  "LeaderElection",
+		Check: elector.Ping,
+	}
+}
+
+// RoleCheck creates a check reporting the role of the instance.
+// Standby is reported as an error in check details but never marks the service as down.
+func RoleCheck(elector *Elector) health.Check {
+	return health.Check{
+		Name:               "LeaderRole",
+		Check:              elector.CheckRole,
+		MaxContiguousFails: math.MaxUint32,
+	}
+}
@@ -0,0 +1,115 @@
+package lease
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/tucowsinc/tdp-shared-go/logger"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/idn"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// Object types a lease can be taken on
+const (
+	ObjectDomain  = "domain"
+	ObjectContact = "contact"
+	ObjectHost    = "host"
+)
+
+// DefaultTTL bounds how long a lease is held by a job which never gets a response
+const DefaultTTL = 10 * time.Minute
+
+// MaxDeferWait caps how long a job is deferred when the lease is held, leases are usually released
+// as soon as the holder response is handled, long before they expire
+const MaxDeferWait = 30 * time.Second
+
+// Key identifies the registry object a lease is taken on
+type Key struct {
+	ObjectType        string
+	ObjectName        string
+	AccreditationName string
+}
+
+// Domain returns the lease key of a domain by its A-label name, so jobs naming the domain by
+// its U-label or its A-label take the same lease
+func Domain(name string, accreditationName string) Key {
+	return Key{ObjectType: ObjectDomain, ObjectName: objectName(name), AccreditationName: accreditationName}
+}
+
+// Contact returns the lease key of a contact by its registry handle
+func Contact(handle string, accreditationName string) Key {
+	return Key{ObjectType: ObjectContact, ObjectName: handle, AccreditationName: accreditationName}
+}
+
+// Host returns the lease key of a host
+func Host(name string, accreditationName string) Key {
+	return Key{ObjectType: ObjectHost, ObjectName: objectName(name), AccreditationName: accreditationName}
+}
+
+// objectName returns the A-label of the name, names which are not valid IDNs are only lowercased
+// as the command fails for them anyway
+func objectName(name string) string {
+	ascii, _, err := idn.Normalize(name)
+	if err != nil {
+		return strings.ToLower(name)
+	}
+
+	return ascii
+}
+
+// DeferJob takes the lease of the object before a registry command is sent for it.
+//
+// The lease is held by the job and released by the database once the job is no longer processing,
+// that is once its response is handled, or after DefaultTTL. When another job holds the lease the
+// job start date is moved forward and true is returned; the caller must not send the command, the
+// job is dispatched again at its new start date.
+//
+// The lease is taken within tx so it is not left behind when tx is rolled back; a claimed job passes
+// the database itself. Errors while taking the lease are logged and the command is allowed.
+func DeferJob(ctx context.Context, tx database.Database, job *model.Job, key Key, logger logger.ILogger) (deferred bool, err error) {
+	wait, err := tx.AcquireObjectLease(ctx, key.ObjectType, key.ObjectName, key.AccreditationName, job.ID, DefaultTTL)
+	if err != nil {
+		logger.Warn("Failed to take object lease, sending without lease", log.Fields{
+			types.LogFieldKeys.Error: err,
+			"object_type":            key.ObjectType,
+			"object_name":            key.ObjectName,
+		})
+		return false, nil
+	}
+
+	if wait <= 0 {
+		return false, nil
+	}
+
+	if wait > MaxDeferWait {
+		wait = MaxDeferWait
+	}
+
+	startDate := time.Now().Add(wait + time.Duration(rand.Int63n(int64(wait)+1)))
+	job.StartDate = &startDate
+
+	err = tx.UpdateJob(ctx, job)
+	if err != nil {
+		logger.Error("Failed to defer job waiting for object lease", log.Fields{
+			types.LogFieldKeys.Error: err,
+			"object_type":            key.ObjectType,
+			"object_name":            key.ObjectName,
+		})
+		return false, err
+	}
+
+	logger.Info("Object lease is held by another job, job deferred", log.Fields{
+		"object_type":   key.ObjectType,
+		"object_name":   key.ObjectName,
+		"accreditation": key.AccreditationName,
+		"start_date":    startDate,
+	})
+
+	return true, nil
+}
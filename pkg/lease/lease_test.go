@@ -0,0 +1,95 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+)
+
+func setup() {
+	log.Setup(config.Config{LogLevel: "mute"})
+}
+
+func TestKeys(t *testing.T) {
+	require.Equal(t, Key{ObjectType: ObjectDomain, ObjectName: "example.com", AccreditationName: "opensrs-uniregistry"}, Domain("Example.COM", "opensrs-uniregistry"))
+	require.Equal(t, Key{ObjectType: ObjectHost, ObjectName: "ns1.example.com", AccreditationName: "opensrs-uniregistry"}, Host("NS1.example.com", "opensrs-uniregistry"))
+	require.Equal(t, Key{ObjectType: ObjectDomain, ObjectName: "xn--bcher-kva.example", AccreditationName: "opensrs-uniregistry"}, Domain("Bücher.example", "opensrs-uniregistry"))
+	require.Equal(t, Domain("xn--bcher-kva.example", "opensrs-uniregistry"), Domain("bücher.example", "opensrs-uniregistry"))
+	require.Equal(t, Key{ObjectType: ObjectHost, ObjectName: "ns1.xn--bcher-kva.example", AccreditationName: "opensrs-uniregistry"}, Host("ns1.bücher.example", "opensrs-uniregistry"))
+	require.Equal(t, Key{ObjectType: ObjectContact, ObjectName: "tdp-ABC", AccreditationName: "opensrs-uniregistry"}, Contact("tdp-ABC", "opensrs-uniregistry"))
+}
+
+func TestDeferJob_Acquired(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+
+	db.On("AcquireObjectLease", ctx, ObjectDomain, "example.com", "opensrs-uniregistry", "job1", DefaultTTL).Return(time.Duration(0), nil)
+
+	deferred, err := DeferJob(ctx, db, job, Domain("example.com", "opensrs-uniregistry"), log.CreateChildLogger())
+	require.NoError(t, err)
+	require.False(t, deferred)
+	require.Nil(t, job.StartDate)
+
+	db.AssertNotCalled(t, "UpdateJob", mock.Anything, mock.Anything)
+}
+
+func TestDeferJob_Held(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	tx := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+
+	tx.On("AcquireObjectLease", ctx, ObjectDomain, "example.com", "opensrs-uniregistry", "job1", DefaultTTL).Return(5*time.Minute, nil)
+	tx.On("UpdateJob", ctx, job).Return(nil)
+
+	before := time.Now()
+	deferred, err := DeferJob(ctx, tx, job, Domain("example.com", "opensrs-uniregistry"), log.CreateChildLogger())
+	require.NoError(t, err)
+	require.True(t, deferred)
+
+	// the wait is capped so the job comes back soon after the holder is done
+	require.NotNil(t, job.StartDate)
+	require.False(t, job.StartDate.Before(before.Add(MaxDeferWait)))
+	require.False(t, job.StartDate.After(time.Now().Add(2*MaxDeferWait)))
+
+	tx.AssertExpectations(t)
+}
+
+func TestDeferJob_AcquireFailed(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+
+	db.On("AcquireObjectLease", ctx, ObjectHost, "ns1.example.com", "opensrs-uniregistry", "job1", DefaultTTL).Return(time.Duration(0), errors.New("connection reset"))
+
+	deferred, err := DeferJob(ctx, db, job, Host("ns1.example.com", "opensrs-uniregistry"), log.CreateChildLogger())
+	require.NoError(t, err)
+	require.False(t, deferred)
+}
+
+func TestDeferJob_UpdateFailed(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+	updateErr := errors.New("could not obtain lock")
+
+	db.On("AcquireObjectLease", ctx, ObjectContact, "tdp-contact", "opensrs-uniregistry", "job1", DefaultTTL).Return(time.Second, nil)
+	db.On("UpdateJob", ctx, job).Return(updateErr)
+
+	deferred, err := DeferJob(ctx, db, job, Contact("tdp-contact", "opensrs-uniregistry"), log.CreateChildLogger())
+	require.ErrorIs(t, err, updateErr)
+	require.False(t, deferred)
+}
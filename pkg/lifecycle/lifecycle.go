@@ -0,0 +1,208 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alexliesenfeld/health"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/sqs"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// ErrDraining is returned for messages held while the worker drains once the consumer stops, they are redelivered
+var ErrDraining = errors.New("worker is shutting down")
+
+// StopTimeout bounds how long Run waits for the consumer to return once stopped
+const StopTimeout = 10 * time.Second
+
+// Lifecycle tracks in-flight message handlers of a worker so they are drained on shutdown
+// instead of being killed halfway through a transaction or after a registry send.
+type Lifecycle struct {
+	drainTimeout time.Duration
+
+	mu       sync.RWMutex
+	draining bool
+	wg       sync.WaitGroup
+	inFlight atomic.Int64
+
+	released    chan struct{}
+	releaseOnce sync.Once
+}
+
+// New creates a Lifecycle waiting up to drainTimeout for in-flight handlers on shutdown
+func New(drainTimeout time.Duration) *Lifecycle {
+	return &Lifecycle{
+		drainTimeout: drainTimeout,
+		released:     make(chan struct{}),
+	}
+}
+
+// Bus returns bus tracking every handler registered on it
+func (l *Lifecycle) Bus(bus messagebus.MessageBus) messagebus.MessageBus {
+	return &trackingBus{MessageBus: bus, l: l}
+}
+
+// Consumer returns consumer tracking every handler registered on it
+func (l *Lifecycle) Consumer(consumer sqs.Consumer) sqs.Consumer {
+	return &trackingConsumer{Consumer: consumer, l: l}
+}
+
+// HealthCheck reports the worker down while it drains so no new traffic is routed to it
+func (l *Lifecycle) HealthCheck() health.Check {
+	return health.Check{
+		Name: "Lifecycle",
+		Check: func(ctx context.Context) error {
+			if l.Draining() {
+				return ErrDraining
+			}
+
+			return nil
+		},
+	}
+}
+
+// Draining tells if the worker is shutting down
+func (l *Lifecycle) Draining() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.draining
+}
+
+// Run runs consume until it returns or a termination signal is received. On signal the consumer is cancelled
+// so it stops receiving messages, the in-flight handlers are drained, then stop is called for consume to return.
+// cancel is nil for consumers which cannot stop receiving on their own, the messages delivered while draining
+// are then held unacknowledged until stop so the broker does not redeliver them to this worker.
+//
+// Run returns the error of consume when it stopped on its own. The caller logs it and returns so tracing and
+// logs are flushed by its deferred calls.
+func (l *Lifecycle) Run(consume func() error, cancel func(), stop func()) (err error) {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consume()
+	}()
+
+	select {
+	case err = <-errCh:
+		stop()
+		return
+	case sig := <-signalCh:
+		log.Info("Received termination signal, draining in-flight handlers", log.Fields{
+			"signal":    sig.String(),
+			"in_flight": l.inFlight.Load(),
+			"deadline":  l.drainTimeout.String(),
+		})
+	}
+
+	if cancel != nil {
+		cancel()
+	}
+
+	l.Drain()
+
+	// held messages are rejected right before the consumer stops
+	l.release()
+	stop()
+
+	select {
+	case err = <-errCh:
+		if err != nil {
+			log.Debug("Consumer returned an error once stopped", log.Fields{
+				types.LogFieldKeys.Error: err,
+			})
+		}
+	case <-time.After(StopTimeout):
+		log.Warn("Consumer did not stop in time")
+	}
+
+	return nil
+}
+
+// Drain stops accepting messages and waits for in-flight handlers until the drain timeout.
+// It returns false when handlers were still running at the deadline.
+func (l *Lifecycle) Drain() bool {
+	l.mu.Lock()
+	l.draining = true
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info("In-flight handlers drained")
+		return true
+	case <-time.After(l.drainTimeout):
+		log.Warn("Drain deadline reached with handlers still in flight", log.Fields{
+			"in_flight": l.inFlight.Load(),
+		})
+		return false
+	}
+}
+
+// release rejects the messages held while draining
+func (l *Lifecycle) release() {
+	l.releaseOnce.Do(func() {
+		close(l.released)
+	})
+}
+
+// track runs handle unless the worker is draining, messages received while draining are held until release
+func (l *Lifecycle) track(handle func() error) error {
+	l.mu.RLock()
+	if l.draining {
+		l.mu.RUnlock()
+		log.Debug("Message received while draining, holding it until the consumer stops")
+		<-l.released
+		return ErrDraining
+	}
+	l.wg.Add(1)
+	l.mu.RUnlock()
+
+	l.inFlight.Add(1)
+	defer func() {
+		l.inFlight.Add(-1)
+		l.wg.Done()
+	}()
+
+	return handle()
+}
+
+type trackingBus struct {
+	messagebus.MessageBus
+	l *Lifecycle
+}
+
+func (b *trackingBus) Register(m proto.Message, h messagebus.HandlerFuncType) {
+	b.MessageBus.Register(m, func(server messagebus.Server, message proto.Message) error {
+		return b.l.track(func() error { return h(server, message) })
+	})
+}
+
+type trackingConsumer struct {
+	sqs.Consumer
+	l *Lifecycle
+}
+
+func (c *trackingConsumer) Register(m proto.Message, h sqs.HandlerFuncType) {
+	c.Consumer.Register(m, func(server sqs.Server, message proto.Message) error {
+		return c.l.track(func() error { return h(server, message) })
+	})
+}
@@ -0,0 +1,136 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+)
+
+func setup() {
+	log.Setup(config.Config{LogLevel: "mute"})
+}
+
+func TestDrainWaitsForInFlightHandlers(t *testing.T) {
+	setup()
+	l := New(time.Second)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go l.track(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	drained := make(chan bool)
+	go func() {
+		drained <- l.Drain()
+	}()
+
+	require.Eventually(t, l.Draining, time.Second, time.Millisecond)
+
+	// messages received while draining are held until release
+	held := make(chan error)
+	go func() {
+		held <- l.track(func() error {
+			t.Error("handler must not be called while draining")
+			return nil
+		})
+	}()
+
+	close(release)
+	require.True(t, <-drained)
+
+	select {
+	case <-held:
+		t.Fatal("message must be held until release")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	l.release()
+	require.ErrorIs(t, <-held, ErrDraining)
+}
+
+func TestRunOnSignal(t *testing.T) {
+	setup()
+	l := New(time.Second)
+
+	var calls []string
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+
+	consume := func() error {
+		close(started)
+		<-stopped
+		return errors.New("connection closed")
+	}
+	cancel := func() {
+		calls = append(calls, "cancel")
+	}
+	stop := func() {
+		require.True(t, l.Draining())
+		calls = append(calls, "stop")
+		close(stopped)
+	}
+
+	go func() {
+		<-started
+		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+	}()
+
+	// the consumer error following the requested stop is not reported
+	require.NoError(t, l.Run(consume, cancel, stop))
+	require.Equal(t, []string{"cancel", "stop"}, calls)
+}
+
+func TestRunConsumeError(t *testing.T) {
+	setup()
+	l := New(time.Second)
+
+	stopped := false
+	err := l.Run(func() error {
+		return errors.New("connection closed")
+	}, nil, func() {
+		stopped = true
+	})
+
+	require.EqualError(t, err, "connection closed")
+	require.True(t, stopped)
+	require.False(t, l.Draining())
+}
+
+func TestDrainDeadline(t *testing.T) {
+	setup()
+	l := New(10 * time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	go l.track(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	require.False(t, l.Drain())
+}
+
+func TestHealthCheck(t *testing.T) {
+	setup()
+	l := New(time.Second)
+	check := l.HealthCheck()
+
+	require.NoError(t, check.Check(context.Background()))
+
+	require.True(t, l.Drain())
+	require.ErrorIs(t, check.Check(context.Background()), ErrDraining)
+}
@@ -0,0 +1,68 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tucowsinc/tdp-shared-go/logger"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+var ErrInMaintenance = errors.New("accreditation is in maintenance")
+
+// Check returns an error wrapping ErrInMaintenance if the accreditation has an active maintenance window
+func Check(ctx context.Context, db database.Database, accreditationName string) error {
+	window, err := db.GetActiveMaintenance(ctx, accreditationName)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("error getting maintenance window of accreditation %q: %w", accreditationName, err)
+	}
+
+	return fmt.Errorf("%w: %q until %s", ErrInMaintenance, accreditationName, window.EndDate.Format(time.RFC3339))
+}
+
+// HoldJob holds the job until the end of the active maintenance window of the accreditation.
+//
+// The job start date is moved to the window end and true is returned; the caller must not send
+// the command. All held jobs share the same start date and are released in the order they were created.
+// Errors while checking the window are logged and the command is allowed.
+func HoldJob(ctx context.Context, db database.Database, tx database.Database, job *model.Job, accreditationName string, logger logger.ILogger) (held bool, err error) {
+	window, err := db.GetActiveMaintenance(ctx, accreditationName)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			logger.Warn("Failed to check accreditation maintenance window, sending anyway", log.Fields{
+				types.LogFieldKeys.Error: err,
+				"accreditation":          accreditationName,
+			})
+		}
+		return false, nil
+	}
+
+	startDate := window.EndDate
+	job.StartDate = &startDate
+
+	err = tx.UpdateJob(ctx, job)
+	if err != nil {
+		logger.Error("Failed to hold job for maintenance window", log.Fields{
+			types.LogFieldKeys.Error: err,
+			"accreditation":          accreditationName,
+		})
+		return false, err
+	}
+
+	logger.Info("Accreditation is in maintenance, job held", log.Fields{
+		"accreditation":  accreditationName,
+		"maintenance_id": window.ID,
+		"start_date":     startDate,
+	})
+
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+)
+
+func setup() {
+	log.Setup(config.Config{LogLevel: "mute"})
+}
+
+func TestCheck(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+
+	db.On("GetActiveMaintenance", ctx, "opensrs-uniregistry").
+		Return(&model.AccreditationMaintenance{ID: "m1", EndDate: time.Now().Add(time.Hour)}, nil)
+	db.On("GetActiveMaintenance", ctx, "opensrs-ascio").
+		Return((*model.AccreditationMaintenance)(nil), database.ErrNotFound)
+
+	require.ErrorIs(t, Check(ctx, db, "opensrs-uniregistry"), ErrInMaintenance)
+	require.NoError(t, Check(ctx, db, "opensrs-ascio"))
+}
+
+func TestHoldJob(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	tx := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+	end := time.Now().Add(time.Hour)
+
+	db.On("GetActiveMaintenance", ctx, "opensrs-uniregistry").
+		Return(&model.AccreditationMaintenance{ID: "m1", EndDate: end}, nil)
+	tx.On("UpdateJob", ctx, job).Return(nil)
+
+	held, err := HoldJob(ctx, db, tx, job, "opensrs-uniregistry", log.CreateChildLogger())
+	require.NoError(t, err)
+	require.True(t, held)
+	require.Equal(t, end, *job.StartDate)
+
+	tx.AssertExpectations(t)
+}
+
+func TestHoldJob_NoMaintenance(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+
+	db.On("GetActiveMaintenance", ctx, "opensrs-uniregistry").
+		Return((*model.AccreditationMaintenance)(nil), database.ErrNotFound)
+
+	held, err := HoldJob(ctx, db, db, job, "opensrs-uniregistry", log.CreateChildLogger())
+	require.NoError(t, err)
+	require.False(t, held)
+	require.Nil(t, job.StartDate)
+
+	db.AssertNotCalled(t, "UpdateJob", mock.Anything, mock.Anything)
+}
+
+func TestHoldJob_CheckFailed(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+
+	db.On("GetActiveMaintenance", ctx, "opensrs-uniregistry").
+		Return((*model.AccreditationMaintenance)(nil), errors.New("connection reset"))
+
+	held, err := HoldJob(ctx, db, db, job, "opensrs-uniregistry", log.CreateChildLogger())
+	require.NoError(t, err)
+	require.False(t, held)
+}
@@ -2,12 +2,15 @@ package messagebus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"google.golang.org/protobuf/proto"
 
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
 	messagebus "github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
@@ -68,9 +71,15 @@ func Call(ctx context.Context, bus messagebus.MessageBus, queue string, msg prot
 		defer cancel()
 	}
 
+	start := time.Now()
+
 	response, err := bus.Call(rpcCtx, queue, msg, nil)
+	metrics.MessageBusCallDuration.WithLabelValues(queue).Observe(time.Since(start).Seconds())
 	if err != nil {
-		return nil, fmt.Errorf("error sending message: %w", err)
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(rpcCtx.Err(), context.DeadlineExceeded) {
+			metrics.MessageBusCallTimeouts.WithLabelValues(queue).Inc()
+		}
+		return nil, joberrors.Transient(fmt.Errorf("error sending message: %w", err))
 	}
 
 	if errResp, ok := response.Message.(*tcwire.ErrorResponse); ok {
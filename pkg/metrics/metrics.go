@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tdp_workers"
+
+var (
+	// JobsHandled counts jobs set to a final status by job type and status
+	JobsHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_handled_total",
+		Help:      "Jobs set to a final status by the workers.",
+	}, []string{"job_type", "status"})
+
+	// JobDuration observes the time from job start date to its final status
+	JobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Time from job start to its final status.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 3600, 86400},
+	}, []string{"job_type", "status"})
+
+	// JobsRescheduled counts jobs resubmitted after a transient error by job type
+	JobsRescheduled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_rescheduled_total",
+		Help:      "Jobs resubmitted after a transient error.",
+	}, []string{"job_type"})
+
+	// EppResponses counts registry EPP response codes by accreditation
+	EppResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registry_epp_responses_total",
+		Help:      "Registry EPP response codes received by accreditation.",
+	}, []string{"accreditation", "code"})
+
+	// MessageBusCallDuration observes message bus RPC call latency by queue
+	MessageBusCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "message_bus_call_duration_seconds",
+		Help:      "Message bus RPC call latency.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"queue"})
+
+	// MessageBusCallTimeouts counts message bus RPC calls which timed out by queue
+	MessageBusCallTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "message_bus_call_timeouts_total",
+		Help:      "Message bus RPC calls which timed out.",
+	}, []string{"queue"})
+
+	// SQSMessages counts SQS messages received and deleted by queue
+	SQSMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sqs_messages_total",
+		Help:      "SQS messages received and deleted.",
+	}, []string{"queue", "operation"})
+
+	// SQSFailures counts failed SQS operations by queue
+	SQSFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sqs_failures_total",
+		Help:      "Failed SQS operations.",
+	}, []string{"queue", "operation"})
+
+	// HandlerPanics counts message handlers recovered from a panic by message type
+	HandlerPanics = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_panics_total",
+		Help:      "Message handlers recovered from a panic.",
+	}, []string{"message_type"})
+
+	// EnqueuerBatchSize observes the number of rows published per enqueuer batch by queue
+	EnqueuerBatchSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "enqueuer_batch_size",
+		Help:      "Rows published per enqueuer batch.",
+		Buckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000},
+	}, []string{"queue"})
+)
+
+// SQS operation label values
+const (
+	SQSReceive = "receive"
+	SQSDelete  = "delete"
+	SQSDecode  = "decode"
+	SQSSend    = "send"
+)
+
+func init() {
+	prometheus.MustRegister(
+		JobsHandled,
+		JobDuration,
+		JobsRescheduled,
+		EppResponses,
+		MessageBusCallDuration,
+		MessageBusCallTimeouts,
+		SQSMessages,
+		SQSFailures,
+		HandlerPanics,
+		EnqueuerBatchSize,
+	)
+}
+
+// Handler returns the http handler exposing the metrics in the Prometheus format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
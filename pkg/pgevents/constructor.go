@@ -6,7 +6,7 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
-func New(connString string) (Listener, error) {
+func New(connString string, opts ...OptionsFunc) (Listener, error) {
 	conf, err := pgx.ParseConfig(connString)
 	if err != nil {
 		return nil, fmt.Errorf("unable to convert conn string [%v] to pgx ParseConfig: %w", connString, err)
@@ -17,6 +17,10 @@ func New(connString string) (Listener, error) {
 		},
 	}
 
+	for _, opt := range opts {
+		opt(listener)
+	}
+
 	return listener, nil
 
 }
@@ -39,3 +39,8 @@ func (m *MockListener) handleNotification(notification *pgconn.Notification) err
 	args := m.Called(notification)
 	return args.Error(0)
 }
+
+func (m *MockListener) Stats() Stats {
+	args := m.Called()
+	return args.Get(0).(Stats)
+}
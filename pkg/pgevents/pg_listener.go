@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
@@ -15,15 +15,40 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
 
+const (
+	DefaultMinReconnectDelay = time.Second
+	DefaultMaxReconnectDelay = time.Minute
+)
+
 type Listener interface {
 	Close(ctx context.Context)
 	RegisterHandler(channel string, handler Handler)
 	StartListening(ctx context.Context) error
+	Stats() Stats
 }
 
 type PGListener struct {
 	Connect  func(ctx context.Context) (*pgx.Conn, error)
 	handlers map[string]Handler
+
+	// MessageTimeout is the time after which incomplete chunked messages are dropped
+	MessageTimeout time.Duration
+	// MaxMessageSize is the max size in bytes of a reassembled message
+	MaxMessageSize int
+
+	// MinReconnectDelay and MaxReconnectDelay bound the exponential backoff between reconnects
+	MinReconnectDelay time.Duration
+	MaxReconnectDelay time.Duration
+
+	// OnConnect is called every time the listener (re)starts listening on its channels,
+	// notifications sent while it was disconnected are lost and can be replayed from here
+	OnConnect func(ctx context.Context)
+
+	assembler     *reassembler
+	assemblerOnce sync.Once
+
+	// reconnect attempts since the last successful connection
+	attempts int
 }
 
 func (l *PGListener) RegisterHandler(channel string, handler Handler) {
@@ -43,7 +68,7 @@ func (l *PGListener) StartListening(ctx context.Context) error {
 		return fmt.Errorf("listen: No handlers")
 	}
 
-	// if connecting to the database fails it will wait a while and try to reconnect.
+	// if connecting to the database fails it will back off and try to reconnect.
 	for {
 		err := l._connect(ctx)
 		if err != nil {
@@ -52,15 +77,52 @@ func (l *PGListener) StartListening(ctx context.Context) error {
 			})
 		}
 
+		delay := l.reconnectDelay()
+		l.attempts++
+
+		log.Info("Reconnecting listener", log.Fields{
+			"attempt": l.attempts,
+			"delay":   delay.String(),
+		})
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		default:
-			time.Sleep(time.Minute)
+		case <-time.After(delay):
 		}
 	}
 }
 
+// reconnectDelay returns exponential backoff delay with jitter for the current reconnect attempt
+func (l *PGListener) reconnectDelay() time.Duration {
+	minDelay, maxDelay := l.MinReconnectDelay, l.MaxReconnectDelay
+	if minDelay <= 0 {
+		minDelay = DefaultMinReconnectDelay
+	}
+
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxReconnectDelay
+	}
+
+	if maxDelay < minDelay {
+		maxDelay = minDelay
+	}
+
+	delay := minDelay
+	for i := 0; i < l.attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	// equal jitter: keep half of the delay and randomize the other half
+	half := delay / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
 func (l *PGListener) _connect(ctx context.Context) error {
 	conn, err := l.Connect(ctx)
 	if err != nil {
@@ -75,6 +137,13 @@ func (l *PGListener) _connect(ctx context.Context) error {
 		}
 	}
 
+	// connection is healthy again, reset backoff
+	l.attempts = 0
+
+	if l.OnConnect != nil {
+		go l.OnConnect(ctx)
+	}
+
 	err = l.listen(ctx, conn)
 	if err != nil {
 		return err
@@ -86,42 +155,39 @@ func (l *PGListener) _connect(ctx context.Context) error {
 
 func (l *PGListener) listen(ctx context.Context, conn *pgx.Conn) error {
 
-	data := notificationData{}
+	assembler := l.reassembler()
 
 	for {
-		notification, err := conn.WaitForNotification(ctx)
-		if err != nil {
-			return fmt.Errorf("waiting for notification: %w", err)
-		}
+		// wake up periodically to drop incomplete messages even if no new notifications arrive
+		waitCtx, cancel := context.WithTimeout(ctx, assembler.timeout)
+		notification, err := conn.WaitForNotification(waitCtx)
+		cancel()
 
-		payload := notification.Payload
-		parts := strings.SplitN(payload, ":", 3)
-		data.receivedParts, err = strconv.Atoi(parts[0])
 		if err != nil {
-			log.Error("invalid 'part' received")
-			data.reset()
-			continue
+			if ctx.Err() == nil && waitCtx.Err() != nil {
+				l.expire(assembler)
+				continue
+			}
+
+			return fmt.Errorf("waiting for notification: %w", err)
 		}
 
-		data.totalParts, err = strconv.Atoi(parts[1])
+		payload, complete, err := assembler.add(notification.Channel, notification.Payload, time.Now())
 		if err != nil {
-			log.Error("invalid 'total_parts' received")
-			data.reset()
-			continue
+			log.Warn("dropping notification fragment", log.Fields{
+				"channel":                notification.Channel,
+				types.LogFieldKeys.Error: err.Error(),
+			})
 		}
 
-		if data.receivedParts <= data.totalParts {
-			data.fullPayload += parts[2]
+		l.expire(assembler)
 
-			if data.receivedParts < data.totalParts {
-				continue
-			}
+		if !complete {
+			continue
 		}
 
-		log.Debug("Payload:", log.Fields{"payload": data.fullPayload})
-		notification.Payload = data.fullPayload
-
-		data.reset()
+		log.Debug("Payload:", log.Fields{"payload": payload})
+		notification.Payload = payload
 
 		err = l.handleNotification(notification)
 		if err != nil {
@@ -134,6 +200,31 @@ func (l *PGListener) listen(ctx context.Context, conn *pgx.Conn) error {
 	}
 
 }
+
+// expire drops incomplete messages that were not completed in time
+func (l *PGListener) expire(assembler *reassembler) {
+	if expired := assembler.expire(time.Now()); expired > 0 {
+		log.Warn("dropped incomplete notification messages", log.Fields{
+			"expired": expired,
+			"timeout": assembler.timeout.String(),
+		})
+	}
+}
+
+// reassembler returns the listener reassembler, creating it on first use
+func (l *PGListener) reassembler() *reassembler {
+	l.assemblerOnce.Do(func() {
+		l.assembler = newReassembler(l.MessageTimeout, l.MaxMessageSize)
+	})
+
+	return l.assembler
+}
+
+// Stats returns counters of received, completed and dropped notification fragments
+func (l *PGListener) Stats() Stats {
+	return l.reassembler().stats()
+}
+
 func (l *PGListener) handleNotification(notification *pgconn.Notification) error {
 	meta := new(Notification)
 
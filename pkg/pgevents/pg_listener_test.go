@@ -401,3 +401,35 @@ func TestPGListenerStartListening_Constructor(t *testing.T) {
 	}
 
 }
+
+func TestPGListenerReconnectDelay(t *testing.T) {
+	listener := &PGListener{
+		MinReconnectDelay: time.Second,
+		MaxReconnectDelay: 10 * time.Second,
+	}
+
+	expected := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+
+	for attempt, max := range expected {
+		listener.attempts = attempt
+
+		delay := listener.reconnectDelay()
+		require.GreaterOrEqual(t, delay, max/2)
+		require.LessOrEqual(t, delay, max)
+	}
+}
+
+func TestPGListenerReconnectDelay_Defaults(t *testing.T) {
+	listener := &PGListener{attempts: 100}
+
+	delay := listener.reconnectDelay()
+	require.GreaterOrEqual(t, delay, DefaultMaxReconnectDelay/2)
+	require.LessOrEqual(t, delay, DefaultMaxReconnectDelay)
+}
@@ -0,0 +1,224 @@
+package pgevents
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultMessageTimeout = 30 * time.Second
+	DefaultMaxMessageSize = 1024 * 1024 // 1MB
+)
+
+var (
+	ErrInvalidFragment   = errors.New("invalid notification fragment")
+	ErrDuplicateFragment = errors.New("duplicate notification fragment")
+	ErrMessageTooLarge   = errors.New("notification message exceeds max size")
+)
+
+// fragment is a single part of a chunked notify_event payload
+//
+// Payloads are sent in the "<id>:<part>:<total>:<chunk>" format. The legacy
+// "<part>:<total>:<chunk>" format (without message id) is still accepted.
+type fragment struct {
+	messageID string
+	part      int
+	total     int
+	chunk     string
+}
+
+// parseFragment parses raw notification payload into a fragment
+func parseFragment(payload string) (f fragment, err error) {
+	parts := strings.SplitN(payload, ":", 4)
+	if len(parts) < 3 {
+		return f, fmt.Errorf("%w: expected at least 3 parts, got %d", ErrInvalidFragment, len(parts))
+	}
+
+	// legacy format does not include message id and starts with the part number
+	if _, err := strconv.Atoi(parts[0]); err == nil {
+		parts = strings.SplitN(payload, ":", 3)
+		parts = append([]string{""}, parts...)
+	} else if len(parts) < 4 || parts[0] == "" {
+		return f, fmt.Errorf("%w: missing message id", ErrInvalidFragment)
+	}
+
+	f.messageID = parts[0]
+
+	f.part, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return f, fmt.Errorf("%w: invalid 'part' received", ErrInvalidFragment)
+	}
+
+	f.total, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return f, fmt.Errorf("%w: invalid 'total_parts' received", ErrInvalidFragment)
+	}
+
+	if f.part < 1 || f.total < 1 || f.part > f.total {
+		return f, fmt.Errorf("%w: part %d out of range of %d", ErrInvalidFragment, f.part, f.total)
+	}
+
+	f.chunk = parts[3]
+
+	return
+}
+
+// pendingMessage holds the fragments received so far for a single message
+type pendingMessage struct {
+	chunks    []string
+	seen      []bool
+	received  int
+	size      int
+	firstSeen time.Time
+}
+
+func (m *pendingMessage) payload() string {
+	return strings.Join(m.chunks, "")
+}
+
+// Stats contains counters of the notification reassembly
+type Stats struct {
+	ReceivedFragments uint64
+	CompletedMessages uint64
+	DroppedFragments  uint64
+	InvalidFragments  uint64
+	ExpiredMessages   uint64
+	OversizedMessages uint64
+}
+
+// reassembler rebuilds chunked notify_event payloads keyed by channel and message id,
+// so fragments of concurrently sent messages never get mixed together.
+// It is not safe for concurrent use; counters however can be read at any time.
+type reassembler struct {
+	timeout time.Duration
+	maxSize int
+	pending map[string]*pendingMessage
+
+	receivedFragments atomic.Uint64
+	completedMessages atomic.Uint64
+	droppedFragments  atomic.Uint64
+	invalidFragments  atomic.Uint64
+	expiredMessages   atomic.Uint64
+	oversizedMessages atomic.Uint64
+}
+
+func newReassembler(timeout time.Duration, maxSize int) *reassembler {
+	if timeout <= 0 {
+		timeout = DefaultMessageTimeout
+	}
+
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+
+	return &reassembler{
+		timeout: timeout,
+		maxSize: maxSize,
+		pending: make(map[string]*pendingMessage),
+	}
+}
+
+func messageKey(channel string, messageID string) string {
+	return channel + ":" + messageID
+}
+
+// add adds raw notification payload received on channel.
+// It returns the full payload and true once all fragments of the message are received.
+func (r *reassembler) add(channel string, payload string, now time.Time) (string, bool, error) {
+	r.receivedFragments.Add(1)
+
+	f, err := parseFragment(payload)
+	if err != nil {
+		r.invalidFragments.Add(1)
+		r.droppedFragments.Add(1)
+		return "", false, err
+	}
+
+	key := messageKey(channel, f.messageID)
+	msg, ok := r.pending[key]
+
+	// legacy messages have no id, so a new first part means previous message never completed
+	if ok && f.messageID == "" && f.part == 1 {
+		r.drop(key, msg)
+		ok = false
+	}
+
+	if ok && len(msg.chunks) != f.total {
+		r.drop(key, msg)
+		r.invalidFragments.Add(1)
+		r.droppedFragments.Add(1)
+		return "", false, fmt.Errorf("%w: total parts changed from %d to %d", ErrInvalidFragment, len(msg.chunks), f.total)
+	}
+
+	if !ok {
+		msg = &pendingMessage{
+			chunks:    make([]string, f.total),
+			seen:      make([]bool, f.total),
+			firstSeen: now,
+		}
+		r.pending[key] = msg
+	}
+
+	if msg.seen[f.part-1] {
+		r.droppedFragments.Add(1)
+		return "", false, fmt.Errorf("%w: part %d of message %q", ErrDuplicateFragment, f.part, f.messageID)
+	}
+
+	msg.size += len(f.chunk)
+	if msg.size > r.maxSize {
+		r.droppedFragments.Add(1)
+		r.drop(key, msg)
+		r.oversizedMessages.Add(1)
+		return "", false, fmt.Errorf("%w: %d > %d bytes", ErrMessageTooLarge, msg.size, r.maxSize)
+	}
+
+	msg.chunks[f.part-1] = f.chunk
+	msg.seen[f.part-1] = true
+	msg.received++
+
+	if msg.received < f.total {
+		return "", false, nil
+	}
+
+	delete(r.pending, key)
+	r.completedMessages.Add(1)
+
+	return msg.payload(), true, nil
+}
+
+// expire drops all incomplete messages which were first seen more than timeout ago.
+// It returns the number of messages dropped.
+func (r *reassembler) expire(now time.Time) (expired int) {
+	for key, msg := range r.pending {
+		if now.Sub(msg.firstSeen) < r.timeout {
+			continue
+		}
+
+		r.drop(key, msg)
+		r.expiredMessages.Add(1)
+		expired++
+	}
+
+	return
+}
+
+// drop removes pending message and accounts all its received fragments as dropped
+func (r *reassembler) drop(key string, msg *pendingMessage) {
+	delete(r.pending, key)
+	r.droppedFragments.Add(uint64(msg.received))
+}
+
+func (r *reassembler) stats() Stats {
+	return Stats{
+		ReceivedFragments: r.receivedFragments.Load(),
+		CompletedMessages: r.completedMessages.Load(),
+		DroppedFragments:  r.droppedFragments.Load(),
+		InvalidFragments:  r.invalidFragments.Load(),
+		ExpiredMessages:   r.expiredMessages.Load(),
+		OversizedMessages: r.oversizedMessages.Load(),
+	}
+}
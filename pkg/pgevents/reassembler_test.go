@@ -0,0 +1,186 @@
+package pgevents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFragment(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    fragment
+		wantErr bool
+	}{
+		{
+			name:    "with message id",
+			payload: "6d0c5d2e-4c1a-4fd4-8d4c-1a9e3f1d6f0b:1:2:{\"id\":\"1\"",
+			want:    fragment{messageID: "6d0c5d2e-4c1a-4fd4-8d4c-1a9e3f1d6f0b", part: 1, total: 2, chunk: "{\"id\":\"1\""},
+		},
+		{
+			name:    "legacy format",
+			payload: "2:2:\"type\":\"x\"}",
+			want:    fragment{part: 2, total: 2, chunk: "\"type\":\"x\"}"},
+		},
+		{
+			name:    "missing message id",
+			payload: ":1:1:invalid-message",
+			wantErr: true,
+		},
+		{
+			name:    "missing total",
+			payload: "0::invalid-message",
+			wantErr: true,
+		},
+		{
+			name:    "part out of range",
+			payload: "id:3:2:chunk",
+			wantErr: true,
+		},
+		{
+			name:    "not enough parts",
+			payload: "payload",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFragment(tt.payload)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidFragment)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReassemblerInterleavedMessages(t *testing.T) {
+	r := newReassembler(time.Minute, 0)
+	now := time.Now()
+
+	payload, complete, err := r.add("channel", "a:1:2:hello ", now)
+	require.NoError(t, err)
+	require.False(t, complete)
+	require.Empty(t, payload)
+
+	_, complete, err = r.add("channel", "b:1:2:foo ", now)
+	require.NoError(t, err)
+	require.False(t, complete)
+
+	payload, complete, err = r.add("channel", "b:2:2:bar", now)
+	require.NoError(t, err)
+	require.True(t, complete)
+	require.Equal(t, "foo bar", payload)
+
+	payload, complete, err = r.add("channel", "a:2:2:world", now)
+	require.NoError(t, err)
+	require.True(t, complete)
+	require.Equal(t, "hello world", payload)
+
+	require.Equal(t, Stats{ReceivedFragments: 4, CompletedMessages: 2}, r.stats())
+}
+
+func TestReassemblerSameIdDifferentChannels(t *testing.T) {
+	r := newReassembler(time.Minute, 0)
+	now := time.Now()
+
+	_, _, err := r.add("channel1", "a:1:2:one", now)
+	require.NoError(t, err)
+
+	_, complete, err := r.add("channel2", "a:2:2:two", now)
+	require.NoError(t, err)
+	require.False(t, complete)
+
+	require.Len(t, r.pending, 2)
+}
+
+func TestReassemblerOutOfOrder(t *testing.T) {
+	r := newReassembler(time.Minute, 0)
+	now := time.Now()
+
+	_, complete, err := r.add("channel", "a:2:2:world", now)
+	require.NoError(t, err)
+	require.False(t, complete)
+
+	payload, complete, err := r.add("channel", "a:1:2:hello ", now)
+	require.NoError(t, err)
+	require.True(t, complete)
+	require.Equal(t, "hello world", payload)
+}
+
+func TestReassemblerDuplicateFragment(t *testing.T) {
+	r := newReassembler(time.Minute, 0)
+	now := time.Now()
+
+	_, _, err := r.add("channel", "a:1:2:hello ", now)
+	require.NoError(t, err)
+
+	_, complete, err := r.add("channel", "a:1:2:hello ", now)
+	require.ErrorIs(t, err, ErrDuplicateFragment)
+	require.False(t, complete)
+
+	require.Equal(t, uint64(1), r.stats().DroppedFragments)
+}
+
+func TestReassemblerLegacyRestart(t *testing.T) {
+	r := newReassembler(time.Minute, 0)
+	now := time.Now()
+
+	_, _, err := r.add("channel", "1:3:lost", now)
+	require.NoError(t, err)
+
+	_, _, err = r.add("channel", "1:2:hello ", now)
+	require.NoError(t, err)
+
+	payload, complete, err := r.add("channel", "2:2:world", now)
+	require.NoError(t, err)
+	require.True(t, complete)
+	require.Equal(t, "hello world", payload)
+
+	require.Equal(t, uint64(1), r.stats().DroppedFragments)
+}
+
+func TestReassemblerMaxSize(t *testing.T) {
+	r := newReassembler(time.Minute, 10)
+	now := time.Now()
+
+	_, _, err := r.add("channel", "a:1:2:123456", now)
+	require.NoError(t, err)
+
+	_, complete, err := r.add("channel", "a:2:2:7890123", now)
+	require.ErrorIs(t, err, ErrMessageTooLarge)
+	require.False(t, complete)
+	require.Empty(t, r.pending)
+
+	stats := r.stats()
+	require.Equal(t, uint64(2), stats.DroppedFragments)
+	require.Equal(t, uint64(1), stats.OversizedMessages)
+}
+
+func TestReassemblerExpire(t *testing.T) {
+	r := newReassembler(time.Second, 0)
+	now := time.Now()
+
+	_, _, err := r.add("channel", "a:1:3:one", now)
+	require.NoError(t, err)
+
+	_, _, err = r.add("channel", "a:2:3:two", now)
+	require.NoError(t, err)
+
+	_, _, err = r.add("channel", "b:1:2:one", now.Add(time.Second))
+	require.NoError(t, err)
+
+	require.Equal(t, 0, r.expire(now.Add(500*time.Millisecond)))
+	require.Equal(t, 1, r.expire(now.Add(time.Second)))
+	require.Len(t, r.pending, 1)
+
+	stats := r.stats()
+	require.Equal(t, uint64(2), stats.DroppedFragments)
+	require.Equal(t, uint64(1), stats.ExpiredMessages)
+}
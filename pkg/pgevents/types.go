@@ -1,10 +1,9 @@
 package pgevents
 
-type notificationData struct {
-	receivedParts int
-	totalParts    int
-	fullPayload   string
-}
+import (
+	"context"
+	"time"
+)
 
 type Notification struct {
 	ID      string `json:"id"`
@@ -12,12 +11,6 @@ type Notification struct {
 	Payload string `json:"payload"`
 }
 
-func (nd *notificationData) reset() {
-	nd.receivedParts = 0
-	nd.totalParts = 0
-	nd.fullPayload = ""
-}
-
 type Handler interface {
 	HandleNotification(notification *Notification) error
 }
@@ -27,3 +20,35 @@ type HandlerFunc func(notification *Notification) error
 func (f HandlerFunc) HandleNotification(notification *Notification) error {
 	return f(notification)
 }
+
+// OptionsFunc configures the PGListener
+type OptionsFunc func(l *PGListener)
+
+// WithMessageTimeout sets the time after which incomplete chunked messages are dropped
+func WithMessageTimeout(timeout time.Duration) OptionsFunc {
+	return func(l *PGListener) {
+		l.MessageTimeout = timeout
+	}
+}
+
+// WithMaxMessageSize sets the max size in bytes of a reassembled message
+func WithMaxMessageSize(size int) OptionsFunc {
+	return func(l *PGListener) {
+		l.MaxMessageSize = size
+	}
+}
+
+// WithReconnectBackoff sets the bounds of the exponential backoff between reconnects
+func WithReconnectBackoff(minDelay time.Duration, maxDelay time.Duration) OptionsFunc {
+	return func(l *PGListener) {
+		l.MinReconnectDelay = minDelay
+		l.MaxReconnectDelay = maxDelay
+	}
+}
+
+// WithOnConnect sets the function called every time the listener (re)starts listening
+func WithOnConnect(f func(ctx context.Context)) OptionsFunc {
+	return func(l *PGListener) {
+		l.OnConnect = f
+	}
+}
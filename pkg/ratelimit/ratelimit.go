@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/tucowsinc/tdp-shared-go/logger"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/types"
+)
+
+// DeferJob takes a token from the accreditation outbound command bucket before a registry command is sent.
+//
+// When the bucket is empty the job start date is moved forward to when a token is expected to be
+// available and true is returned; the caller must not send the command, the job is dispatched again
+// at its new start date. A jitter of up to the wait time is added so deferred jobs do not all come
+// back at once.
+//
+// The bucket is read outside of tx, so the bucket row is not locked for the duration of the job
+// transaction. Errors while checking the limit are logged and the command is allowed.
+func DeferJob(ctx context.Context, db database.Database, tx database.Database, job *model.Job, accreditationName string, logger logger.ILogger) (deferred bool, err error) {
+	wait, err := db.AcquireRateLimitToken(ctx, accreditationName)
+	if err != nil {
+		logger.Warn("Failed to check accreditation rate limit, sending without limit", log.Fields{
+			types.LogFieldKeys.Error: err,
+			"accreditation":          accreditationName,
+		})
+		return false, nil
+	}
+
+	if wait <= 0 {
+		return false, nil
+	}
+
+	startDate := time.Now().Add(wait + time.Duration(rand.Int63n(int64(wait)+1)))
+	job.StartDate = &startDate
+
+	err = tx.UpdateJob(ctx, job)
+	if err != nil {
+		logger.Error("Failed to defer rate limited job", log.Fields{
+			types.LogFieldKeys.Error: err,
+			"accreditation":          accreditationName,
+		})
+		return false, err
+	}
+
+	logger.Info("Accreditation rate limit exceeded, job deferred", log.Fields{
+		"accreditation": accreditationName,
+		"start_date":    startDate,
+	})
+
+	return true, nil
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/config"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+)
+
+func setup() {
+	log.Setup(config.Config{LogLevel: "mute"})
+}
+
+func TestDeferJob_Allowed(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+
+	db.On("AcquireRateLimitToken", ctx, "opensrs-uniregistry").Return(time.Duration(0), nil)
+
+	deferred, err := DeferJob(ctx, db, db, job, "opensrs-uniregistry", log.CreateChildLogger())
+	require.NoError(t, err)
+	require.False(t, deferred)
+	require.Nil(t, job.StartDate)
+
+	db.AssertNotCalled(t, "UpdateJob", mock.Anything, mock.Anything)
+}
+
+func TestDeferJob_Limited(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	tx := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+	wait := 2 * time.Second
+
+	db.On("AcquireRateLimitToken", ctx, "opensrs-uniregistry").Return(wait, nil)
+	tx.On("UpdateJob", ctx, job).Return(nil)
+
+	before := time.Now()
+	deferred, err := DeferJob(ctx, db, tx, job, "opensrs-uniregistry", log.CreateChildLogger())
+	require.NoError(t, err)
+	require.True(t, deferred)
+
+	require.NotNil(t, job.StartDate)
+	require.False(t, job.StartDate.Before(before.Add(wait)))
+	require.False(t, job.StartDate.After(time.Now().Add(2*wait)))
+
+	tx.AssertExpectations(t)
+}
+
+func TestDeferJob_LimitCheckFailed(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+
+	db.On("AcquireRateLimitToken", ctx, "opensrs-uniregistry").Return(time.Duration(0), errors.New("connection reset"))
+
+	deferred, err := DeferJob(ctx, db, db, job, "opensrs-uniregistry", log.CreateChildLogger())
+	require.NoError(t, err)
+	require.False(t, deferred)
+}
+
+func TestDeferJob_UpdateFailed(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	db := &database.MockDatabase{}
+	job := &model.Job{ID: "job1"}
+	updateErr := errors.New("could not obtain lock")
+
+	db.On("AcquireRateLimitToken", ctx, "opensrs-uniregistry").Return(time.Second, nil)
+	db.On("UpdateJob", ctx, job).Return(updateErr)
+
+	deferred, err := DeferJob(ctx, db, db, job, "opensrs-uniregistry", log.CreateChildLogger())
+	require.ErrorIs(t, err, updateErr)
+	require.False(t, deferred)
+}
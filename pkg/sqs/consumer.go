@@ -3,6 +3,7 @@ package sqs
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"time"
 
 	"google.golang.org/protobuf/proto"
@@ -14,6 +15,7 @@ import (
 	wire "github.com/tucowsinc/tdp-messagebus-go/pkg/message"
 	"github.com/tucowsinc/tdp-messages-go/message"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 	hostingproto "github.com/tucowsinc/tucows-domainshosting-app/cmd/functions/order/proto"
 )
@@ -26,6 +28,7 @@ var _ Consumer = (*sqsConsumer)(nil)
 type Consumer interface {
 	Ping(ctx context.Context) error
 	Consume()
+	Stop()
 	Register(m proto.Message, h HandlerFuncType)
 }
 
@@ -60,11 +63,15 @@ func NewConsumer(ctx context.Context, options SqsOptions) (c Consumer, err error
 
 	log.Info("AWS queue URL", log.Fields{"url": *queueUrl.QueueUrl})
 
+	receiveCtx, stopReceive := context.WithCancel(ctx)
+
 	consumer := &sqsConsumer{
-		ctx:      ctx,
-		client:   client,
-		queueUrl: queueUrl.QueueUrl,
-		options:  &options,
+		ctx:         ctx,
+		receiveCtx:  receiveCtx,
+		stopReceive: stopReceive,
+		client:      client,
+		queueUrl:    queueUrl.QueueUrl,
+		options:     &options,
 	}
 
 	consumer.handlers = make(map[string]handlerType)
@@ -101,7 +108,7 @@ func (c *sqsConsumer) Consume() {
 	}
 
 	for {
-		output, err := c.client.ReceiveMessage(c.ctx, &sqs.ReceiveMessageInput{
+		output, err := c.client.ReceiveMessage(c.receiveCtx, &sqs.ReceiveMessageInput{
 			QueueUrl:              c.queueUrl,
 			MaxNumberOfMessages:   10,
 			VisibilityTimeout:     30, // messages are redelivered after 30 second if not deleted
@@ -109,18 +116,21 @@ func (c *sqsConsumer) Consume() {
 			AttributeNames:        []sqstypes.QueueAttributeName{sqstypes.QueueAttributeName(sqstypes.MessageSystemAttributeNameSentTimestamp)},
 		})
 
-		if c.ctx.Err() != nil {
+		if c.receiveCtx.Err() != nil {
 			log.Debug("context done closing consumer loop...")
 			return
 		}
 
 		if err != nil {
+			metrics.SQSFailures.WithLabelValues(c.options.QueueName, metrics.SQSReceive).Inc()
 			log.Error("error receiving messages", log.Fields{
 				types.LogFieldKeys.Error: err.Error(),
 			})
 			continue
 		}
 
+		metrics.SQSMessages.WithLabelValues(c.options.QueueName, metrics.SQSReceive).Add(float64(len(output.Messages)))
+
 		if len(output.Messages) == 0 {
 			log.Debug("no new messages; sleeping...")
 			time.Sleep(5 * time.Second)
@@ -134,6 +144,11 @@ func (c *sqsConsumer) Consume() {
 	}
 }
 
+// Stop stops receiving messages from SQS, the messages already received are still handled until ctx is done
+func (c *sqsConsumer) Stop() {
+	c.stopReceive()
+}
+
 // ack provides a mechanism to ack a message on SQS that has been processed by the consumer
 func (c *sqsConsumer) ack(id string) {
 	msgHandle, ok := ackMap.Get(id)
@@ -165,12 +180,16 @@ func (c *sqsConsumer) deleteMessage(msgHandle *string) (err error) {
 		ReceiptHandle: msgHandle,
 	})
 	if err != nil {
+		metrics.SQSFailures.WithLabelValues(c.options.QueueName, metrics.SQSDelete).Inc()
 		log.Error("error deleting message handle", log.Fields{
 			"msg_handle":             *msgHandle,
 			types.LogFieldKeys.Error: err.Error(),
 		})
+		return
 	}
 
+	metrics.SQSMessages.WithLabelValues(c.options.QueueName, metrics.SQSDelete).Inc()
+
 	return
 }
 
@@ -213,6 +232,7 @@ func (c *sqsConsumer) decode(sqsMsg *sqstypes.Message) {
 	msg := new(hostingproto.OrderDetailsResponse)
 	err := proto.Unmarshal(decoded, msg)
 	if err != nil {
+		metrics.SQSFailures.WithLabelValues(c.options.QueueName, metrics.SQSDecode).Inc()
 		log.Error("error decoding payload", log.Fields{
 			"message_id":             *sqsMsg.MessageId,
 			types.LogFieldKeys.Error: err.Error(),
@@ -273,14 +293,15 @@ func (c *sqsConsumer) handle(wireMsg *message.TcWire) {
 		return
 	}
 
-	// panic recovery to gracefully handle user defined handler functions
+	// panic recovery to gracefully handle user defined handler functions, the message is not acked
+	// and is redelivered once its visibility timeout expires
 	defer func() {
 		if r := recover(); r != nil {
 			log.Error("call to handler was not successful: panic", log.Fields{
 				"message_type":           msgType,
-				types.LogFieldKeys.Error: err.Error(),
+				types.LogFieldKeys.Error: fmt.Sprintf("panic while handling message: %v", r),
+				"stack":                  string(debug.Stack()),
 			})
-			err = errors.New(fmt.Sprintf("panic while handling message: %v", r))
 		}
 	}()
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
@@ -180,6 +181,29 @@ func (s *SQSConsumerTestSuite) TestConsumer__Success() {
 	mockSQSClient.AssertExpectations(s.T())
 }
 
+func (s *SQSConsumerTestSuite) TestConsumer__Stop() {
+	s.mockSQSClient.On("ReceiveMessage", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, context.Canceled)
+
+	done := make(chan struct{})
+	go func() {
+		s.sqsConsumer.Consume()
+		close(done)
+	}()
+
+	s.sqsConsumer.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("consumer did not stop receiving")
+	}
+
+	// messages already received are still handled
+	s.NoError(s.sqsConsumer.(*sqsConsumer).ctx.Err())
+}
+
 func (s *SQSConsumerTestSuite) Test_deleteMessage__Failure() {
 	testHandle := uuid.NewString()
 	mockSQSClient := &MockSQSClientAPI{}
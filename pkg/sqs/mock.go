@@ -75,6 +75,10 @@ func (c *MockConsumer) Consume() {
 	c.Called()
 }
 
+func (c *MockConsumer) Stop() {
+	c.Called()
+}
+
 func (c *MockConsumer) Register(m proto.Message, h HandlerFuncType) {
 	c.Called(m, h)
 }
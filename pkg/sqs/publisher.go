@@ -8,6 +8,7 @@ import (
 	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/google/uuid"
 	log "github.com/tucowsinc/tdp-workers-go/pkg/logging"
+	"github.com/tucowsinc/tdp-workers-go/pkg/metrics"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 	"google.golang.org/protobuf/proto"
 )
@@ -92,11 +93,14 @@ func (p *sqsPublisher) Send(msg proto.Message) (msgId string, err error) {
 	})
 
 	if err != nil {
+		metrics.SQSFailures.WithLabelValues(p.options.QueueName, metrics.SQSSend).Inc()
 		log.Error("Error sending message to queue", log.Fields{
 			types.LogFieldKeys.Queue: *p.queueUrl,
 			types.LogFieldKeys.Error: err.Error()})
 		return "", err
 	}
 
+	metrics.SQSMessages.WithLabelValues(p.options.QueueName, metrics.SQSSend).Inc()
+
 	return *output.MessageId, nil
 }
@@ -99,6 +99,8 @@ type SqsOptionsBuilder struct {
 
 type sqsConsumer struct {
 	ctx          context.Context
+	receiveCtx   context.Context
+	stopReceive  context.CancelFunc
 	client       SQSClientAPI
 	options      *SqsOptions
 	queueUrl     *string
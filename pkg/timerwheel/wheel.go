@@ -0,0 +1,191 @@
+package timerwheel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTick  = 100 * time.Millisecond
+	DefaultSlots = 600 // one revolution per minute with the default tick
+)
+
+// entry is a single scheduled key
+type entry struct {
+	key  string
+	at   time.Time
+	tick uint64
+}
+
+// Wheel is a hashed timer wheel which fires scheduled keys once their time is reached.
+//
+// Every key is placed into the slot of the tick it is due at, so advancing the wheel only
+// looks at a single slot instead of all scheduled keys. Keys due further than one revolution
+// ahead stay in their slot until the wheel reaches their tick.
+// Scheduling the same key again replaces its previous time.
+type Wheel struct {
+	tick  time.Duration
+	slots []map[string]*entry
+
+	mu      sync.Mutex
+	start   time.Time
+	current uint64
+	entries map[string]*entry
+}
+
+// New creates timer wheel with the given tick resolution and number of slots
+func New(tick time.Duration, slots int) *Wheel {
+	if tick <= 0 {
+		tick = DefaultTick
+	}
+
+	if slots <= 0 {
+		slots = DefaultSlots
+	}
+
+	w := &Wheel{
+		tick:    tick,
+		slots:   make([]map[string]*entry, slots),
+		start:   time.Now(),
+		entries: make(map[string]*entry),
+	}
+
+	for i := range w.slots {
+		w.slots[i] = make(map[string]*entry)
+	}
+
+	return w
+}
+
+// Schedule schedules key to fire at the given time, replacing previous schedule of the key.
+// Keys which are already due fire on the next tick.
+func (w *Wheel) Schedule(key string, at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.remove(key)
+
+	e := &entry{key: key, at: at, tick: w.tickOf(at)}
+	w.entries[key] = e
+	w.slots[e.tick%uint64(len(w.slots))][key] = e
+}
+
+// Cancel removes key from the wheel, it returns false if key was not scheduled
+func (w *Wheel) Cancel(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.remove(key)
+}
+
+// Scheduled returns the time key is scheduled at
+func (w *Wheel) Scheduled(key string) (time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e, ok := w.entries[key]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return e.at, true
+}
+
+// Len returns the number of scheduled keys
+func (w *Wheel) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.entries)
+}
+
+// Run advances the wheel every tick until ctx is done, calling fire with the keys which became due.
+// fire is called from the Run goroutine and should not block for long.
+func (w *Wheel) Run(ctx context.Context, fire func(keys []string)) error {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if keys := w.Advance(now); len(keys) > 0 {
+				fire(keys)
+			}
+		}
+	}
+}
+
+// Advance moves the wheel up to now and returns the keys which became due.
+// All ticks missed since the last call are processed, so a late call never skips keys.
+func (w *Wheel) Advance(now time.Time) (due []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target := w.elapsed(now)
+
+	for w.current < target {
+		w.current++
+
+		slot := w.slots[w.current%uint64(len(w.slots))]
+		for key, e := range slot {
+			if e.tick > w.current {
+				// due in one of the next revolutions
+				continue
+			}
+
+			delete(slot, key)
+			delete(w.entries, key)
+			due = append(due, key)
+		}
+
+		// nothing left to fire, skip straight to the target tick
+		if len(w.entries) == 0 {
+			w.current = target
+		}
+	}
+
+	return
+}
+
+// tickOf returns the first tick at or after t which has not been processed yet
+func (w *Wheel) tickOf(t time.Time) uint64 {
+	if !t.After(w.start) {
+		return w.current + 1
+	}
+
+	d := t.Sub(w.start)
+	tick := uint64(d / w.tick)
+	if d%w.tick != 0 {
+		tick++
+	}
+
+	if tick <= w.current {
+		return w.current + 1
+	}
+
+	return tick
+}
+
+// elapsed returns the number of whole ticks passed since the wheel start
+func (w *Wheel) elapsed(now time.Time) uint64 {
+	if !now.After(w.start) {
+		return 0
+	}
+
+	return uint64(now.Sub(w.start) / w.tick)
+}
+
+func (w *Wheel) remove(key string) bool {
+	e, ok := w.entries[key]
+	if !ok {
+		return false
+	}
+
+	delete(w.entries, key)
+	delete(w.slots[e.tick%uint64(len(w.slots))], key)
+
+	return true
+}
@@ -0,0 +1,102 @@
+package timerwheel
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWheelAdvance(t *testing.T) {
+	w := New(100*time.Millisecond, 10)
+	start := w.start
+
+	w.Schedule("a", start.Add(250*time.Millisecond))
+	w.Schedule("b", start.Add(400*time.Millisecond))
+	w.Schedule("c", start.Add(5*time.Second)) // several revolutions ahead
+
+	require.Empty(t, w.Advance(start.Add(200*time.Millisecond)))
+	require.Equal(t, []string{"a"}, w.Advance(start.Add(300*time.Millisecond)))
+	require.Equal(t, []string{"b"}, w.Advance(start.Add(1200*time.Millisecond)))
+	require.Equal(t, 1, w.Len())
+
+	require.Equal(t, []string{"c"}, w.Advance(start.Add(5*time.Second)))
+	require.Equal(t, 0, w.Len())
+}
+
+func TestWheelAdvance_LateCall(t *testing.T) {
+	w := New(100*time.Millisecond, 10)
+	start := w.start
+
+	w.Schedule("a", start.Add(150*time.Millisecond))
+	w.Schedule("b", start.Add(2500*time.Millisecond))
+	w.Schedule("c", start.Add(time.Minute))
+
+	due := w.Advance(start.Add(3 * time.Second))
+	sort.Strings(due)
+
+	require.Equal(t, []string{"a", "b"}, due)
+	require.Equal(t, 1, w.Len())
+}
+
+func TestWheelSchedule_Past(t *testing.T) {
+	w := New(100*time.Millisecond, 10)
+	start := w.start
+
+	w.Advance(start.Add(time.Second))
+	w.Schedule("a", start.Add(-time.Hour))
+
+	require.Equal(t, []string{"a"}, w.Advance(start.Add(1100*time.Millisecond)))
+}
+
+func TestWheelSchedule_Replace(t *testing.T) {
+	w := New(100*time.Millisecond, 10)
+	start := w.start
+
+	w.Schedule("a", start.Add(200*time.Millisecond))
+	w.Schedule("a", start.Add(time.Second))
+
+	at, ok := w.Scheduled("a")
+	require.True(t, ok)
+	require.Equal(t, start.Add(time.Second), at)
+	require.Equal(t, 1, w.Len())
+
+	require.Empty(t, w.Advance(start.Add(500*time.Millisecond)))
+	require.Equal(t, []string{"a"}, w.Advance(start.Add(time.Second)))
+}
+
+func TestWheelCancel(t *testing.T) {
+	w := New(100*time.Millisecond, 10)
+	start := w.start
+
+	w.Schedule("a", start.Add(200*time.Millisecond))
+
+	require.True(t, w.Cancel("a"))
+	require.False(t, w.Cancel("a"))
+	require.Empty(t, w.Advance(start.Add(time.Second)))
+}
+
+func TestWheelRun(t *testing.T) {
+	w := New(10*time.Millisecond, 8)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	fired := make(chan []string, 1)
+	go w.Run(ctx, func(keys []string) {
+		fired <- keys
+	})
+
+	at := time.Now().Add(50 * time.Millisecond)
+	w.Schedule("a", at)
+
+	select {
+	case keys := <-fired:
+		require.Equal(t, []string{"a"}, keys)
+		require.False(t, time.Now().Before(at))
+	case <-ctx.Done():
+		t.Fatal("key was not fired")
+	}
+}
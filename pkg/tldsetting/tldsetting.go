@@ -0,0 +1,89 @@
+package tldsetting
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+)
+
+// Get returns the value of the TLD setting of the accreditation tld, it is empty when the setting is not set.
+// A failure to read the setting is transient.
+func Get(ctx context.Context, db database.Database, accreditationTldId string, key string) (string, error) {
+	setting, err := db.GetTLDSetting(ctx, accreditationTldId, key)
+	if err != nil {
+		return "", joberrors.Transient(fmt.Errorf("failed to get TLD setting %s: %w", key, err))
+	}
+
+	if setting == nil {
+		return "", nil
+	}
+
+	return setting.Value, nil
+}
+
+// Bool returns the boolean TLD setting, it is false when the setting is not set.
+// A value which is not a boolean is a permanent error.
+func Bool(ctx context.Context, db database.Database, accreditationTldId string, key string) (bool, error) {
+	value, err := Get(ctx, db, accreditationTldId, key)
+	if err != nil || value == "" {
+		return false, err
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, invalid(key, err)
+	}
+
+	return parsed, nil
+}
+
+// StringList returns the text array TLD setting, it is nil when the setting is not set.
+// A value which is not an array is a permanent error.
+func StringList(ctx context.Context, db database.Database, accreditationTldId string, key string) ([]string, error) {
+	value, err := Get(ctx, db, accreditationTldId, key)
+	if err != nil || value == "" {
+		return nil, err
+	}
+
+	var values pq.StringArray
+	if err = values.Scan(value); err != nil {
+		return nil, invalid(key, err)
+	}
+
+	return values, nil
+}
+
+// IntList returns the integer array TLD setting, it is nil when the setting is not set.
+// A value which is not an integer array is a permanent error.
+func IntList(ctx context.Context, db database.Database, accreditationTldId string, key string) ([]int, error) {
+	value, err := Get(ctx, db, accreditationTldId, key)
+	if err != nil || value == "" {
+		return nil, err
+	}
+
+	var values pq.Int64Array
+	if err = values.Scan(value); err != nil {
+		return nil, invalid(key, err)
+	}
+
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v)
+	}
+
+	return ints, nil
+}
+
+// Invalid returns the permanent error of a TLD setting which value is not allowed
+func Invalid(key string, value string) error {
+	return joberrors.Permanent(fmt.Errorf("invalid TLD setting %s value %q", key, value))
+}
+
+func invalid(key string, err error) error {
+	return joberrors.Permanent(fmt.Errorf("failed to parse TLD setting %s: %w", key, err))
+}
@@ -0,0 +1,71 @@
+package tldsetting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tucowsinc/tdp-workers-go/pkg/database"
+	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
+)
+
+func TestSettings(t *testing.T) {
+	ctx := context.Background()
+
+	db := &database.MockDatabase{}
+	db.OnTLDSettings("acc-tld", map[string]string{
+		"tld.test.bool":        "true",
+		"tld.test.strings":     "{de,fr}",
+		"tld.test.ints":        "{13,8}",
+		"tld.test.empty":       "",
+		"tld.test.invalid":     "yes please",
+		"tld.test.empty_array": "{}",
+	})
+	db.On("GetTLDSetting", mock.Anything, "acc-tld", "tld.test.unset").Return((*model.VAttribute)(nil), nil)
+	db.On("GetTLDSetting", mock.Anything, "acc-tld", "tld.test.unavailable").Return((*model.VAttribute)(nil), errors.New("connection refused"))
+
+	b, err := Bool(ctx, db, "acc-tld", "tld.test.bool")
+	require.NoError(t, err)
+	require.True(t, b)
+
+	values, err := StringList(ctx, db, "acc-tld", "tld.test.strings")
+	require.NoError(t, err)
+	require.Equal(t, []string{"de", "fr"}, values)
+
+	ints, err := IntList(ctx, db, "acc-tld", "tld.test.ints")
+	require.NoError(t, err)
+	require.Equal(t, []int{13, 8}, ints)
+
+	ints, err = IntList(ctx, db, "acc-tld", "tld.test.empty_array")
+	require.NoError(t, err)
+	require.Empty(t, ints)
+
+	// settings which are not set have their zero value
+	for _, key := range []string{"tld.test.unset", "tld.test.empty"} {
+		b, err = Bool(ctx, db, "acc-tld", key)
+		require.NoError(t, err)
+		require.False(t, b)
+
+		values, err = StringList(ctx, db, "acc-tld", key)
+		require.NoError(t, err)
+		require.Nil(t, values)
+	}
+
+	// invalid values are permanent
+	_, err = Bool(ctx, db, "acc-tld", "tld.test.invalid")
+	require.Error(t, err)
+	require.False(t, joberrors.IsTransient(err))
+
+	_, err = IntList(ctx, db, "acc-tld", "tld.test.strings")
+	require.Error(t, err)
+	require.False(t, joberrors.IsTransient(err))
+
+	// read failures are transient
+	_, err = StringList(ctx, db, "acc-tld", "tld.test.unavailable")
+	require.EqualError(t, err, "failed to get TLD setting tld.test.unavailable: connection refused")
+	require.True(t, joberrors.IsTransient(err))
+}
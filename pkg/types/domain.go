@@ -66,6 +66,7 @@ type DomainData struct {
 	LaunchData         *DomainLaunchData `json:"launch_data"`
 	SecDNS             *SecDNSData       `json:"secdns"`
 	IdnData            *IdnData          `json:"idn"`
+	Attributes         map[string]string `json:"attributes"`
 }
 
 type SecDNSUpdateAddData struct {
@@ -139,6 +140,7 @@ type DomainUpdateData struct {
 	ProvisionDomainUpdateId string            `json:"provision_domain_update_id"`
 	Locks                   map[string]bool   `json:"locks"`
 	SecDNSData              *SecDNSUpdateData `json:"secdns"`
+	Attributes              map[string]string `json:"attributes"`
 }
 
 type DomainTransferInRequestData struct {
@@ -162,6 +162,12 @@ type JobEvent struct {
 	Metadata       Metadata `json:"metadata"`
 }
 
+// JobScheduledEvent is sent when a submitted job is scheduled to start in the future
+type JobScheduledEvent struct {
+	JobId     string    `json:"job_id"`
+	StartDate time.Time `json:"start_date"`
+}
+
 func GetQueryQueue(accreditation string) (queue string) {
 	queue = fmt.Sprintf("ry-%v-query", accreditation)
 	return
@@ -251,6 +257,7 @@ func IsValidUUID(u string) bool {
 var JobStatus = struct {
 	Created,
 	Submitted,
+	Dispatching,
 	Processing,
 	Completed,
 	Failed,
@@ -258,6 +265,7 @@ var JobStatus = struct {
 }{
 	"created",
 	"submitted",
+	"dispatching",
 	"processing",
 	"completed",
 	"failed",
@@ -404,6 +412,15 @@ var EPPStatusCode = struct {
 	"clientUpdateProhibited",
 }
 
+// LocksToEppStatus maps domain lock names to the EPP client status they set
+var LocksToEppStatus = map[string]string{
+	"update":   EPPStatusCode.ClientUpdateProhibited,
+	"delete":   EPPStatusCode.ClientDeleteProhibited,
+	"transfer": EPPStatusCode.ClientTransferProhibited,
+	"renew":    EPPStatusCode.ClientRenewProhibited,
+	"hold":     EPPStatusCode.ClientHold,
+}
+
 var RgpStatus = struct {
 	AddPeriod,
 	AutoRenewPeriod,
@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
-	"github.com/google/uuid"
 	tcwire "github.com/tucowsinc/tdp-messages-go/message"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/worker"
@@ -18,6 +18,9 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// AutoRenewPollEnabledSetting is the TLD setting opting an accreditation in to auto-renew poll message handling
+const AutoRenewPollEnabledSetting = "tld.lifecycle.autorenew_poll_enabled"
+
 type AutoRenewHandler struct{}
 
 func NewAutoRenewHandler() *AutoRenewHandler {
@@ -36,14 +39,7 @@ func (a *AutoRenewHandler) Matches(msg *worker.PollMessage) bool {
 	return false
 }
 
-func (a *AutoRenewHandler) Handle(ctx context.Context, service *WorkerService, request *worker.PollMessage) (err error) {
-	logger := log.CreateChildLogger(log.Fields{
-		types.LogFieldKeys.LogID:     uuid.NewString(),
-		LogFieldKeys.PollMessageID:   request.Id,
-		LogFieldKeys.PollMessageType: request.Type,
-		LogFieldKeys.Accreditation:   request.Accreditation,
-	})
-
+func (a *AutoRenewHandler) Handle(ctx context.Context, service *WorkerService, request *worker.PollMessage, logger logger.ILogger) (err error) {
 	// Get domain name
 	domainName := GetDomainName(request)
 	if domainName == "" {
@@ -70,46 +66,67 @@ func (a *AutoRenewHandler) Handle(ctx context.Context, service *WorkerService, r
 		return
 	}
 
-	// Get domain expiry date
-	exDate, err := GetDomainExpiryDate(service, ctx, request, domainName, logger)
+	// Auto-renew handling is opt-in per accreditation
+	enabled, err := isAutoRenewPollEnabled(ctx, service, types.SafeDeref(domain.AccreditationTldID))
 	if err != nil {
-		logger.Error("Error getting domain expiry date", log.Fields{
+		logger.Error("Error getting auto-renew poll setting", log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
 		return
 	}
-
-	// Domain model
-	d := model.Domain{
-		ID:           domain.ID,
-		RyExpiryDate: *types.TimestampToTime(exDate),
-		ExpiryDate:   *types.TimestampToTime(exDate),
+	if !enabled {
+		logger.Info("Auto-renew poll message handling is disabled for accreditation, skipping", log.Fields{
+			types.LogFieldKeys.Domain: domainName,
+		})
+		return
 	}
 
-	// Update domain expiry date
-	err = service.db.UpdateDomain(ctx, &d)
+	// Get domain expiry date
+	exDate, err := GetDomainExpiryDate(service, ctx, request, domainName, logger)
 	if err != nil {
-		logger.Error("Error updating domain details in database", log.Fields{
+		logger.Error("Error getting domain expiry date", log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
 		return
 	}
 
-	// Domain rgp status model
-	drs := model.DomainRgpStatus{
-		DomainID: domain.ID,
-		StatusID: service.db.GetRgpStatusId("autorenew_grace_period"),
-	}
+	renewed := false
+	err = service.db.WithTransaction(func(tx database.Database) (err error) {
+		// Update domain expiry date unless this expiry was already recorded
+		renewed, err = tx.AutoRenewDomain(ctx, domain.ID, *types.TimestampToTime(exDate))
+		if err != nil || !renewed {
+			return err
+		}
+
+		// Domain rgp status model
+		drs := model.DomainRgpStatus{
+			DomainID: domain.ID,
+			StatusID: tx.GetRgpStatusId("autorenew_grace_period"),
+		}
+
+		// Create domain rgp status
+		err = tx.CreateDomainRgpStatus(ctx, &drs)
+		if err != nil {
+			return err
+		}
 
-	// Create domain rgp status
-	err = service.db.CreateDomainRgpStatus(ctx, &drs)
+		// Notify the tenant
+		return tx.CreateDomainAutoRenewEvent(ctx, domain.ID, *types.TimestampToTime(exDate))
+	})
 	if err != nil {
-		logger.Error("Error inserting domain RGP status in database", log.Fields{
+		logger.Error("Error recording domain auto-renew in database", log.Fields{
 			types.LogFieldKeys.Error: err,
 		})
 		return
 	}
 
+	if !renewed {
+		logger.Info("Domain auto-renew already recorded for expiry date, skipping", log.Fields{
+			types.LogFieldKeys.Domain: domainName,
+		})
+		return
+	}
+
 	logger.Info("Successfully handled auto-renew for domain", log.Fields{
 		types.LogFieldKeys.Domain: domainName,
 	})
@@ -117,6 +134,20 @@ func (a *AutoRenewHandler) Handle(ctx context.Context, service *WorkerService, r
 	return
 }
 
+// isAutoRenewPollEnabled tells if auto-renew poll messages are handled for the accreditation tld
+func isAutoRenewPollEnabled(ctx context.Context, service *WorkerService, accreditationTldId string) (bool, error) {
+	setting, err := service.db.GetTLDSetting(ctx, accreditationTldId, AutoRenewPollEnabledSetting)
+	if err != nil {
+		return false, err
+	}
+
+	if setting == nil {
+		return false, nil
+	}
+
+	return strconv.ParseBool(setting.Value)
+}
+
 // GetDomainExpiryDate gets expiry date from renewal poll message type or fetches from domain info request
 func GetDomainExpiryDate(service *WorkerService, ctx context.Context, request *worker.PollMessage, domainName string, logger logger.ILogger) (exDate *timestamppb.Timestamp, err error) {
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
 
@@ -58,7 +59,7 @@ func (service *WorkerService) PollMessageHandler(server messagebus.Server, messa
 
 	if err != nil {
 		// skip temporary failures
-		if errors.Is(err, ErrTempRyFailure) {
+		if joberrors.IsTransient(err) {
 			logger.Warn("Temporary failure detected, skipping for now", log.Fields{
 				types.LogFieldKeys.Error: err,
 			})
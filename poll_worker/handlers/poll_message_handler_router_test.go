@@ -12,13 +12,16 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/tucowsinc/tdp-messagebus-go/pkg/messagebus"
 	"github.com/tucowsinc/tdp-messagebus-go/pkg/mocks"
 	"github.com/tucowsinc/tdp-messages-go/message/ryinterface"
 	"github.com/tucowsinc/tdp-messages-go/message/worker"
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type PollMessageTestSuite struct {
@@ -349,103 +352,140 @@ func (suite *PollMessageTestSuite) TestPollMessageHandlerUnspecPendingAction() {
 	}
 }
 
-// func (suite *PollMessageTestSuite) TestPollMessageHandlerRenewal() {
-// 	tldName := "sexy"
-// 	accreditationName := "opensrs-uniregistry"
-
-// 	name := fmt.Sprintf("%v.%v", uuid.New().String(), tldName)
-// 	domain, _, err := insertTestDomain(suite.db, name, accreditationName, tldName)
-// 	if err != nil {
-// 		return
-// 	}
-
-// 	expectedRgpEppStatus := "autoRenewPeriod"
-// 	expectedExDate := time.Now().AddDate(1, 0, 0)
-
-// 	msg := &worker.PollMessage{
-// 		Id:            uuid.New().String(),
-// 		Msg:           fmt.Sprintf("Auto Renew Notice: %v", name),
-// 		Type:          PollMessageType.Renewal,
-// 		Accreditation: accreditationName,
-// 		Data: &worker.PollMessage_RenData{
-// 			RenData: &ryinterface.EppPollRenData{
-// 				Name:   name,
-// 				ExDate: timestamppb.New(expectedExDate),
-// 			},
-// 		},
-// 	}
-
-// 	suite.s.On("Context").Return(suite.ctx)
-
-// 	err = suite.service.PollMessageHandler(suite.s, msg)
-// 	suite.NoError(err, "Failed to process poll message")
-
-// 	updatedDomain, err := suite.db.GetVDomain(suite.ctx, &model.VDomain{Name: &name})
-// 	if err != nil {
-// 		return
-// 	}
-
-// 	suite.NotEqual(&domain.RyExpiryDate, updatedDomain.RyExpiryDate)
-// 	suite.Equal(expectedExDate.Unix(), updatedDomain.RyExpiryDate.Unix())
-// 	suite.Equal(&expectedRgpEppStatus, updatedDomain.RgpEppStatus)
-
-// 	suite.mb.AssertExpectations(suite.T())
-// }
-
-// func (suite *PollMessageTestSuite) TestPollMessageHandlerUnspecRenewal() {
-// 	tldName := "sexy"
-// 	accreditationName := "enom-uniregistry"
-
-// 	name := fmt.Sprintf("%v.%v", uuid.New().String(), tldName)
-// 	domain, _, err := insertTestDomain(suite.db, name, accreditationName, tldName)
-// 	if err != nil {
-// 		return
-// 	}
-
-// 	expectedRgpEppStatus := "autoRenewPeriod"
-// 	expectedExDate := time.Now().AddDate(1, 0, 0)
-// 	expectedContext := suite.ctx
-// 	expectedDestination := types.GetQueryQueue(accreditationName)
-// 	expectedMsg := ryinterface.DomainInfoRequest{
-// 		Name: name,
-// 	}
-// 	expectedHeaders := map[string]any{}
-
-// 	domainInfoResponse := ryinterface.DomainInfoResponse{
-// 		Name:       name,
-// 		ExpiryDate: timestamppb.New(expectedExDate),
-// 	}
-
-// 	expResponse := messagebus.RpcResponse{
-// 		Server:  nil,
-// 		Message: &domainInfoResponse,
-// 	}
-
-// 	suite.mb.On("Call", mock.AnythingOfType("*context.timerCtx"), expectedDestination, &expectedMsg, expectedHeaders).Return("test_id", expResponse, nil)
-// 	suite.s.On("MessageBus").Return(suite.mb)
-// 	suite.s.On("Context").Return(expectedContext)
-
-// 	msg := &worker.PollMessage{
-// 		Id:            uuid.New().String(),
-// 		Msg:           fmt.Sprintf("M085: Domain %v (ABC-US) auto-renewed", name),
-// 		Type:          PollMessageType.Unspec,
-// 		Accreditation: accreditationName,
-// 	}
-
-// 	err = suite.service.PollMessageHandler(suite.s, msg)
-// 	suite.NoError(err, "Failed to process poll message")
-
-// 	updatedDomain, err := suite.db.GetVDomain(expectedContext, &model.VDomain{Name: &name})
-// 	if err != nil {
-// 		return
-// 	}
-
-// 	suite.NotEqual(&domain.RyExpiryDate, updatedDomain.RyExpiryDate)
-// 	suite.Equal(expectedExDate.Unix(), updatedDomain.RyExpiryDate.Unix())
-// 	suite.Equal(&expectedRgpEppStatus, updatedDomain.RgpEppStatus)
-
-// 	suite.mb.AssertExpectations(suite.T())
-// }
+func enableAutoRenewPoll(db database.Database, accreditationTldId *string, enabled bool) error {
+	sql := `UPDATE v_attribute SET value = ? WHERE key = ? AND accreditation_tld_id = ?`
+	return db.GetDB().Exec(sql, fmt.Sprint(enabled), AutoRenewPollEnabledSetting, accreditationTldId).Error
+}
+
+func countAutoRenewRgpStatuses(db database.Database, domainId string) (count int64, err error) {
+	err = db.GetDB().Table("domain_rgp_status").
+		Where("domain_id = ? AND status_id = ?", domainId, db.GetRgpStatusId("autorenew_grace_period")).
+		Count(&count).Error
+	return
+}
+
+func renewalPollMessage(name, accreditationName string, exDate time.Time) *worker.PollMessage {
+	return &worker.PollMessage{
+		Id:            uuid.New().String(),
+		Msg:           fmt.Sprintf("Auto Renew Notice: %v", name),
+		Type:          PollMessageType.Renewal,
+		Accreditation: accreditationName,
+		Data: &worker.PollMessage_RenData{
+			RenData: &ryinterface.EppPollRenData{
+				Name:   name,
+				ExDate: timestamppb.New(exDate),
+			},
+		},
+	}
+}
+
+func (suite *PollMessageTestSuite) TestPollMessageHandlerRenewal() {
+	tldName := "sexy"
+	accreditationName := "opensrs-uniregistry"
+
+	name := fmt.Sprintf("%v.%v", uuid.New().String(), tldName)
+	domain, _, err := insertTestDomain(suite.db, name, accreditationName, tldName)
+	suite.NoError(err, "Failed to insert test domain")
+
+	err = enableAutoRenewPoll(suite.db, domain.AccreditationTldID, true)
+	suite.NoError(err, "Failed to enable auto-renew poll messages")
+	defer enableAutoRenewPoll(suite.db, domain.AccreditationTldID, false)
+
+	expectedRgpEppStatus := "autoRenewPeriod"
+	expectedExDate := time.Now().AddDate(1, 0, 0)
+
+	suite.s.On("Context").Return(suite.ctx)
+
+	err = suite.service.PollMessageHandler(suite.s, renewalPollMessage(name, accreditationName, expectedExDate))
+	suite.NoError(err, "Failed to process poll message")
+
+	updatedDomain, err := suite.db.GetVDomain(suite.ctx, &model.VDomain{Name: &name})
+	suite.NoError(err, "Failed to get domain")
+
+	suite.Equal(expectedExDate.Unix(), updatedDomain.RyExpiryDate.Unix())
+	suite.Equal(&expectedRgpEppStatus, updatedDomain.RgpEppStatus)
+
+	// a redelivered or duplicate notice for the same expiry is not recorded again
+	err = suite.service.PollMessageHandler(suite.s, renewalPollMessage(name, accreditationName, expectedExDate))
+	suite.NoError(err, "Failed to process duplicate poll message")
+
+	count, err := countAutoRenewRgpStatuses(suite.db, domain.ID)
+	suite.NoError(err, "Failed to count rgp statuses")
+	suite.Equal(int64(1), count)
+
+	suite.mb.AssertExpectations(suite.T())
+}
+
+func (suite *PollMessageTestSuite) TestPollMessageHandlerRenewalDisabled() {
+	tldName := "sexy"
+	accreditationName := "opensrs-uniregistry"
+
+	name := fmt.Sprintf("%v.%v", uuid.New().String(), tldName)
+	domain, _, err := insertTestDomain(suite.db, name, accreditationName, tldName)
+	suite.NoError(err, "Failed to insert test domain")
+
+	suite.s.On("Context").Return(suite.ctx)
+
+	err = suite.service.PollMessageHandler(suite.s, renewalPollMessage(name, accreditationName, time.Now().AddDate(1, 0, 0)))
+	suite.NoError(err, "Failed to process poll message")
+
+	updatedDomain, err := suite.db.GetVDomain(suite.ctx, &model.VDomain{Name: &name})
+	suite.NoError(err, "Failed to get domain")
+	suite.Equal(domain.RyExpiryDate.Unix(), updatedDomain.RyExpiryDate.Unix())
+
+	count, err := countAutoRenewRgpStatuses(suite.db, domain.ID)
+	suite.NoError(err, "Failed to count rgp statuses")
+	suite.Zero(count)
+}
+
+func (suite *PollMessageTestSuite) TestPollMessageHandlerUnspecRenewal() {
+	tldName := "sexy"
+	accreditationName := "enom-uniregistry"
+
+	name := fmt.Sprintf("%v.%v", uuid.New().String(), tldName)
+	domain, _, err := insertTestDomain(suite.db, name, accreditationName, tldName)
+	suite.NoError(err, "Failed to insert test domain")
+
+	err = enableAutoRenewPoll(suite.db, domain.AccreditationTldID, true)
+	suite.NoError(err, "Failed to enable auto-renew poll messages")
+	defer enableAutoRenewPoll(suite.db, domain.AccreditationTldID, false)
+
+	expectedRgpEppStatus := "autoRenewPeriod"
+	expectedExDate := time.Now().AddDate(1, 0, 0)
+	expectedDestination := types.GetQueryQueue(accreditationName)
+	expectedMsg := ryinterface.DomainInfoRequest{
+		Name: name,
+	}
+	expectedHeaders := map[string]any{}
+
+	expResponse := messagebus.RpcResponse{
+		Message: &ryinterface.DomainInfoResponse{
+			Name:       name,
+			ExpiryDate: timestamppb.New(expectedExDate),
+		},
+	}
+
+	suite.mb.On("Call", mock.Anything, expectedDestination, &expectedMsg, expectedHeaders).Return(expResponse, nil)
+	suite.s.On("Context").Return(suite.ctx)
+
+	msg := &worker.PollMessage{
+		Id:            uuid.New().String(),
+		Msg:           fmt.Sprintf("M085: Domain %v (ABC-US) auto-renewed", name),
+		Type:          PollMessageType.Unspec,
+		Accreditation: accreditationName,
+	}
+
+	err = suite.service.PollMessageHandler(suite.s, msg)
+	suite.NoError(err, "Failed to process poll message")
+
+	updatedDomain, err := suite.db.GetVDomain(suite.ctx, &model.VDomain{Name: &name})
+	suite.NoError(err, "Failed to get domain")
+
+	suite.Equal(expectedExDate.Unix(), updatedDomain.RyExpiryDate.Unix())
+	suite.Equal(&expectedRgpEppStatus, updatedDomain.RgpEppStatus)
+
+	suite.mb.AssertExpectations(suite.T())
+}
 
 func (suite *PollMessageTestSuite) TestPollMessageHandlerUnknown() {
 
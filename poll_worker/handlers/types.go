@@ -13,6 +13,7 @@ import (
 	"github.com/tucowsinc/tdp-workers-go/pkg/config"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database"
 	"github.com/tucowsinc/tdp-workers-go/pkg/database/model"
+	"github.com/tucowsinc/tdp-workers-go/pkg/joberrors"
 	mb "github.com/tucowsinc/tdp-workers-go/pkg/message_bus"
 	"github.com/tucowsinc/tdp-workers-go/pkg/types"
 )
@@ -66,7 +67,7 @@ var TransferStatus = struct {
 	"serverCancelled",
 }
 
-var ErrTempRyFailure = errors.New("temporary ry failure")
+var ErrTempRyFailure = joberrors.Transient(errors.New("temporary ry failure"))
 var ErrDeferMessage = errors.New("defers poll message processing")
 
 // PollHandler represents poll handler interface
@@ -94,7 +95,7 @@ type WorkerService struct {
 // NewWorkerService creates and returns instance of worker service
 func NewWorkerService(bus messagebus.MessageBus, db database.Database, tracer *oteltrace.Tracer, cfg config.Config) *WorkerService {
 	PollHandlers := []PollHandler{
-		// NewAutoRenewHandler(), // We don't support auto-renewal poll messages processing at the moment.
+		NewAutoRenewHandler(),
 		NewPendingActionHandler(),
 		NewTransferHandler(),
 	}
@@ -12,3 +12,19 @@ INSERT INTO rgp_status (name, epp_name, descr) VALUES
    ('autorenew_grace_period', 'autoRenewPeriod', 'registry provides credit for deleted domain during this period for the cost of the renewal'),
    ('redemption_grace_period', 'redemptionPeriod', 'deleted domain might be restored during this period'),
    ('pending_delete_period', 'pendingDelete', 'deleted domain not restored during redemptionPeriod');
+
+-- EPP result policies, see docs/error-msg-and-codes-mapping.md
+INSERT INTO epp_result_policy (job_type_id, epp_code, job_status_id, result_message) VALUES
+   (tc_id_from_name('job_type', 'provision_domain_create'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_renew'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_update'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_update'), 2102, tc_id_from_name('job_status', 'failed'), 'Unimplemented option'),
+   (tc_id_from_name('job_type', 'provision_domain_update'), 2302, tc_id_from_name('job_status', 'failed'), 'Host association already exists'),
+   (tc_id_from_name('job_type', 'provision_host_create'), 2302, tc_id_from_name('job_status', 'completed'), 'Host already exists'),
+   (tc_id_from_name('job_type', 'provision_host_delete'), 2303, tc_id_from_name('job_status', 'completed'), 'Host does not exist'),
+   (tc_id_from_name('job_type', 'provision_domain_delete_host'), 2303, tc_id_from_name('job_status', 'completed'), 'Host does not exist'),
+   (tc_id_from_name('job_type', 'provision_domain_delete'), 2303, tc_id_from_name('job_status', 'completed'), NULL),
+   (tc_id_from_name('job_type', 'setup_domain_delete'), 2303, tc_id_from_name('job_status', 'completed'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_transfer_in_request'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_transfer_away'), 1001, tc_id_from_name('job_status', 'failed'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_transfer_in_cancel_request'), 1001, tc_id_from_name('job_status', 'failed'), NULL);
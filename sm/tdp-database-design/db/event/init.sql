@@ -1,2 +1,3 @@
 INSERT INTO event_type (name, reference_table_name, description)
-VALUES ('domain_transfer', 'domain', 'Domain transfer event');
\ No newline at end of file
+VALUES ('domain_transfer', 'domain', 'Domain transfer event'),
+       ('domain_autorenew', 'domain', 'Domain auto-renew event');
@@ -2,6 +2,7 @@
 INSERT INTO job_status(name,descr,is_final,is_success) VALUES
 ('created','Job has been created',false,true),
 ('submitted','Job has been submitted',false,true),
+('dispatching','Job is claimed by a worker sending its command',false,true),
 ('processing','Job is currently running',false,true),
 ('completed','Job has completed successfully',true,true),
 ('failed','Job failed',true,false),
@@ -0,0 +1,33 @@
+-- prefix notify_event chunks with the message id so listeners can re-assemble
+-- chunks of concurrently sent messages independently
+CREATE OR REPLACE FUNCTION notify_event(_channel TEXT,_msg_type TEXT,_payload TEXT) RETURNS BOOLEAN AS 
+$$
+DECLARE 
+    part        INT;
+    total       INT;
+    size        INT;
+    chunk_size  INT DEFAULT (8000-50); 
+    envelope    TEXT;
+    msg         TEXT;
+    msg_id      UUID DEFAULT gen_random_uuid();
+BEGIN
+
+
+    envelope := JSONB_BUILD_OBJECT(
+        'payload',_payload,
+        'type',_msg_type,
+        'id', msg_id
+    )::TEXT;
+
+    size := LENGTH(envelope);
+    total := CEIL(size::NUMERIC / chunk_size::NUMERIC)::INT;
+
+    FOR part IN SELECT generate_series FROM generate_series(1,total,1)
+    LOOP 
+        msg := SUBSTRING(envelope,(chunk_size * (part - 1)) + 1 ,chunk_size );
+        PERFORM PG_NOTIFY(_channel,FORMAT('%s:%s:%s:%s',msg_id,part,total,msg));
+    END LOOP;
+
+    RETURN TRUE;
+END
+$$ LANGUAGE PLPGSQL;
@@ -0,0 +1,16 @@
+--
+-- table: job_dispatch
+-- description: this table stores the dispatch state of submitted jobs to the message bus,
+--              a record is written by the job scheduler once the publish is confirmed or failed
+--
+
+CREATE TABLE job_dispatch (
+  job_id                UUID NOT NULL PRIMARY KEY REFERENCES job ON DELETE CASCADE,
+  attempt_count         INT NOT NULL DEFAULT 0,
+  dispatched_date       TIMESTAMPTZ,
+  last_attempt_date     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  next_attempt_date     TIMESTAMPTZ,
+  last_error            TEXT
+);
+
+CREATE INDEX ON job_dispatch(next_attempt_date) WHERE last_error IS NOT NULL;
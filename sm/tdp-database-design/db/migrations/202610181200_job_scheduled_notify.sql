@@ -0,0 +1,38 @@
+-- notify job scheduler about jobs starting in the future so it can dispatch them
+-- exactly at their start_date
+CREATE INDEX IF NOT EXISTS job_start_date_idx ON job(start_date);
+
+CREATE OR REPLACE FUNCTION job_scheduled_notify() RETURNS TRIGGER AS
+$$
+BEGIN
+
+  PERFORM notify_event(
+    'job_event',
+    'job_scheduled_notify',
+    JSONB_BUILD_OBJECT(
+      'job_id',NEW.id,
+      'start_date',NEW.start_date
+    )::TEXT
+  );
+
+  RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS job_scheduled_notify_tg ON job;
+CREATE TRIGGER job_scheduled_notify_tg AFTER INSERT ON job
+       FOR EACH ROW WHEN (
+              NEW.start_date > NOW()
+              AND NEW.status_id = tc_id_from_name('job_status','submitted') 
+       )
+       EXECUTE PROCEDURE job_scheduled_notify();
+
+DROP TRIGGER IF EXISTS job_scheduled_submitted_tg ON job;
+CREATE TRIGGER job_scheduled_submitted_tg AFTER UPDATE ON job 
+       FOR EACH ROW WHEN (
+              (OLD.status_id <> NEW.status_id OR OLD.start_date <> NEW.start_date)
+              AND NEW.start_date > NOW()
+              AND NEW.status_id = tc_id_from_name('job_status','submitted')
+       )
+       EXECUTE PROCEDURE job_scheduled_notify();
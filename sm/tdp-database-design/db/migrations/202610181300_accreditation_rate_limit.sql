@@ -0,0 +1,57 @@
+--
+-- table: accreditation_rate_limit
+-- description: token bucket limiting outbound registry commands per accreditation
+--
+
+CREATE TABLE accreditation_rate_limit (
+  accreditation_id     UUID NOT NULL PRIMARY KEY REFERENCES accreditation ON DELETE CASCADE,
+  capacity             INT NOT NULL CHECK (capacity > 0),
+  refill_rate          NUMERIC NOT NULL CHECK (refill_rate > 0),
+  tokens               NUMERIC,
+  refilled_date        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+) INHERITS (class.audit_trail);
+
+COMMENT ON TABLE accreditation_rate_limit IS 'accreditations without a row are not rate limited';
+COMMENT ON COLUMN accreditation_rate_limit.capacity IS 'max number of commands sent in a burst';
+COMMENT ON COLUMN accreditation_rate_limit.refill_rate IS 'number of commands allowed per second';
+COMMENT ON COLUMN accreditation_rate_limit.tokens IS 'tokens left in the bucket at refilled_date, NULL means full';
+
+--
+-- function: rate_limit_acquire()
+-- description: takes a token from the accreditation rate limit bucket. Returns the time
+-- to wait until a token becomes available, or zero interval if the token was taken or
+-- the accreditation is not rate limited.
+--
+CREATE OR REPLACE FUNCTION rate_limit_acquire(_accreditation_name TEXT) RETURNS INTERVAL AS $$
+DECLARE
+    _rl     RECORD;
+    _tokens NUMERIC;
+BEGIN
+    SELECT rl.* INTO _rl
+    FROM accreditation_rate_limit rl
+        JOIN accreditation a ON a.id = rl.accreditation_id
+    WHERE a.name = _accreditation_name
+    FOR UPDATE OF rl;
+
+    IF NOT FOUND THEN
+        RETURN INTERVAL '0';
+    END IF;
+
+    -- refill the bucket for the time passed since the last update
+    _tokens := LEAST(
+        _rl.capacity,
+        COALESCE(_rl.tokens, _rl.capacity) + EXTRACT(EPOCH FROM clock_timestamp() - _rl.refilled_date) * _rl.refill_rate
+    );
+
+    IF _tokens < 1 THEN
+        RETURN ((1 - _tokens) / _rl.refill_rate) * INTERVAL '1 second';
+    END IF;
+
+    UPDATE accreditation_rate_limit
+    SET tokens = _tokens - 1,
+        refilled_date = clock_timestamp()
+    WHERE accreditation_id = _rl.accreditation_id;
+
+    RETURN INTERVAL '0';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,16 @@
+--
+-- table: accreditation_maintenance
+-- description: registry maintenance windows; while a window is active jobs for the
+-- accreditation are held and released once the window ends
+--
+
+CREATE TABLE accreditation_maintenance (
+  id                   UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+  accreditation_id     UUID NOT NULL REFERENCES accreditation ON DELETE CASCADE,
+  start_date           TIMESTAMPTZ NOT NULL,
+  end_date             TIMESTAMPTZ NOT NULL,
+  reason               TEXT,
+  CHECK (end_date > start_date)
+) INHERITS (class.audit_trail);
+
+CREATE INDEX ON accreditation_maintenance(accreditation_id, end_date);
@@ -0,0 +1,18 @@
+--
+-- table: job_admin_audit
+-- description: this table stores operations done on jobs through the job scheduler admin API
+--
+
+CREATE TABLE IF NOT EXISTS job_admin_audit (
+  id                    UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+  job_id                UUID REFERENCES job ON DELETE CASCADE,
+  action                TEXT NOT NULL,
+  actor                 TEXT NOT NULL,
+  reason                TEXT,
+  previous_status       TEXT,
+  new_status            TEXT,
+  details               JSONB,
+  created_date          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS job_admin_audit_job_id_idx ON job_admin_audit(job_id);
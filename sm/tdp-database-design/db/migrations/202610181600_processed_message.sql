@@ -0,0 +1,13 @@
+--
+-- table: processed_message
+-- description: this table records message bus messages handled by the workers to skip redeliveries
+--
+
+CREATE TABLE IF NOT EXISTS processed_message (
+  message_key           TEXT NOT NULL PRIMARY KEY,
+  message_type          TEXT NOT NULL,
+  processed_date        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  expiry_date           TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS processed_message_expiry_date_idx ON processed_message(expiry_date);
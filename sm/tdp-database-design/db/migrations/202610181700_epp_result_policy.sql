@@ -0,0 +1,48 @@
+--
+-- table: epp_result_policy
+-- description: maps registry EPP result codes to job outcomes per job type; rows with a tld,
+-- an accreditation or a message pattern override the generic row of the same job type and code
+--
+
+CREATE TABLE IF NOT EXISTS epp_result_policy (
+  id                   UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+  job_type_id          UUID NOT NULL REFERENCES job_type,
+  epp_code             INT NOT NULL,
+  message_pattern      TEXT,
+  tld_id               UUID REFERENCES tld ON DELETE CASCADE,
+  accreditation_id     UUID REFERENCES accreditation ON DELETE CASCADE,
+  job_status_id        UUID NOT NULL REFERENCES job_status,
+  result_message       TEXT
+) INHERITS (class.audit_trail);
+
+CREATE INDEX IF NOT EXISTS epp_result_policy_job_type_id_epp_code_idx ON epp_result_policy(job_type_id, epp_code);
+
+COMMENT ON COLUMN epp_result_policy.message_pattern IS 'regular expression matched against the EPP result message';
+COMMENT ON COLUMN epp_result_policy.result_message IS 'customer facing message set on the job';
+
+CREATE OR REPLACE VIEW v_epp_result_policy AS
+SELECT
+    p.id,
+    jt.name AS job_type_name,
+    p.epp_code,
+    p.message_pattern,
+    t.name AS tld_name,
+    a.name AS accreditation_name,
+    js.name AS job_status_name,
+    p.result_message
+FROM epp_result_policy p
+    JOIN job_type jt ON jt.id = p.job_type_id
+    JOIN job_status js ON js.id = p.job_status_id
+    LEFT JOIN tld t ON t.id = p.tld_id
+    LEFT JOIN accreditation a ON a.id = p.accreditation_id;
+
+-- EPP result policies, see docs/error-msg-and-codes-mapping.md
+INSERT INTO epp_result_policy (job_type_id, epp_code, job_status_id, result_message) VALUES
+   (tc_id_from_name('job_type', 'provision_domain_create'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_renew'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_update'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_update'), 2102, tc_id_from_name('job_status', 'failed'), 'Unimplemented option'),
+   (tc_id_from_name('job_type', 'provision_domain_update'), 2302, tc_id_from_name('job_status', 'failed'), 'Host association already exists'),
+   (tc_id_from_name('job_type', 'provision_host_create'), 2302, tc_id_from_name('job_status', 'completed'), 'Host already exists'),
+   (tc_id_from_name('job_type', 'provision_host_delete'), 2303, tc_id_from_name('job_status', 'completed'), 'Host does not exist'),
+   (tc_id_from_name('job_type', 'provision_domain_delete_host'), 2303, tc_id_from_name('job_status', 'completed'), 'Host does not exist');
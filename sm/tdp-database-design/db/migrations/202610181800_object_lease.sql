@@ -0,0 +1,78 @@
+--
+-- table: object_lease
+-- description: this table serializes registry commands per object, a job holds the lease of the
+-- domain, contact or host it sends a command for until its response is handled
+--
+
+CREATE TABLE IF NOT EXISTS object_lease (
+  object_type           TEXT NOT NULL,
+  object_name           TEXT NOT NULL,
+  accreditation_name    TEXT NOT NULL,
+  job_id                UUID NOT NULL REFERENCES job ON DELETE CASCADE,
+  acquired_date         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  expiry_date           TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY(object_type, object_name, accreditation_name)
+);
+
+CREATE INDEX IF NOT EXISTS object_lease_job_id_idx ON object_lease(job_id);
+
+-- function: object_lease_acquire
+-- description: takes the lease of a registry object for a job. Returns zero interval if the lease
+-- was taken or is already held by the job, otherwise the time left until the lease held by another
+-- job expires.
+CREATE OR REPLACE FUNCTION object_lease_acquire(
+  _object_type TEXT,
+  _object_name TEXT,
+  _accreditation_name TEXT,
+  _job_id UUID,
+  _ttl INTERVAL
+) RETURNS INTERVAL AS $$
+DECLARE
+  _expiry_date TIMESTAMPTZ;
+BEGIN
+  INSERT INTO object_lease(object_type, object_name, accreditation_name, job_id, expiry_date)
+  VALUES (_object_type, _object_name, _accreditation_name, _job_id, NOW() + _ttl)
+  ON CONFLICT (object_type, object_name, accreditation_name) DO UPDATE
+  SET job_id = EXCLUDED.job_id,
+      acquired_date = NOW(),
+      expiry_date = EXCLUDED.expiry_date
+  WHERE object_lease.job_id = EXCLUDED.job_id
+     OR object_lease.expiry_date <= NOW();
+
+  IF FOUND THEN
+    RETURN INTERVAL '0';
+  END IF;
+
+  SELECT expiry_date INTO _expiry_date
+  FROM object_lease
+  WHERE object_type = _object_type
+    AND object_name = _object_name
+    AND accreditation_name = _accreditation_name;
+
+  -- the lease was released in the meantime, try again shortly
+  RETURN COALESCE(GREATEST(_expiry_date - NOW(), INTERVAL '0'), INTERVAL '1 second');
+END;
+$$ LANGUAGE plpgsql;
+
+--
+-- object_lease_release releases the object leases held by a job once it is no longer processing.
+--
+
+CREATE OR REPLACE FUNCTION object_lease_release() RETURNS TRIGGER AS
+$$
+BEGIN
+
+  DELETE FROM object_lease WHERE job_id = NEW.id;
+
+  RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS job_object_lease_release_tg ON job;
+CREATE TRIGGER job_object_lease_release_tg AFTER UPDATE ON job 
+       FOR EACH ROW WHEN (
+              OLD.status_id <> NEW.status_id
+              AND NEW.status_id <> tc_id_from_name('job_status','processing')
+       )
+       EXECUTE PROCEDURE object_lease_release();
@@ -0,0 +1,13 @@
+--
+-- job status: dispatching
+-- description: a worker claims a submitted job by moving it to dispatching before sending its command,
+-- claims left behind by a worker which stopped are put back to submitted by the job scheduler
+--
+
+INSERT INTO job_status(name,descr,is_final,is_success) VALUES
+('dispatching','Job is claimed by a worker sending its command',false,true)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE job ADD COLUMN IF NOT EXISTS claimed_date TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS job_claimed_date_idx ON job(claimed_date) WHERE claimed_date IS NOT NULL;
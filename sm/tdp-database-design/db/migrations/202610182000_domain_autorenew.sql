@@ -0,0 +1,21 @@
+--
+-- tld setting: autorenew_poll_enabled
+-- description: opts an accreditation in to auto-renew poll message handling, which moves the domain expiry
+-- forward, adds the autorenew_grace_period rgp status and notifies the tenant with a domain_autorenew event
+--
+
+INSERT INTO attr_key(name,
+                     category_id,
+                     descr,
+                     value_type_id,
+                     default_value,
+                     allow_null)
+VALUES ('autorenew_poll_enabled',
+        (SELECT id FROM attr_category WHERE name = 'lifecycle'),
+        'Registry auto-renew poll messages update domain expiry',
+        (SELECT id FROM attr_value_type WHERE name = 'BOOLEAN'),
+        FALSE::TEXT,
+        FALSE) ON CONFLICT DO NOTHING;
+
+INSERT INTO event_type (name, reference_table_name, description)
+VALUES ('domain_autorenew', 'domain', 'Domain auto-renew event') ON CONFLICT DO NOTHING;
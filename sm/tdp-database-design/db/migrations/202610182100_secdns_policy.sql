@@ -0,0 +1,24 @@
+--
+-- tld settings: secdns_algorithms, secdns_digest_types
+-- description: DNSSEC algorithms and DS digest types accepted by the registry, DNSSEC data is validated
+-- against them before domain create and update commands are sent
+--
+
+INSERT INTO attr_key(name,
+                     category_id,
+                     descr,
+                     value_type_id,
+                     default_value,
+                     allow_null)
+VALUES ('secdns_algorithms',
+        (SELECT id FROM attr_category WHERE name = 'dns'),
+        'List of DNSSEC algorithms accepted by the registry',
+        (SELECT id FROM attr_value_type WHERE name = 'INTEGER_LIST'),
+        '{8,10,13,14,15,16}'::TEXT,
+        FALSE),
+       ('secdns_digest_types',
+        (SELECT id FROM attr_category WHERE name = 'dns'),
+        'List of DS digest types accepted by the registry',
+        (SELECT id FROM attr_value_type WHERE name = 'INTEGER_LIST'),
+        '{2,4}'::TEXT,
+        FALSE) ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+--
+-- tld setting: extension_builders
+-- description: extension builders adding TLD specific extensions, built from the order attributes,
+-- to domain create and update commands
+--
+
+INSERT INTO attr_key(name,
+                     category_id,
+                     descr,
+                     value_type_id,
+                     default_value,
+                     allow_null)
+VALUES ('extension_builders',
+        (SELECT id FROM attr_category WHERE name = 'order'),
+        'List of extension builders adding TLD specific extensions to domain create and update commands',
+        (SELECT id FROM attr_value_type WHERE name = 'TEXT_LIST'),
+        '{}'::TEXT,
+        FALSE) ON CONFLICT DO NOTHING;
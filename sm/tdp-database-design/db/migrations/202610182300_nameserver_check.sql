@@ -0,0 +1,24 @@
+--
+-- tld settings: nameserver_check, nameserver_check_authoritative
+-- description: nameserver check run before domain create and update commands are sent, nameservers must
+-- resolve, in-zone nameservers must have glue addresses and, when required, answer authoritatively
+--
+
+INSERT INTO attr_key(name,
+                     category_id,
+                     descr,
+                     value_type_id,
+                     default_value,
+                     allow_null)
+VALUES ('nameserver_check',
+        (SELECT id FROM attr_category WHERE name = 'dns'),
+        'Nameserver check before domain create and update: off, warn or fail',
+        (SELECT id FROM attr_value_type WHERE name = 'TEXT'),
+        'off'::TEXT,
+        FALSE),
+       ('nameserver_check_authoritative',
+        (SELECT id FROM attr_category WHERE name = 'dns'),
+        'Nameserver check requires nameservers to answer authoritatively for the domain',
+        (SELECT id FROM attr_value_type WHERE name = 'BOOLEAN'),
+        FALSE::TEXT,
+        FALSE) ON CONFLICT DO NOTHING;
@@ -0,0 +1,800 @@
+ALTER TABLE IF EXISTS order_item_create_domain ADD COLUMN IF NOT EXISTS attributes JSONB;
+ALTER TABLE IF EXISTS order_item_update_domain ADD COLUMN IF NOT EXISTS attributes JSONB;
+ALTER TABLE IF EXISTS provision_domain ADD COLUMN IF NOT EXISTS attributes JSONB;
+ALTER TABLE IF EXISTS provision_domain_update ADD COLUMN IF NOT EXISTS attributes JSONB;
+
+COMMENT ON COLUMN order_item_create_domain.attributes IS
+'TLD specific attributes of the order as a flat object of string values, read by the
+extension builders listed in the tld.order.extension_builders TLD setting';
+
+COMMENT ON COLUMN order_item_update_domain.attributes IS
+'TLD specific attributes of the order as a flat object of string values, read by the
+extension builders listed in the tld.order.extension_builders TLD setting';
+
+DROP VIEW IF EXISTS v_order_create_domain;
+CREATE OR REPLACE VIEW v_order_create_domain AS
+SELECT 
+  cd.id AS order_item_id,
+  cd.order_id AS order_id,
+  cd.accreditation_tld_id,
+  o.metadata AS order_metadata,
+  o.tenant_customer_id,
+  o.type_id,
+  o.customer_user_id,
+  o.status_id,
+  s.name AS status_name,
+  s.descr AS status_descr,
+  tc.tenant_id,
+  tc.customer_id,
+  tc.tenant_name,
+  tc.name,
+  at.provider_name,
+  at.provider_instance_id,
+  at.provider_instance_name,
+  at.tld_id AS tld_id,
+  at.tld_name AS tld_name,
+  at.accreditation_id,
+  cd.name AS domain_name,
+  cd.registration_period AS registration_period,
+  cd.auto_renew,
+  cd.locks,
+  cd.launch_data,
+  cd.auth_info,
+  cd.secdns_max_sig_life,
+  cd.uname,
+  cd.language,
+  cd.created_date,
+  cd.updated_date,
+  cd.tags,
+  cd.metadata,
+  cd.attributes
+FROM order_item_create_domain cd
+  JOIN "order" o ON o.id=cd.order_id  
+  JOIN v_order_type ot ON ot.id = o.type_id
+  JOIN v_tenant_customer tc ON tc.id = o.tenant_customer_id
+  JOIN order_status s ON s.id = o.status_id
+  JOIN v_accreditation_tld at ON at.accreditation_tld_id = cd.accreditation_tld_id    
+;
+
+CREATE OR REPLACE VIEW v_order_update_domain AS
+SELECT
+    ud.id AS order_item_id,
+    ud.order_id AS order_id,
+    ud.accreditation_tld_id,
+    o.metadata AS order_metadata,
+    o.tenant_customer_id,
+    o.type_id,
+    o.customer_user_id,
+    o.status_id,
+    s.name AS status_name,
+    s.descr AS status_descr,
+    tc.tenant_id,
+    tc.customer_id,
+    tc.tenant_name,
+    tc.name,
+    at.provider_name,
+    at.provider_instance_id,
+    at.provider_instance_name,
+    at.tld_id AS tld_id,
+    at.tld_name AS tld_name,
+    at.accreditation_id,
+    d.name AS domain_name,
+    d.id AS domain_id,
+    ud.auth_info,
+    ud.auto_renew,
+    ud.locks,
+    ud.secdns_max_sig_life,
+    ud.attributes
+FROM order_item_update_domain ud
+     JOIN "order" o ON o.id=ud.order_id
+     JOIN v_order_type ot ON ot.id = o.type_id
+     JOIN v_tenant_customer tc ON tc.id = o.tenant_customer_id
+     JOIN order_status s ON s.id = o.status_id
+     JOIN v_accreditation_tld at ON at.accreditation_tld_id = ud.accreditation_tld_id
+     JOIN domain d ON d.tenant_customer_id=o.tenant_customer_id AND d.name=ud.name
+;
+
+-- function: plan_create_domain_provision_domain()
+-- description: create a domain based on the plan
+CREATE OR REPLACE FUNCTION plan_create_domain_provision_domain() RETURNS TRIGGER AS $$
+DECLARE
+    v_create_domain   RECORD;
+    v_pd_id           UUID;
+    v_parent_id       UUID;
+    v_locks_required_changes jsonb;
+    v_order_item_plan_ids UUID[];
+BEGIN
+    -- order information
+    SELECT * INTO v_create_domain
+    FROM v_order_create_domain
+    WHERE order_item_id = NEW.order_item_id;
+
+    WITH pd_ins AS (
+        INSERT INTO provision_domain(
+            domain_name,
+            registration_period,
+            accreditation_id,
+            accreditation_tld_id,
+            tenant_customer_id,
+            auto_renew,
+            secdns_max_sig_life,
+            uname,
+            language,
+            pw,
+            tags,
+            metadata,
+            launch_data,
+            order_metadata,
+            attributes
+        ) VALUES(
+            v_create_domain.domain_name,
+            v_create_domain.registration_period,
+            v_create_domain.accreditation_id,
+            v_create_domain.accreditation_tld_id,
+            v_create_domain.tenant_customer_id,
+            v_create_domain.auto_renew,
+            v_create_domain.secdns_max_sig_life,
+            v_create_domain.uname,
+            v_create_domain.language,
+            COALESCE(v_create_domain.auth_info, TC_GEN_PASSWORD(16)),
+            COALESCE(v_create_domain.tags,ARRAY[]::TEXT[]),
+            COALESCE(v_create_domain.metadata, '{}'::JSONB),
+            COALESCE(v_create_domain.launch_data, '{}'::JSONB),
+            v_create_domain.order_metadata,
+            v_create_domain.attributes
+        ) RETURNING id
+    )
+    SELECT id INTO v_pd_id FROM pd_ins;
+
+    SELECT
+        jsonb_object_agg(key, value)
+    INTO v_locks_required_changes FROM jsonb_each(v_create_domain.locks) WHERE value::BOOLEAN = TRUE;
+
+    IF NOT is_jsonb_empty_or_null(v_locks_required_changes) THEN
+        WITH inserted_domain_update AS (
+            INSERT INTO provision_domain_update(
+                domain_name,
+                accreditation_id,
+                accreditation_tld_id,
+                tenant_customer_id,
+                order_metadata,
+                order_item_plan_ids,
+                locks
+            ) VALUES (
+                v_create_domain.domain_name,
+                v_create_domain.accreditation_id,
+                v_create_domain.accreditation_tld_id,
+                v_create_domain.tenant_customer_id,
+                v_create_domain.order_metadata,
+                ARRAY[NEW.id],
+                v_locks_required_changes
+            ) RETURNING id
+        )
+        SELECT id INTO v_parent_id FROM inserted_domain_update;
+    ELSE
+        v_order_item_plan_ids := ARRAY [NEW.id];
+    END IF;
+
+    -- insert contacts
+    INSERT INTO provision_domain_contact(
+        provision_domain_id,
+        contact_id,
+        contact_type_id
+    ) (
+        SELECT
+            v_pd_id,
+            order_contact_id,
+            domain_contact_type_id
+        FROM create_domain_contact
+        WHERE create_domain_id = NEW.order_item_id
+        AND is_contact_type_supported_for_tld(domain_contact_type_id, v_create_domain.accreditation_tld_id)
+    );
+
+    -- insert hosts
+    INSERT INTO provision_domain_host(
+        provision_domain_id,
+        host_id
+    ) (
+        SELECT
+            v_pd_id,
+            h.id
+        FROM ONLY host h
+                 JOIN order_host oh ON oh.name = h.name
+                 JOIN create_domain_nameserver cdn ON cdn.host_id = oh.id
+        WHERE cdn.create_domain_id = NEW.order_item_id AND oh.tenant_customer_id = h.tenant_customer_id
+    );
+
+    -- insert secdns
+    INSERT INTO provision_domain_secdns(
+        provision_domain_id,
+        secdns_id
+    ) (
+        SELECT
+            v_pd_id,
+            cds.id 
+        FROM create_domain_secdns cds
+        WHERE cds.create_domain_id = NEW.order_item_id
+    );
+
+    UPDATE provision_domain
+    SET is_complete = TRUE, order_item_plan_ids = v_order_item_plan_ids, parent_id = v_parent_id
+    WHERE id = v_pd_id;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- function: plan_update_domain_provision_domain()
+-- description: update a domain based on the plan
+CREATE OR REPLACE FUNCTION plan_update_domain_provision_domain() RETURNS TRIGGER AS $$
+DECLARE
+    v_update_domain             RECORD;
+    v_pdu_id                     UUID;
+BEGIN
+    -- order information
+    SELECT * INTO v_update_domain
+    FROM v_order_update_domain
+    WHERE order_item_id = NEW.order_item_id;
+
+    -- we now signal the provisioning
+    WITH pdu_ins AS (
+        INSERT INTO provision_domain_update(
+            domain_id,
+            domain_name,
+            auth_info,
+            accreditation_id,
+            accreditation_tld_id,
+            tenant_customer_id,
+            auto_renew,
+            order_metadata,
+            order_item_plan_ids,
+            locks,
+            secdns_max_sig_life,
+            attributes
+        ) VALUES(
+            v_update_domain.domain_id,
+            v_update_domain.domain_name,
+            v_update_domain.auth_info,
+            v_update_domain.accreditation_id,
+            v_update_domain.accreditation_tld_id,
+            v_update_domain.tenant_customer_id,
+            v_update_domain.auto_renew,
+            v_update_domain.order_metadata,
+            ARRAY[NEW.id],
+            v_update_domain.locks,
+            v_update_domain.secdns_max_sig_life,
+            v_update_domain.attributes
+        ) RETURNING id
+    )
+    SELECT id INTO v_pdu_id FROM pdu_ins;
+
+    -- insert contacts
+    INSERT INTO provision_domain_update_contact(
+        provision_domain_update_id,
+        contact_id,
+        contact_type_id
+    )(
+        SELECT
+            v_pdu_id,
+            order_contact_id,
+            domain_contact_type_id
+        FROM update_domain_contact
+        WHERE update_domain_id = NEW.order_item_id
+    );
+
+    INSERT INTO provision_domain_update_add_contact(
+        provision_domain_update_id,
+        contact_id,
+        contact_type_id
+    )(
+        SELECT
+            v_pdu_id,
+            order_contact_id,
+            domain_contact_type_id
+        FROM update_domain_add_contact
+        WHERE update_domain_id = NEW.order_item_id
+    );
+
+    INSERT INTO provision_domain_update_rem_contact(
+        provision_domain_update_id,
+        contact_id,
+        contact_type_id
+    )(
+        SELECT
+            v_pdu_id,
+            order_contact_id,
+            domain_contact_type_id
+        FROM update_domain_rem_contact
+        WHERE update_domain_id = NEW.order_item_id
+    );
+
+    -- insert hosts to add
+    INSERT INTO provision_domain_update_add_host(
+        provision_domain_update_id,
+        host_id
+    ) (
+        SELECT
+            v_pdu_id,
+            h.id
+        FROM ONLY host h
+            JOIN order_host oh ON oh.name = h.name
+            JOIN update_domain_add_nameserver udan ON udan.host_id = oh.id
+        WHERE udan.update_domain_id = NEW.order_item_id AND oh.tenant_customer_id = h.tenant_customer_id
+    );
+
+    -- insert hosts to remove
+    INSERT INTO provision_domain_update_rem_host(
+        provision_domain_update_id,
+        host_id
+    ) (
+        SELECT
+            v_pdu_id,
+            h.id
+        FROM ONLY host h
+            JOIN order_host oh ON oh.name = h.name
+            JOIN update_domain_rem_nameserver udrn ON udrn.host_id = oh.id
+            JOIN domain_host dh ON dh.host_id = h.id
+        WHERE udrn.update_domain_id = NEW.order_item_id 
+            AND oh.tenant_customer_id = h.tenant_customer_id
+            -- make sure host to be removed is associated with domain
+            AND dh.domain_id = v_update_domain.domain_id
+    );
+
+    -- insert secdns to add
+    INSERT INTO provision_domain_update_add_secdns (
+        provision_domain_update_id,
+        secdns_id
+    )(
+        SELECT
+            v_pdu_id,
+            id
+        FROM update_domain_add_secdns
+        WHERE update_domain_id = NEW.order_item_id
+    );
+
+    -- insert hosts to remove
+    INSERT INTO provision_domain_update_rem_secdns (
+        provision_domain_update_id,
+        secdns_id
+    )(
+        SELECT
+            v_pdu_id,
+            id
+        FROM update_domain_rem_secdns
+        WHERE update_domain_id = NEW.order_item_id
+    );
+
+    UPDATE provision_domain_update SET is_complete = TRUE WHERE id = v_pdu_id;
+    
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- function: provision_domain_job()
+-- description: creates the job to create the domain
+CREATE OR REPLACE FUNCTION provision_domain_job() RETURNS TRIGGER AS $$
+DECLARE
+    v_domain     RECORD;
+    _start_date  TIMESTAMPTZ;
+BEGIN
+    WITH 
+        contacts AS (
+            SELECT JSONB_AGG(
+                        JSONB_BUILD_OBJECT(
+                                'type',ct.name,
+                                'handle',pc.handle
+                        )
+                ) AS data
+            FROM provision_domain pd
+                    JOIN provision_domain_contact pdc
+                        ON pdc.provision_domain_id=pd.id
+                    JOIN domain_contact_type ct ON ct.id=pdc.contact_type_id
+                    JOIN provision_contact pc ON pc.contact_id = pdc.contact_id
+                    JOIN provision_status ps ON ps.id = pc.status_id
+            WHERE
+                ps.is_success AND ps.is_final AND pd.accreditation_id = pc.accreditation_id
+            AND pd.id = NEW.id
+        ),
+        hosts AS (
+            SELECT JSONB_AGG(data) AS data
+            FROM
+                (SELECT JSONB_BUILD_OBJECT(
+                                'name',
+                                h.name,
+                                'ip_addresses',
+                                COALESCE(jsonb_agg(ha.address) FILTER (WHERE ha.host_id IS NOT NULL), '[]')
+                        ) as data
+                FROM provision_domain pd
+                        JOIN provision_domain_host pdh ON pdh.provision_domain_id=pd.id
+                        JOIN ONLY host h ON h.id = pdh.host_id
+                        -- addresses might be omitted if customer is not authoritative
+                        -- or host already existed at registry
+                        LEFT JOIN ONLY host_addr ha on ha.host_id = h.id 
+                WHERE pd.id=NEW.id
+                GROUP BY h.name) sub_q
+        ),
+        price AS (
+            SELECT
+                CASE
+                    WHEN voip.price IS NULL THEN NULL
+                    ELSE JSONB_BUILD_OBJECT(
+                        'amount', voip.price,
+                        'currency', voip.currency_type_code,
+                        'fraction', voip.currency_type_fraction
+                    )
+                END AS data
+            FROM v_order_item_price voip
+                    JOIN v_order_create_domain vocd ON voip.order_item_id = vocd.order_item_id AND voip.order_id = vocd.order_id
+            WHERE vocd.domain_name = NEW.domain_name
+            ORDER BY vocd.created_date DESC
+            LIMIT 1
+        ),
+        secdns AS (
+            SELECT
+                pd.secdns_max_sig_life as max_sig_life,
+                JSONB_AGG(
+                    JSONB_BUILD_OBJECT(
+                        'key_tag', osdd.key_tag,
+                        'algorithm', osdd.algorithm,
+                        'digest_type', osdd.digest_type,
+                        'digest', osdd.digest,
+                        'key_data',
+                        CASE
+                            WHEN osdd.key_data_id IS NOT NULL THEN
+                                JSONB_BUILD_OBJECT(
+                                    'flags', oskd2.flags,
+                                    'protocol', oskd2.protocol,
+                                    'algorithm', oskd2.algorithm,
+                                    'public_key', oskd2.public_key
+                                )
+                        END
+                    )
+                ) FILTER (WHERE cds.ds_data_id IS NOT NULL) AS ds_data,
+                JSONB_AGG(
+                	JSONB_BUILD_OBJECT(
+                    	'flags', oskd1.flags,
+                   		'protocol', oskd1.protocol,
+                    	'algorithm', oskd1.algorithm,
+                    	'public_key', oskd1.public_key
+                 	)
+            	) FILTER (WHERE cds.key_data_id IS NOT NULL) AS key_data
+            FROM provision_domain pd
+                JOIN provision_domain_secdns pds ON pds.provision_domain_id = pd.id
+                JOIN create_domain_secdns cds ON cds.id = pds.secdns_id
+                LEFT JOIN order_secdns_ds_data osdd ON osdd.id = cds.ds_data_id
+                LEFT JOIN order_secdns_key_data oskd1 ON oskd1.id = cds.key_data_id
+                LEFT JOIN order_secdns_key_data oskd2 ON oskd2.id = osdd.key_data_id
+            WHERE pd.id = NEW.id
+            GROUP BY pd.id, cds.create_domain_id
+        )
+    SELECT
+        NEW.id AS provision_contact_id,
+        tnc.id AS tenant_customer_id,
+        d.domain_name AS name,
+        d.registration_period,
+        d.pw AS pw,
+        contacts.data AS contacts,
+        hosts.data AS nameservers,
+        price.data AS price,
+        CASE
+            WHEN d.uname IS NULL AND d.language IS NULL
+            THEN NULL
+            ELSE jsonb_build_object('uname', d.uname, 'language', d.language)
+        END AS idn,
+        TO_JSONB(secdns.*) AS secdns,
+        TO_JSONB(a.*) AS accreditation,
+        TO_JSONB(vat.*) AS accreditation_tld,
+        d.launch_data AS launch_data,
+        d.attributes AS attributes,
+        d.order_metadata AS metadata
+    INTO v_domain
+    FROM provision_domain d
+             JOIN contacts ON TRUE
+             JOIN hosts ON TRUE
+             LEFT JOIN price ON TRUE
+             LEFT JOIN secdns ON TRUE
+             JOIN v_accreditation a ON a.accreditation_id = NEW.accreditation_id
+             JOIN v_accreditation_tld vat ON vat.accreditation_tld_id = d.accreditation_tld_id
+             JOIN tenant_customer tnc ON tnc.tenant_id = a.tenant_id
+    WHERE d.id = NEW.id;
+
+    _start_date := job_start_date(NEW.attempt_count);
+
+    UPDATE provision_domain SET job_id = job_submit(
+            v_domain.tenant_customer_id,
+            'provision_domain_create',
+            NEW.id,
+            TO_JSONB(v_domain.*),
+            NULL,
+            _start_date
+    ) WHERE id=NEW.id;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- function: provision_domain_update_job()
+-- description: creates the job to update the domain.
+CREATE OR REPLACE FUNCTION provision_domain_update_job() RETURNS TRIGGER AS $$
+DECLARE
+    v_domain     RECORD;
+    _parent_job_id      UUID;
+    v_locks_required_changes JSONB;
+BEGIN
+    WITH contacts AS(
+        SELECT JSONB_AGG(
+            JSONB_BUILD_OBJECT(
+                    'type', ct.name,
+                    'handle', pc.handle
+            )
+        ) AS data
+        FROM provision_domain_update_contact pdc
+            JOIN domain_contact_type ct ON ct.id = pdc.contact_type_id
+            JOIN provision_contact pc ON pc.contact_id = pdc.contact_id
+            JOIN provision_status ps ON ps.id = pc.status_id
+        WHERE
+            ps.is_success AND ps.is_final AND pc.accreditation_id = NEW.accreditation_id
+            AND pdc.provision_domain_update_id = NEW.id
+    ), contacts_add AS(
+        SELECT JSONB_AGG(data) AS add
+        FROM (
+            SELECT
+                JSON_BUILD_OBJECT(
+                    'type', ct.name,
+                    'handle', pc.handle
+                ) AS data
+            FROM provision_domain_update_add_contact pduac
+                JOIN domain_contact_type ct ON ct.id = pduac.contact_type_id
+                JOIN provision_contact pc ON pc.contact_id = pduac.contact_id
+                JOIN provision_status ps ON ps.id = pc.status_id
+            WHERE
+                ps.is_success AND ps.is_final AND pc.accreditation_id = NEW.accreditation_id
+                AND pduac.provision_domain_update_id = NEW.id
+        ) sub_q
+    ), contacts_rem AS(
+        SELECT JSONB_AGG(data) AS rem
+        FROM (
+            SELECT
+                JSON_BUILD_OBJECT(
+                    'type', ct.name,
+                    'handle', dc.handle
+                ) AS data
+            FROM provision_domain_update_rem_contact pdurc
+                 JOIN provision_domain_update pdu ON pdu.id = pdurc.provision_domain_update_id
+                 JOIN domain_contact dc on dc.domain_id = pdu.domain_id
+                    AND dc.domain_contact_type_id = pdurc.contact_type_id
+                    AND dc.contact_id = pdurc.contact_id
+                 JOIN domain_contact_type ct ON ct.id = pdurc.contact_type_id
+            WHERE pdurc.provision_domain_update_id = NEW.id
+        ) sub_q
+    ),hosts_add AS(
+        SELECT JSONB_AGG(data) AS add
+        FROM (
+            SELECT
+                JSON_BUILD_OBJECT(
+                    'name', h.name,
+                    'ip_addresses', JSONB_AGG(ha.address)
+                ) AS data
+            FROM provision_domain_update_add_host pduah
+                JOIN ONLY host h ON h.id = pduah.host_id
+                LEFT JOIN ONLY host_addr ha ON h.id = ha.host_id
+            WHERE pduah.provision_domain_update_id = NEW.id
+            GROUP BY h.name
+        ) sub_q
+    ), hosts_rem AS(
+        SELECT  JSONB_AGG(data) AS rem
+        FROM (
+            SELECT
+                JSON_BUILD_OBJECT(
+                    'name', h.name,
+                    'ip_addresses', JSONB_AGG(ha.address)
+                ) AS data
+            FROM provision_domain_update_rem_host pdurh
+                JOIN ONLY host h ON h.id = pdurh.host_id
+                LEFT JOIN ONLY host_addr ha ON h.id = ha.host_id
+            WHERE pdurh.provision_domain_update_id = NEW.id
+            GROUP BY h.name
+        ) sub_q
+    ), secdns_add AS(
+        SELECT
+            JSONB_AGG(
+                JSONB_BUILD_OBJECT(
+                    'key_tag', osdd.key_tag,
+                    'algorithm', osdd.algorithm,
+                    'digest_type', osdd.digest_type,
+                    'digest', osdd.digest,
+                    'key_data',
+                    CASE
+                        WHEN osdd.key_data_id IS NOT NULL THEN
+                            JSONB_BUILD_OBJECT(
+                                'flags', oskd2.flags,
+                                'protocol', oskd2.protocol,
+                                'algorithm', oskd2.algorithm,
+                                'public_key', oskd2.public_key
+                            )
+                    END
+                )
+            ) FILTER (WHERE udas.ds_data_id IS NOT NULL) AS ds_data,
+            JSONB_AGG(
+                JSONB_BUILD_OBJECT(
+                    'flags', oskd1.flags,
+                    'protocol', oskd1.protocol,
+                    'algorithm', oskd1.algorithm,
+                    'public_key', oskd1.public_key
+                )
+            ) FILTER (WHERE udas.key_data_id IS NOT NULL) AS key_data
+        FROM provision_domain_update_add_secdns pduas
+            LEFT JOIN update_domain_add_secdns udas ON udas.id = pduas.secdns_id
+            LEFT JOIN order_secdns_ds_data osdd ON osdd.id = udas.ds_data_id
+            LEFT JOIN order_secdns_key_data oskd1 ON oskd1.id = udas.key_data_id
+            LEFT JOIN order_secdns_key_data oskd2 ON oskd2.id = osdd.key_data_id
+
+        WHERE pduas.provision_domain_update_id = NEW.id
+        GROUP BY pduas.provision_domain_update_id
+    ), secdns_rem AS(
+        SELECT
+            JSONB_AGG(
+                JSONB_BUILD_OBJECT(
+                    'key_tag', osdd.key_tag,
+                    'algorithm', osdd.algorithm,
+                    'digest_type', osdd.digest_type,
+                    'digest', osdd.digest,
+                    'key_data',
+                    CASE
+                        WHEN osdd.key_data_id IS NOT NULL THEN
+                            JSONB_BUILD_OBJECT(
+                                'flags', oskd2.flags,
+                                'protocol', oskd2.protocol,
+                                'algorithm', oskd2.algorithm,
+                                'public_key', oskd2.public_key
+                            )
+                    END
+                )
+            ) FILTER (WHERE udrs.ds_data_id IS NOT NULL) AS ds_data,
+            JSONB_AGG(
+                JSONB_BUILD_OBJECT(
+                    'flags', oskd1.flags,
+                    'protocol', oskd1.protocol,
+                    'algorithm', oskd1.algorithm,
+                    'public_key', oskd1.public_key
+                )
+            ) FILTER (WHERE udrs.key_data_id IS NOT NULL) AS key_data
+        FROM provision_domain_update_rem_secdns pdurs
+            LEFT JOIN update_domain_rem_secdns udrs ON udrs.id = pdurs.secdns_id
+            LEFT JOIN order_secdns_ds_data osdd ON osdd.id = udrs.ds_data_id
+            LEFT JOIN order_secdns_key_data oskd1 ON oskd1.id = udrs.key_data_id
+            LEFT JOIN order_secdns_key_data oskd2 ON oskd2.id = osdd.key_data_id
+
+        WHERE pdurs.provision_domain_update_id = NEW.id
+        GROUP BY pdurs.provision_domain_update_id
+    )
+    SELECT
+        NEW.id AS provision_domain_update_id,
+        tnc.id AS tenant_customer_id,
+        d.order_metadata,
+        d.domain_name AS name,
+        d.auth_info AS pw,
+        coalesce(contacts.data, TO_JSONB(contacts_add) || TO_JSONB(contacts_rem))AS contacts,
+        TO_JSONB(hosts_add) || TO_JSONB(hosts_rem) AS nameservers,
+        JSONB_BUILD_OBJECT(
+            'max_sig_life', d.secdns_max_sig_life,
+            'add', TO_JSONB(secdns_add),
+            'rem', TO_JSONB(secdns_rem)
+        ) as secdns,
+        TO_JSONB(a.*) AS accreditation,
+        TO_JSONB(vat.*) AS accreditation_tld,
+        d.attributes AS attributes,
+        d.order_metadata AS metadata,
+        (lock_attrs.lock_support->>'tld.order.is_rem_update_lock_with_domain_content_supported')::boolean AS is_rem_update_lock_with_domain_content_supported,
+        (lock_attrs.lock_support->>'tld.order.is_add_update_lock_with_domain_content_supported')::boolean AS is_add_update_lock_with_domain_content_supported
+    INTO v_domain
+    FROM provision_domain_update d
+        LEFT JOIN contacts ON TRUE
+        LEFT JOIN contacts_add ON TRUE
+        LEFT JOIN contacts_rem ON TRUE
+        LEFT JOIN hosts_add ON TRUE
+        LEFT JOIN hosts_rem ON TRUE
+        LEFT JOIN secdns_add ON TRUE
+        LEFT JOIN secdns_rem ON TRUE
+        JOIN v_accreditation a ON a.accreditation_id = NEW.accreditation_id
+        JOIN v_accreditation_tld vat ON vat.accreditation_tld_id = d.accreditation_tld_id
+        JOIN tenant_customer tnc ON tnc.tenant_id = a.tenant_id
+        JOIN LATERAL (
+        SELECT jsonb_object_agg(key, value) AS lock_support
+        FROM v_attribute va
+        WHERE va.accreditation_tld_id = d.accreditation_tld_id
+          AND va.key IN (
+             'tld.order.is_rem_update_lock_with_domain_content_supported',
+             'tld.order.is_add_update_lock_with_domain_content_supported'
+            )
+        ) lock_attrs ON true
+    WHERE d.id = NEW.id;
+
+    -- Retrieves the required changes for domain locks based on the provided lock configuration.
+    SELECT
+        JSONB_OBJECT_AGG(
+                l.key, l.value::BOOLEAN
+        )
+    INTO v_locks_required_changes
+    FROM JSONB_EACH(NEW.locks) l
+             LEFT JOIN v_domain_lock vdl ON vdl.name = l.key AND vdl.domain_id = NEW.domain_id AND NOT vdl.is_internal
+    WHERE (NOT l.value::boolean AND vdl.id IS NOT NULL) OR (l.value::BOOLEAN AND vdl.id IS NULL);
+
+    -- If there are required changes for the 'update' lock AND there are other changes to the domain, THEN we MAY need to
+    -- create two separate jobs: One job for the 'update' lock and Another job for all other domain changes, Because if
+    -- the only change we have is 'update' lock, we can do it in a single job
+    IF (v_locks_required_changes ? 'update') AND
+       (COALESCE(v_domain.contacts,v_domain.nameservers,v_domain.pw::JSONB)  IS NOT NULL
+           OR NOT is_jsonb_empty_or_null(v_locks_required_changes - 'update'))
+    THEN
+        -- If 'update' lock has false value (remove the lock) and the registry "DOES NOT" support removing that lock with
+        -- the other domain changes in a single command, then we need to create two jobs: the first one to remove the
+        -- domain lock, and the second one to handle the other domain changes
+        IF (v_locks_required_changes->'update')::BOOLEAN IS FALSE AND
+           NOT v_domain.is_rem_update_lock_with_domain_content_supported THEN
+            -- all the changes without the update lock removal, because first we need to remove the lock on update
+            SELECT job_create(
+                           v_domain.tenant_customer_id,
+                           'provision_domain_update',
+                           NEW.id,
+                           TO_JSONB(v_domain.*) || jsonb_build_object('locks',v_locks_required_changes - 'update')
+                   ) INTO _parent_job_id;
+
+            -- Update provision_domain_update table with parent job id
+            UPDATE provision_domain_update SET job_id = _parent_job_id  WHERE id=NEW.id;
+
+            -- first remove the update lock so we can do the other changes
+            PERFORM job_submit(
+                    v_domain.tenant_customer_id,
+                    'provision_domain_update',
+                    NULL,
+                    jsonb_build_object('locks', jsonb_build_object('update', FALSE),
+                                       'name',v_domain.name,
+                                       'accreditation',v_domain.accreditation,
+                                       'accreditation_tld', v_domain.accreditation_tld),
+                    _parent_job_id
+                    );
+            RETURN NEW; -- RETURN
+
+        -- Same thing here, if 'update' lock has true value (add the lock) and the registry DOES NOT support adding that
+        -- lock with the other domain changes in a single command, then we need to create two jobs: the first one to
+        -- handle the other domain changes and the second one to add the domain lock
+
+        elsif (v_locks_required_changes->'update')::BOOLEAN IS TRUE AND
+              NOT v_domain.is_add_update_lock_with_domain_content_supported THEN
+            -- here we want to add the lock on update (we will do the changes first then add the lock)
+            SELECT job_create(
+                           v_domain.tenant_customer_id,
+                           'provision_domain_update',
+                           NEW.id,
+                           jsonb_build_object('locks', jsonb_build_object('update', TRUE),
+                                              'name',v_domain.name,
+                                              'accreditation',v_domain.accreditation)
+                   ) INTO _parent_job_id;
+
+            -- Update provision_domain_update table with parent job id
+            UPDATE provision_domain_update SET job_id = _parent_job_id  WHERE id=NEW.id;
+
+            -- Submit child job for all the changes other than domain update lock
+            PERFORM job_submit(
+                    v_domain.tenant_customer_id,
+                    'provision_domain_update',
+                    NULL,
+                    TO_JSONB(v_domain.*) || jsonb_build_object('locks',v_locks_required_changes - 'update'),
+                    _parent_job_id
+                    );
+
+            RETURN NEW; -- RETURN
+        end if;
+    end if;
+    UPDATE provision_domain_update SET
+        job_id = job_submit(
+                v_domain.tenant_customer_id,
+                'provision_domain_update',
+                NEW.id,
+                TO_JSONB(v_domain.*) || jsonb_build_object('locks',v_locks_required_changes)
+                 ) WHERE id=NEW.id;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
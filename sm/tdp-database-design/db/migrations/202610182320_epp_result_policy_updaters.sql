@@ -0,0 +1,7 @@
+-- EPP result policies of the domain delete and transfer handlers, see docs/error-msg-and-codes-mapping.md
+INSERT INTO epp_result_policy (job_type_id, epp_code, job_status_id, result_message) VALUES
+   (tc_id_from_name('job_type', 'provision_domain_delete'), 2303, tc_id_from_name('job_status', 'completed'), NULL),
+   (tc_id_from_name('job_type', 'setup_domain_delete'), 2303, tc_id_from_name('job_status', 'completed'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_transfer_in_request'), 1001, tc_id_from_name('job_status', 'completed_conditionally'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_transfer_away'), 1001, tc_id_from_name('job_status', 'failed'), NULL),
+   (tc_id_from_name('job_type', 'provision_domain_transfer_in_cancel_request'), 1001, tc_id_from_name('job_status', 'failed'), NULL);
@@ -0,0 +1,82 @@
+--
+-- job_prevent_if_final lets a failed job be submitted again by job_requeue, as when it is requeued
+-- through the job scheduler admin API; every other update of a final job still raises.
+--
+
+CREATE OR REPLACE FUNCTION job_prevent_if_final() RETURNS TRIGGER AS
+$$
+DECLARE 
+  v_job_status RECORD;
+BEGIN
+
+  SELECT * INTO v_job_status FROM job_status WHERE id=OLD.status_id;
+
+  IF v_job_status.id = tc_id_from_name('job_status','failed')
+    AND NEW.status_id = tc_id_from_name('job_status','submitted')
+    AND current_setting('job.requeue', TRUE) = 'on' THEN
+    NEW.end_date = NULL;
+    RETURN NEW;
+  END IF;
+
+  IF v_job_status.is_final THEN 
+    RAISE EXCEPTION 'cannot modify job (%), has status: %',NEW.id,v_job_status.name;
+  END IF;
+
+  RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+--
+-- job_requeue submits a failed job again to be dispatched now. Returns false if the job is not failed,
+-- or if its parent job or the status of its reference row is final: the order already moved on and
+-- the outcome of the new attempt could not be applied to them.
+--
+
+CREATE OR REPLACE FUNCTION job_requeue(_job_id UUID) RETURNS BOOLEAN AS $$
+DECLARE
+  _job                RECORD;
+  _job_type           RECORD;
+  _reference_final    BOOLEAN;
+BEGIN
+
+  SELECT * INTO _job FROM job WHERE id = _job_id AND status_id = tc_id_from_name('job_status','failed');
+  IF NOT FOUND THEN
+    RETURN FALSE;
+  END IF;
+
+  PERFORM TRUE FROM v_job WHERE job_id = _job.parent_id AND job_status_is_final;
+  IF FOUND THEN
+    RETURN FALSE;
+  END IF;
+
+  SELECT * INTO _job_type FROM job_type WHERE id = _job.type_id;
+
+  IF _job_type.reference_table IS NOT NULL THEN
+    EXECUTE FORMAT(
+      'SELECT rst.is_final FROM "%s" r JOIN %s rst ON rst.id = r.%s WHERE r.id = $1',
+      _job_type.reference_table,
+      _job_type.reference_status_table,
+      _job_type.reference_status_column
+    )
+      INTO _reference_final
+      USING _job.reference_id;
+
+    IF _reference_final THEN
+      RETURN FALSE;
+    END IF;
+  END IF;
+
+  -- job_prevent_if_final only lets a failed job through while this transaction local flag is on
+  PERFORM set_config('job.requeue', 'on', TRUE);
+
+  UPDATE job SET
+    status_id = tc_id_from_name('job_status','submitted'),
+    start_date = NOW()
+  WHERE id = _job_id;
+
+  PERFORM set_config('job.requeue', 'off', TRUE);
+
+  RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
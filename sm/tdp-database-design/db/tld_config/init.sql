@@ -103,6 +103,38 @@ VALUES
   '[0, 0]'::TEXT,
   FALSE
 ),
+(
+  'secdns_algorithms',
+  tc_id_from_name('attr_category', 'dns'),
+  'List of DNSSEC algorithms accepted by the registry',
+  tc_id_from_name('attr_value_type', 'INTEGER_LIST'),
+  '{8,10,13,14,15,16}'::TEXT,
+  FALSE
+),
+(
+  'secdns_digest_types',
+  tc_id_from_name('attr_category', 'dns'),
+  'List of DS digest types accepted by the registry',
+  tc_id_from_name('attr_value_type', 'INTEGER_LIST'),
+  '{2,4}'::TEXT,
+  FALSE
+),
+(
+  'nameserver_check',
+  tc_id_from_name('attr_category', 'dns'),
+  'Nameserver check before domain create and update: off, warn or fail',
+  tc_id_from_name('attr_value_type', 'TEXT'),
+  'off'::TEXT,
+  FALSE
+),
+(
+  'nameserver_check_authoritative',
+  tc_id_from_name('attr_category', 'dns'),
+  'Nameserver check requires nameservers to answer authoritatively for the domain',
+  tc_id_from_name('attr_value_type', 'BOOLEAN'),
+  FALSE::TEXT,
+  FALSE
+),
 
 -- finance category
 (
@@ -293,6 +325,14 @@ VALUES
   TRUE::TEXT,
   FALSE
 ),
+(
+  'autorenew_poll_enabled',
+  tc_id_from_name('attr_category', 'lifecycle'),
+  'Registry auto-renew poll messages update domain expiry',
+  tc_id_from_name('attr_value_type', 'BOOLEAN'),
+  FALSE::TEXT,
+  FALSE
+),
 (
   'is_redeem_report_required',
   tc_id_from_name('attr_category', 'lifecycle'),
@@ -430,6 +470,14 @@ VALUES
   '{}'::TEXT,
   FALSE
 ),
+(
+  'extension_builders',
+  tc_id_from_name('attr_category', 'order'),
+  'List of extension builders adding TLD specific extensions to domain create and update commands',
+  tc_id_from_name('attr_value_type', 'TEXT_LIST'),
+  '{}'::TEXT,
+  FALSE
+),
 (
     'rdp_enabled',
     tc_id_from_name('attr_category', 'order'),